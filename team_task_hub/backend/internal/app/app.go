@@ -7,7 +7,9 @@ import (
 	"team_task_hub/backend/internal/middleware"
 	"team_task_hub/backend/internal/repositories"
 	"team_task_hub/backend/internal/router"
+	"team_task_hub/backend/internal/scheduler"
 	"team_task_hub/backend/internal/services"
+	"time"
 
 	_ "team_task_hub/backend/docs"
 
@@ -21,8 +23,9 @@ import (
 
 // App 应用主体
 type App struct {
-	router *gin.Engine
-	config *config.Config
+	router    *gin.Engine
+	config    *config.Config
+	scheduler *scheduler.Scheduler
 }
 
 // New 创建应用实例
@@ -51,10 +54,61 @@ func New() *App {
 	// 设置路由
 	setupRoutes(r, db, cfg)
 
+	// 启动后台定时任务
+	sched := startScheduler(db, cfg)
+
 	return &App{
-		router: r,
-		config: cfg,
+		router:    r,
+		config:    cfg,
+		scheduler: sched,
+	}
+}
+
+// startScheduler 启动后台定时任务（重复待办续期、逾期标记、回收站清理、提醒投递）
+func startScheduler(db *gorm.DB, cfg *config.Config) *scheduler.Scheduler {
+	if !cfg.SchedulerEnabled {
+		log.Println("后台定时任务已禁用")
+		return nil
 	}
+	// 多副本部署依赖Redis锁避免任务重复执行，Redis不可用时宁可不启动
+	if cache.Client == nil && !cfg.SchedulerSingleInstance {
+		log.Println("警告: Redis不可用且配置为多实例部署(SCHEDULER_SINGLE_INSTANCE=false)，后台定时任务未启动！" +
+			"重复待办续期、逾期标记、回收站清理、提醒投递和自动排程都不会执行")
+		return nil
+	}
+
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	todoService := router.NewTodoService(db)
+
+	// 提醒投递渠道：邮件、站内通知
+	reminderService := services.NewReminderService(
+		repositories.NewReminderRepository(db),
+		repositories.NewTodoRepository(db),
+		repositories.NewActivityRepository(db),
+		repositories.NewActivityParticipationRepository(db),
+		repositories.NewUserRepository(db),
+	)
+	emailService := services.NewEmailService(newEmailConfig(cfg), repositories.NewVerificationCodeRepository(db))
+	reminderService.RegisterChannel(services.NewEmailReminderChannel(emailService))
+	reminderService.RegisterChannel(services.NewInAppReminderChannel(notificationService))
+
+	sched := scheduler.New(cfg.SchedulerSingleInstance)
+	scheduler.RegisterTodoJobs(sched, todoService,
+		time.Duration(cfg.RenewIntervalMinutes)*time.Minute,
+		time.Duration(cfg.OverdueIntervalMinutes)*time.Minute,
+	)
+	scheduler.RegisterTrashJobs(sched, todoService,
+		time.Duration(cfg.PurgeIntervalMinutes)*time.Minute,
+		time.Duration(cfg.TrashRetentionDays)*24*time.Hour,
+	)
+	scheduler.RegisterAutoScheduleJobs(sched, todoService,
+		time.Duration(cfg.AutoScheduleIntervalMinutes)*time.Minute,
+	)
+	scheduler.RegisterReminderJobs(sched, reminderService,
+		time.Duration(cfg.ReminderIntervalSeconds)*time.Second,
+	)
+	sched.Start()
+	return sched
 }
 
 // initDatabase 初始化数据库
@@ -74,15 +128,20 @@ func setGinMode(cfg *config.Config) {
 	}
 }
 
-// setupRoutes 设置路由
-func setupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
-	// 创建邮箱配置
-	emailConfig := &services.EmailConfig{
+// newEmailConfig 根据应用配置创建邮箱配置
+func newEmailConfig(cfg *config.Config) *services.EmailConfig {
+	return &services.EmailConfig{
 		Host:     cfg.SMTPHost,
 		Port:     cfg.SMTPPort,
 		User:     cfg.SMTPUser,
 		Password: cfg.SMTPPassword,
 	}
+}
+
+// setupRoutes 设置路由
+func setupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	// 创建邮箱配置
+	emailConfig := newEmailConfig(cfg)
 
 	// 设置路由
 	//初始化结构
@@ -101,8 +160,26 @@ func setupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	router.SetupAuthRoutes(r.Group("/api"), db, emailService, cfg.JWTSecret)
 	//待办路由
 	router.SetupTodoRoutes(r, db, authService)
+	//看板路由
+	router.SetupBoardRoutes(r, db, authService)
+	//待办模板路由
+	router.SetupTemplateRoutes(r, db, authService)
+	//分类与标签路由
+	router.SetupLabelRoutes(r, db, authService)
+	//站内通知路由
+	router.SetupNotificationRoutes(r, db, authService)
+	//提醒路由
+	router.SetupReminderRoutes(r, db, authService)
+	//用时记录路由
+	router.SetupTimeTrackingRoutes(r, db, authService)
+	//统计路由
+	router.SetupStatsRoutes(r, db, authService)
+	//日历导出与订阅路由
+	router.SetupCalendarRoutes(r, db, authService)
 	//组织路由
 	router.SetupOrganizationRoutes(r, db, authService)
+	//组织待办路由
+	router.SetupOrgTodoRoutes(r, db, authService)
 	//AI路由
 	router.SetupAIRoutes(r, db, authService)
 }
@@ -111,6 +188,10 @@ func setupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 func (a *App) Run() {
 	//优雅关闭缓存
 	defer cache.Close()
+	//停止后台定时任务
+	if a.scheduler != nil {
+		defer a.scheduler.Stop()
+	}
 
 	log.Printf("服务器启动在 http://localhost:%s", a.config.ServerPort)
 	a.router.Run(":" + a.config.ServerPort)
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const lockKeyPrefix = "lock:"
+
+// releaseLockScript 仅当锁仍由自己持有时才删除，避免误删其他实例的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ErrCacheUnavailable Redis未初始化
+var ErrCacheUnavailable = errors.New("redis未初始化")
+
+// AcquireLock 尝试获取分布式锁
+// name: 锁名称
+// token: 持有者标识，释放时校验
+// ttl: 锁的自动过期时间，防止持有者崩溃后死锁
+func AcquireLock(name, token string, ttl time.Duration) (bool, error) {
+	if Client == nil {
+		return false, ErrCacheUnavailable
+	}
+	return Client.SetNX(ctx, lockKeyPrefix+name, token, ttl).Result()
+}
+
+// ReleaseLock 释放分布式锁
+func ReleaseLock(name, token string) error {
+	if Client == nil {
+		return ErrCacheUnavailable
+	}
+	return releaseLockScript.Run(ctx, Client, []string{lockKeyPrefix + name}, token).Err()
+}
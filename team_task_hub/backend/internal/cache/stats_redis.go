@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// statsVersionExpiration 统计版本号的保留时间，需远长于统计缓存本身的过期时间
+const statsVersionExpiration = 7 * 24 * time.Hour
+
+// UserStatsVersionKey 用户统计版本号缓存键，待办变化时递增，使该用户已缓存的统计全部失效
+func UserStatsVersionKey(userID uint) string {
+	return fmt.Sprintf("user:%d:stats_version", userID)
+}
+
+// GetUserStatsVersion 获取用户统计版本号，不存在时为0
+func GetUserStatsVersion(userID uint) (int64, error) {
+	version, err := Client.Get(ctx, UserStatsVersionKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// BumpUserStatsVersion 递增用户统计版本号
+func BumpUserStatsVersion(userID uint) error {
+	key := UserStatsVersionKey(userID)
+	if err := Client.Incr(ctx, key).Err(); err != nil {
+		return err
+	}
+	return Client.Expire(ctx, key, statsVersionExpiration).Err()
+}
+
+// UserStatsKey 用户某项统计在某个版本下的缓存键
+func UserStatsKey(userID uint, version int64, name string) string {
+	return fmt.Sprintf("user:%d:stats:%d:%s", userID, version, name)
+}
+
+// SetUserStats 缓存用户的统计结果
+func SetUserStats(userID uint, version int64, name string, value any, expiration time.Duration) error {
+	return setJson(UserStatsKey(userID, version, name), value, expiration)
+}
+
+// GetUserStats 获取缓存的统计结果
+func GetUserStats(userID uint, version int64, name string, dest any) error {
+	return getJson(UserStatsKey(userID, version, name), dest)
+}
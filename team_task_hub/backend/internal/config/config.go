@@ -13,6 +13,7 @@ type Config struct {
 	DBUser     string `mapstructure:"DB_USER"`
 	DBPassword string `mapstructure:"DB_PASSWORD"`
 	DBName     string `mapstructure:"DB_NAME"`
+	DBTimezone string `mapstructure:"DB_TIMEZONE"` // 数据库DATETIME所用时区，与写入时一致才能正确还原时间
 	JWTSecret  string `mapstructure:"JWT_SECRET"`
 	DebugMode  bool   `mapstructure:"DEBUG"`
 
@@ -25,6 +26,19 @@ type Config struct {
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       int    `mapstructure:"REDIS_DB"`
+
+	SchedulerEnabled bool `mapstructure:"SCHEDULER_ENABLED"`
+	// 是否只部署一个实例：是则Redis不可用时定时任务不加锁执行，否则Redis不可用时不启动定时任务
+	SchedulerSingleInstance bool `mapstructure:"SCHEDULER_SINGLE_INSTANCE"`
+	RenewIntervalMinutes    int  `mapstructure:"RENEW_INTERVAL_MINUTES"`
+	OverdueIntervalMinutes  int  `mapstructure:"OVERDUE_INTERVAL_MINUTES"`
+	// 提醒投递轮询间隔（秒）
+	ReminderIntervalSeconds int `mapstructure:"REMINDER_INTERVAL_SECONDS"`
+	// 回收站保留天数，超期后由后台任务彻底删除
+	TrashRetentionDays   int `mapstructure:"TRASH_RETENTION_DAYS"`
+	PurgeIntervalMinutes int `mapstructure:"PURGE_INTERVAL_MINUTES"`
+	// 弹性待办自动排程的间隔（分钟），处理活动变化、时间流逝等导致的安排失效
+	AutoScheduleIntervalMinutes int `mapstructure:"AUTO_SCHEDULE_INTERVAL_MINUTES"`
 }
 
 func LoadConfig() *Config {
@@ -65,6 +79,7 @@ func setDefaults() {
 	viper.SetDefault("DB_USER", "root")
 	viper.SetDefault("DB_PASSWORD", "")
 	viper.SetDefault("DB_NAME", "team_task_hub")
+	viper.SetDefault("DB_TIMEZONE", "Local")
 
 	viper.SetDefault("JWT_SECRET", "default-jwt-secret-change-in-production")
 
@@ -77,4 +92,13 @@ func setDefaults() {
 	viper.SetDefault("REDIS_ADDR", "localhost:6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
+
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+	viper.SetDefault("SCHEDULER_SINGLE_INSTANCE", true)
+	viper.SetDefault("RENEW_INTERVAL_MINUTES", 60)
+	viper.SetDefault("OVERDUE_INTERVAL_MINUTES", 5)
+	viper.SetDefault("REMINDER_INTERVAL_SECONDS", 60)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("PURGE_INTERVAL_MINUTES", 60)
+	viper.SetDefault("AUTO_SCHEDULE_INTERVAL_MINUTES", 15)
 }
@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"team_task_hub/backend/internal/config"
 	"team_task_hub/backend/internal/models"
 
@@ -11,13 +12,14 @@ import (
 )
 
 func InitDB(cfg *config.Config) (*gorm.DB, error) {
-	// 构建DSN连接字符串
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	// 构建DSN连接字符串，DATETIME按DB_TIMEZONE解释，用户时区只影响日期范围的计算和接口返回
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=%s",
 		cfg.DBUser,
 		cfg.DBPassword,
 		cfg.DBHost,
 		cfg.DBPort,
-		cfg.DBName)
+		cfg.DBName,
+		url.QueryEscape(cfg.DBTimezone))
 
 	// 连接数据库
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
@@ -40,11 +42,33 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 		&models.Permission{},
 		&models.VerificationCode{},
 		&models.OrganizationApplication{},
+		&models.Category{},
+		&models.Tag{},
+		&models.TodoTag{},
+		&models.ChecklistItem{},
+		&models.TodoDependency{},
+		&models.Notification{},
+		&models.Reminder{},
+		&models.CalendarFeed{},
+		&models.TodoChangeSet{},
+		&models.TodoChange{},
+		&models.TimeEntry{},
+		&models.Board{},
+		&models.BoardColumn{},
+		&models.BoardCard{},
+		&models.TodoTemplate{},
+		&models.TodoTemplateItem{},
+		&models.SchedulePreference{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("表迁移失败: %v", err)
 	}
 
+	// 数据迁移
+	if err := migrateTodoCategories(db); err != nil {
+		return nil, fmt.Errorf("分类数据迁移失败: %v", err)
+	}
+
 	log.Println("数据库连接和表迁移成功!")
 	return db, nil
 }
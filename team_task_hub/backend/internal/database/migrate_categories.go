@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// migrateTodoCategories 将旧版ENUM分类迁移为用户分类
+// 为每个仍未关联分类的用户创建预置分类（以及历史数据中出现过的其他分类），并回填待办的category_id。
+// 迁移是幂等的，只处理category_id为0的待办。
+func migrateTodoCategories(db *gorm.DB) error {
+	var rows []struct {
+		CreatorUserID uint
+		Category      string
+	}
+	err := db.Model(&models.Todo{}).
+		Select("DISTINCT creator_user_id, category").
+		Where("category_id = 0 AND creator_user_id != 0").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("查询待迁移分类失败: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	isDefault := make(map[string]bool, len(models.DefaultCategoryNames))
+	for _, name := range models.DefaultCategoryNames {
+		isDefault[name] = true
+	}
+
+	seeded := make(map[uint]bool)
+	migrated := int64(0)
+	for _, row := range rows {
+		// 先为用户创建全部预置分类
+		if !seeded[row.CreatorUserID] {
+			for _, name := range models.DefaultCategoryNames {
+				if _, err := findOrCreateCategory(db, row.CreatorUserID, name, true); err != nil {
+					return err
+				}
+			}
+			seeded[row.CreatorUserID] = true
+		}
+
+		name := row.Category
+		if name == "" {
+			name = models.DefaultCategoryName
+		}
+		category, err := findOrCreateCategory(db, row.CreatorUserID, name, isDefault[name])
+		if err != nil {
+			return err
+		}
+
+		result := db.Model(&models.Todo{}).
+			Where("creator_user_id = ? AND category_id = 0 AND category = ?", row.CreatorUserID, row.Category).
+			Updates(map[string]any{
+				"category_id": category.ID,
+				"category":    category.Name,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("回填待办分类失败: %v", result.Error)
+		}
+		migrated += result.RowsAffected
+	}
+
+	log.Printf("待办分类迁移完成: 涉及用户 %d 个, 待办 %d 条", len(seeded), migrated)
+	return nil
+}
+
+// findOrCreateCategory 查找或创建用户的分类
+func findOrCreateCategory(db *gorm.DB, userID uint, name string, isDefault bool) (*models.Category, error) {
+	category := models.Category{UserID: userID, Name: name}
+	err := db.
+		Where(models.Category{UserID: userID, Name: name}).
+		Attrs(models.Category{IsDefault: isDefault}).
+		FirstOrCreate(&category).Error
+	if err != nil {
+		return nil, fmt.Errorf("创建分类 %s 失败: %v", name, err)
+	}
+	return &category, nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"team_task_hub/backend/internal/services"
 
@@ -329,6 +330,52 @@ func (h *AuthHandler) UpdateAvatar(c *gin.Context) {
 	})
 }
 
+// UpdateTimezone 更新时区
+// @Summary 更新用户时区
+// @Description 设置IANA时区名称（如Asia/Shanghai），今天、本周、本月等时间范围按该时区计算；传空字符串恢复使用服务器时区
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body string true "更新时区参数" example({"timezone": "Asia/Shanghai"})
+// @Success 200 {object} string "更新成功" example({"success": true, "message": "时区更新成功"})
+// @Failure 400 {object} string "参数错误" example({"success": false, "message": "无法识别的时区 Mars/Base"})
+// @Router /api/auth/change_timezone [put]
+func (h *AuthHandler) UpdateTimezone(c *gin.Context) {
+	var req struct {
+		Timezone string `json:"timezone" binding:"max=64"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	userIDUint := userID.(uint)
+
+	if err := h.authService.UpdateTimezone(userIDUint, req.Timezone); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidTimezone) {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "时区更新成功",
+	})
+}
+
 // UpdateEmail 更新邮箱
 // @Summary 更新用户邮箱
 // @Tags 认证
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BoardHandler 看板处理器
+type BoardHandler struct {
+	boardService *services.BoardService
+}
+
+// NewBoardHandler 构造函数
+func NewBoardHandler(boardService *services.BoardService) *BoardHandler {
+	return &BoardHandler{boardService: boardService}
+}
+
+// parseBoardID 从路径参数中解析看板ID，解析失败时直接写入400响应
+func parseBoardID(c *gin.Context) (uint, bool) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || boardID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的看板ID格式",
+		})
+		return 0, false
+	}
+	return uint(boardID), true
+}
+
+// ListBoardsHandler 获取看板列表
+// @Summary 获取看板列表
+// @Description 返回当前用户的全部看板及列配置；首次访问时自动创建默认的状态看板（待办、进行中、已完成）
+// @Tags 看板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "boards": [...]})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards [get]
+func (h *BoardHandler) ListBoardsHandler(c *gin.Context) {
+	boards, err := h.boardService.ListBoards(c.GetUint("userID"))
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"boards":  boards,
+	})
+}
+
+// CreateBoardHandler 创建看板
+// @Summary 创建看板
+// @Description 创建按状态（group_by=status，每列对应一个状态）或按标签（group_by=tag，每列对应一个标签，不存在的标签自动创建）分列的看板，最多10列
+// @Tags 看板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.CreateBoardRequest true "看板配置"
+// @Success 201 {object} SuccessResponse "创建成功" example({"success": true, "message": "创建成功", "board": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards [post]
+func (h *BoardHandler) CreateBoardHandler(c *gin.Context) {
+	var req services.CreateBoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	board, err := h.boardService.CreateBoard(c.GetUint("userID"), &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "创建成功",
+		"board":   board,
+	})
+}
+
+// GetBoardHandler 获取看板及各列待办
+// @Summary 获取看板及各列待办
+// @Description 一次返回看板的全部列及每列中的待办。列内先按拖拽排序、再按开始时间排列；标签看板只包含未完成的待办，同时带有多个列标签的待办只出现在靠前的列
+// @Tags 看板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "看板ID"
+// @Param limit query int false "每列返回的待办数量，默认100，最大500"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "board": {"id": 1, "columns": [...]}})
+// @Failure 404 {object} ErrorResponse "看板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards/{id} [get]
+func (h *BoardHandler) GetBoardHandler(c *gin.Context) {
+	boardID, ok := parseBoardID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	board, err := h.boardService.GetBoard(c.GetUint("userID"), boardID, limit)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"board":   board,
+	})
+}
+
+// UpdateBoardHandler 更新看板
+// @Summary 更新看板
+// @Description 修改看板名称，或传入columns整体替换看板列（列ID会变化），分列方式不可修改，已有的拖拽排序保留
+// @Tags 看板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "看板ID"
+// @Param request body services.UpdateBoardRequest true "更新内容"
+// @Success 200 {object} SuccessResponse "更新成功" example({"success": true, "message": "更新成功", "board": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 404 {object} ErrorResponse "看板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards/{id} [put]
+func (h *BoardHandler) UpdateBoardHandler(c *gin.Context) {
+	boardID, ok := parseBoardID(c)
+	if !ok {
+		return
+	}
+	var req services.UpdateBoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	board, err := h.boardService.UpdateBoard(c.GetUint("userID"), boardID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"board":   board,
+	})
+}
+
+// DeleteBoardHandler 删除看板
+// @Summary 删除看板
+// @Description 删除看板及其列和拖拽排序，不影响待办本身；默认看板不能删除
+// @Tags 看板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "看板ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 400 {object} ErrorResponse "默认看板不能删除"
+// @Failure 404 {object} ErrorResponse "看板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards/{id} [delete]
+func (h *BoardHandler) DeleteBoardHandler(c *gin.Context) {
+	boardID, ok := parseBoardID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.boardService.DeleteBoard(c.GetUint("userID"), boardID); err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "删除成功",
+	})
+}
+
+// MoveBoardCardHandler 移动看板卡片
+// @Summary 移动看板卡片
+// @Description 将待办拖到某一列的指定位置：after_id为上方相邻的待办，before_id为下方相邻的待办，都不传时放到列尾。移入状态列会按状态机转换状态，移入标签列会替换看板上的标签，状态或标签与排序在同一事务中更新
+// @Tags 看板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "看板ID"
+// @Param request body services.MoveBoardCardRequest true "移动信息"
+// @Success 200 {object} SuccessResponse "移动成功" example({"success": true, "message": "移动成功", "todo": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "看板或待办不存在"
+// @Failure 409 {object} ErrorResponse "非法的状态转换或待办被阻塞"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/boards/{id}/move [post]
+func (h *BoardHandler) MoveBoardCardHandler(c *gin.Context) {
+	boardID, ok := parseBoardID(c)
+	if !ok {
+		return
+	}
+	var req services.MoveBoardCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	todo, err := h.boardService.MoveBoardCard(c.GetUint("userID"), boardID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "移动成功",
+		"todo":    todo,
+	})
+}
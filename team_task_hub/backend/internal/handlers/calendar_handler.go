@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler 日历导出与订阅处理器
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+// NewCalendarHandler 构造函数
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// calendarContentType iCalendar内容类型
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// calendarErrorStatus 根据服务层返回的错误确定HTTP状态码
+func calendarErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrCalendarFeedNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidTodoParams):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// feedResponse 生成订阅信息响应，订阅地址根据当前请求的域名拼接
+func feedResponse(c *gin.Context, message string, feed *models.CalendarFeed) gin.H {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	path := host + "/api/calendar/feeds/" + feed.Token + ".ics"
+
+	return gin.H{
+		"success":    true,
+		"message":    message,
+		"token":      feed.Token,
+		"feed_url":   scheme + "://" + path,
+		"webcal_url": "webcal://" + path,
+	}
+}
+
+// GetFeedHandler 获取日历订阅链接
+// @Summary 获取日历订阅链接
+// @Description 获取当前用户的日历订阅地址，首次调用时自动生成。订阅地址包含私密token，可直接添加到Google/Outlook/Apple日历，无需登录
+// @Tags 日历
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "token": "...", "feed_url": "https://host/api/calendar/feeds/xxx.ics", "webcal_url": "webcal://host/api/calendar/feeds/xxx.ics"})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/calendar/feed [get]
+func (h *CalendarHandler) GetFeedHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	feed, err := h.calendarService.GetFeed(userID)
+	if err != nil {
+		c.JSON(calendarErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedResponse(c, "查询成功", feed))
+}
+
+// ResetFeedHandler 重置日历订阅链接
+// @Summary 重置日历订阅链接
+// @Description 重新生成订阅token，旧的订阅地址立即失效，适用于链接泄露的情况
+// @Tags 日历
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "重置成功"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/calendar/feed/reset [post]
+func (h *CalendarHandler) ResetFeedHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	feed, err := h.calendarService.ResetFeed(userID)
+	if err != nil {
+		c.JSON(calendarErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedResponse(c, "订阅链接已重置", feed))
+}
+
+// RevokeFeedHandler 关闭日历订阅
+// @Summary 关闭日历订阅
+// @Description 删除当前用户的订阅token，已添加的订阅将无法继续同步
+// @Tags 日历
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "关闭成功"
+// @Failure 404 {object} ErrorResponse "尚未开启日历订阅"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/calendar/feed [delete]
+func (h *CalendarHandler) RevokeFeedHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := h.calendarService.RevokeFeed(userID); err != nil {
+		c.JSON(calendarErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "日历订阅已关闭",
+	})
+}
+
+// ExportCalendarHandler 下载日历文件
+// @Summary 下载日历文件
+// @Description 将当前用户未完成的待办和参与的活动导出为.ics文件，重复待办带RRULE
+// @Tags 日历
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param format query string false "待办呈现方式：vevent（默认，日程）或 vtodo（任务）"
+// @Success 200 {string} string "iCalendar内容"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/calendar/export.ics [get]
+func (h *CalendarHandler) ExportCalendarHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	body, err := h.calendarService.RenderUserCalendar(userID, c.Query("format"))
+	if err != nil {
+		c.JSON(calendarErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="team-task-hub.ics"`)
+	c.Data(http.StatusOK, calendarContentType, []byte(body))
+}
+
+// CalendarFeedHandler 日历订阅地址
+// @Summary 日历订阅地址
+// @Description 供日历客户端订阅使用，通过路径中的私密token识别用户，无需JWT
+// @Tags 日历
+// @Produce text/calendar
+// @Param token path string true "订阅token，可带.ics后缀"
+// @Param format query string false "待办呈现方式：vevent（默认，日程）或 vtodo（任务）"
+// @Success 200 {string} string "iCalendar内容"
+// @Failure 404 {string} string "订阅不存在或已失效"
+// @Router /api/calendar/feeds/{token} [get]
+func (h *CalendarHandler) CalendarFeedHandler(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	body, err := h.calendarService.RenderFeed(token, c.Query("format"))
+	if err != nil {
+		c.String(calendarErrorStatus(err), err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, []byte(body))
+}
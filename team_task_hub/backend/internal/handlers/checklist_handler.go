@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChecklistResponse 清单操作响应结构
+type ChecklistResponse struct {
+	Success   bool                     `json:"success"`
+	Message   string                   `json:"message"`
+	Checklist services.ChecklistResult `json:"checklist"`
+}
+
+// parseChecklistItemID 从路径参数中解析清单项ID，解析失败时直接写入400响应
+func parseChecklistItemID(c *gin.Context) (uint, bool) {
+	itemID, err := strconv.ParseUint(c.Param("itemID"), 10, 32)
+	if err != nil || itemID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的清单项ID格式",
+		})
+		return 0, false
+	}
+	return uint(itemID), true
+}
+
+// GetChecklistHandler 获取待办清单
+// @Summary 获取待办清单
+// @Description 按顺序返回待办的全部清单项及完成进度
+// @Tags 待办清单
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} ChecklistResponse "查询成功"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/checklist [get]
+func (h *TodoHandler) GetChecklistHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.todoService.GetChecklist(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "查询成功",
+		"checklist": result,
+	})
+}
+
+// AddChecklistItemHandler 添加清单项
+// @Summary 添加清单项
+// @Description 在待办清单的末尾添加一项；重复待办的抽象待办不能添加清单
+// @Tags 待办清单
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.ChecklistItemRequest true "清单项"
+// @Success 201 {object} ChecklistResponse "添加成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/checklist [post]
+func (h *TodoHandler) AddChecklistItemHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.todoService.AddChecklistItem(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"message":   "清单项添加成功",
+		"checklist": result,
+	})
+}
+
+// UpdateChecklistItemHandler 更新清单项
+// @Summary 更新清单项
+// @Description 修改清单项标题或勾选状态。待办开启 auto_complete 时，勾选最后一项会自动将待办标记为已完成（响应中 auto_completed 为 true）
+// @Tags 待办清单
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param itemID path int true "清单项ID"
+// @Param request body services.UpdateChecklistItemRequest true "更新内容"
+// @Success 200 {object} ChecklistResponse "更新成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办或清单项不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/checklist/{itemID} [patch]
+func (h *TodoHandler) UpdateChecklistItemHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	itemID, ok := parseChecklistItemID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.todoService.UpdateChecklistItem(userID, todoID, itemID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "清单项更新成功",
+		"checklist": result,
+	})
+}
+
+// DeleteChecklistItemHandler 删除清单项
+// @Summary 删除清单项
+// @Description 删除待办的某个清单项；删除后剩余清单项全部已勾选且待办开启了自动完成时，待办会自动变为已完成
+// @Tags 待办清单
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param itemID path int true "清单项ID"
+// @Success 200 {object} ChecklistResponse "删除成功"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办或清单项不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/checklist/{itemID} [delete]
+func (h *TodoHandler) DeleteChecklistItemHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	itemID, ok := parseChecklistItemID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.todoService.DeleteChecklistItem(userID, todoID, itemID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "清单项已删除",
+		"checklist": result,
+	})
+}
+
+// ReorderChecklistHandler 清单项排序
+// @Summary 清单项排序
+// @Description 按 item_ids 的顺序重排清单项，必须包含该待办的全部清单项
+// @Tags 待办清单
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.ReorderChecklistRequest true "新的顺序"
+// @Success 200 {object} ChecklistResponse "排序成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/checklist/order [put]
+func (h *TodoHandler) ReorderChecklistHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.todoService.ReorderChecklist(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "清单排序成功",
+		"checklist": result,
+	})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDependenciesHandler 获取待办依赖
+// @Summary 获取待办依赖
+// @Description 返回待办的前置待办（blocked_by）、后续待办（blocks）以及当前是否被阻塞
+// @Tags 待办依赖
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "dependencies": {"blocked_by": [], "blocks": [], "blocked": false}})
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/dependencies [get]
+func (h *TodoHandler) GetDependenciesHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	dependencies, err := h.todoService.GetDependencies(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "查询成功",
+		"dependencies": dependencies,
+	})
+}
+
+// AddDependencyHandler 添加前置待办
+// @Summary 添加前置待办
+// @Description 设置 blocker_id 为当前待办的前置待办：前置待办完成（或取消）之前，当前待办不能开始或完成。两个待办必须属于同一用户，且不能形成循环依赖
+// @Tags 待办依赖
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.AddDependencyRequest true "前置待办"
+// @Success 201 {object} SuccessResponse "添加成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 409 {object} ErrorResponse "依赖已存在或形成循环"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/dependencies [post]
+func (h *TodoHandler) AddDependencyHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	dependencies, err := h.todoService.AddDependency(userID, todoID, req.BlockerID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "前置待办添加成功",
+		"dependencies": dependencies,
+	})
+}
+
+// RemoveDependencyHandler 移除前置待办
+// @Summary 移除前置待办
+// @Description 解除当前待办与某个前置待办的依赖关系
+// @Tags 待办依赖
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param blockerID path int true "前置待办ID"
+// @Success 200 {object} SuccessResponse "移除成功"
+// @Failure 400 {object} ErrorResponse "无效的ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办或依赖关系不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/dependencies/{blockerID} [delete]
+func (h *TodoHandler) RemoveDependencyHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blockerID"), 10, 32)
+	if err != nil || blockerID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的前置待办ID格式",
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	dependencies, err := h.todoService.RemoveDependency(userID, todoID, uint(blockerID))
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "前置待办已移除",
+		"dependencies": dependencies,
+	})
+}
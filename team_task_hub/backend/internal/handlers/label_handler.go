@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LabelHandler 分类与标签处理器
+type LabelHandler struct {
+	categoryService *services.CategoryService
+	tagService      *services.TagService
+}
+
+// NewLabelHandler 构造函数
+func NewLabelHandler(categoryService *services.CategoryService, tagService *services.TagService) *LabelHandler {
+	return &LabelHandler{
+		categoryService: categoryService,
+		tagService:      tagService,
+	}
+}
+
+// parseLabelID 从路径参数中解析分类/标签ID，解析失败时直接写入400响应
+func parseLabelID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的ID格式",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// labelErrorStatus 根据服务层返回的错误确定HTTP状态码
+func labelErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrTagNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrLabelNameConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrDefaultCategory), errors.Is(err, services.ErrInvalidTodoParams):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListCategoriesHandler 获取分类列表
+// @Summary 获取分类列表
+// @Description 获取当前用户的全部待办分类，首次访问时自动创建预置分类（work/study/fun/personal）
+// @Tags 分类与标签
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "categories": [...]})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/categories [get]
+func (h *LabelHandler) ListCategoriesHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	categories, err := h.categoryService.ListCategories(userID)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"message":    "查询成功",
+		"categories": categories,
+	})
+}
+
+// CreateCategoryHandler 创建分类
+// @Summary 创建分类
+// @Description 创建用户自定义的待办分类，同一用户下分类名称不能重复
+// @Tags 分类与标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.CategoryRequest true "分类信息"
+// @Success 201 {object} SuccessResponse "创建成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 409 {object} ErrorResponse "名称已存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/categories [post]
+func (h *LabelHandler) CreateCategoryHandler(c *gin.Context) {
+	var req services.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	category, err := h.categoryService.CreateCategory(userID, &req)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"message":  "分类创建成功",
+		"category": category,
+	})
+}
+
+// UpdateCategoryHandler 更新分类
+// @Summary 更新分类
+// @Description 修改分类的名称和颜色，改名会同步到该分类下的所有待办；预置分类只能修改颜色
+// @Tags 分类与标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "分类ID"
+// @Param request body services.CategoryRequest true "分类信息"
+// @Success 200 {object} SuccessResponse "更新成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 404 {object} ErrorResponse "分类不存在"
+// @Failure 409 {object} ErrorResponse "名称已存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/categories/{id} [put]
+func (h *LabelHandler) UpdateCategoryHandler(c *gin.Context) {
+	categoryID, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	category, err := h.categoryService.UpdateCategory(userID, categoryID, &req)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "分类更新成功",
+		"category": category,
+	})
+}
+
+// DeleteCategoryHandler 删除分类
+// @Summary 删除分类
+// @Description 删除自定义分类，该分类下的待办会转移到 personal 分类；预置分类不能删除
+// @Tags 分类与标签
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "分类ID"
+// @Success 200 {object} SuccessResponse "删除成功" example({"success": true, "message": "分类已删除", "reassigned_count": 3})
+// @Failure 400 {object} ErrorResponse "预置分类不能删除"
+// @Failure 404 {object} ErrorResponse "分类不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/categories/{id} [delete]
+func (h *LabelHandler) DeleteCategoryHandler(c *gin.Context) {
+	categoryID, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	reassigned, err := h.categoryService.DeleteCategory(userID, categoryID)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"message":          "分类已删除",
+		"reassigned_count": reassigned,
+	})
+}
+
+// ListTagsHandler 获取标签列表
+// @Summary 获取标签列表
+// @Description 获取当前用户的全部标签
+// @Tags 分类与标签
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "tags": [...]})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/tags [get]
+func (h *LabelHandler) ListTagsHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	tags, err := h.tagService.ListTags(userID)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"tags":    tags,
+	})
+}
+
+// CreateTagHandler 创建标签
+// @Summary 创建标签
+// @Description 创建标签，同一用户下标签名称不能重复；创建/编辑待办时使用不存在的标签名也会自动创建
+// @Tags 分类与标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.TagRequest true "标签信息"
+// @Success 201 {object} SuccessResponse "创建成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 409 {object} ErrorResponse "名称已存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/tags [post]
+func (h *LabelHandler) CreateTagHandler(c *gin.Context) {
+	var req services.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	tag, err := h.tagService.CreateTag(userID, &req)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "标签创建成功",
+		"tag":     tag,
+	})
+}
+
+// UpdateTagHandler 更新标签
+// @Summary 更新标签
+// @Description 修改标签的名称和颜色
+// @Tags 分类与标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "标签ID"
+// @Param request body services.TagRequest true "标签信息"
+// @Success 200 {object} SuccessResponse "更新成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 404 {object} ErrorResponse "标签不存在"
+// @Failure 409 {object} ErrorResponse "名称已存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/tags/{id} [put]
+func (h *LabelHandler) UpdateTagHandler(c *gin.Context) {
+	tagID, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+
+	var req services.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	tag, err := h.tagService.UpdateTag(userID, tagID, &req)
+	if err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "标签更新成功",
+		"tag":     tag,
+	})
+}
+
+// DeleteTagHandler 删除标签
+// @Summary 删除标签
+// @Description 删除标签，并从所有待办上移除该标签
+// @Tags 分类与标签
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "标签ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 404 {object} ErrorResponse "标签不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/tags/{id} [delete]
+func (h *LabelHandler) DeleteTagHandler(c *gin.Context) {
+	tagID, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.tagService.DeleteTag(userID, tagID); err != nil {
+		c.JSON(labelErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "标签已删除",
+	})
+}
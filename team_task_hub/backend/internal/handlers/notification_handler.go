@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 站内通知处理器
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler 构造函数
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListNotificationsHandler 获取通知列表
+// @Summary 获取通知列表
+// @Description 按时间倒序返回当前用户的站内通知及未读数量
+// @Tags 站内通知
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param unread query bool false "只返回未读通知"
+// @Param limit query int false "返回条数，默认50，最大200"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "notifications": [...], "unread_count": 2})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/notifications [get]
+func (h *NotificationHandler) ListNotificationsHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+	unreadOnly := c.Query("unread") == "true"
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	notifications, unread, err := h.notificationService.ListNotifications(userID, unreadOnly, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"message":       "查询成功",
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+// MarkNotificationsReadHandler 标记通知已读
+// @Summary 标记通知已读
+// @Description 将指定通知标记为已读，ids为空时标记全部未读通知
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.MarkNotificationsReadRequest false "通知ID列表"
+// @Success 200 {object} SuccessResponse "标记成功" example({"success": true, "message": "标记成功", "count": 2})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/notifications/read [post]
+func (h *NotificationHandler) MarkNotificationsReadHandler(c *gin.Context) {
+	var req services.MarkNotificationsReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "请求参数错误: " + err.Error(),
+			})
+			return
+		}
+	}
+	userID := c.GetUint("userID")
+
+	count, err := h.notificationService.MarkRead(userID, req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "标记成功",
+		"count":   count,
+	})
+}
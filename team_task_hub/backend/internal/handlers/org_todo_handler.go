@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrgTodoHandler 组织待办处理器
+type OrgTodoHandler struct {
+	orgTodoService *services.OrgTodoService
+}
+
+// NewOrgTodoHandler 构造函数
+func NewOrgTodoHandler(orgTodoService *services.OrgTodoService) *OrgTodoHandler {
+	return &OrgTodoHandler{
+		orgTodoService: orgTodoService,
+	}
+}
+
+// respondOrgTodoError 根据服务层返回的错误返回对应的状态码和错误码
+func respondOrgTodoError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "INTERNAL_ERROR"
+	switch {
+	case errors.Is(err, services.ErrOrgTodoNotFound):
+		status, code = http.StatusNotFound, "ORG_TODO_NOT_FOUND"
+	case errors.Is(err, services.ErrAssignmentNotFound):
+		status, code = http.StatusNotFound, "ASSIGNMENT_NOT_FOUND"
+	case errors.Is(err, services.ErrAssignmentHandled):
+		status, code = http.StatusConflict, "ASSIGNMENT_HANDLED"
+	case errors.Is(err, services.ErrAssigneeNotOrgMember):
+		status, code = http.StatusBadRequest, "NOT_ORG_MEMBER"
+	default:
+		status, code = todoErrorInfo(err)
+	}
+	c.JSON(status, ErrorResponse{
+		Success: false,
+		Message: err.Error(),
+		Code:    code,
+	})
+}
+
+// parseIDParam 解析路径中的正整数ID
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "无效的" + name + "参数",
+			Code:    "INVALID_PARAMS",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// PublishOrgTodoHandler 发布组织待办
+// @Summary 发布组织待办
+// @Description 拥有publish_todo权限的成员（创建者和管理员默认拥有）以组织名义发布待办，可同时通过assignee_ids分配给组织成员，被分配的成员会收到站内通知
+// @Tags 组织待办
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param request body services.PublishOrgTodoRequest true "待办信息"
+// @Success 201 {object} SuccessResponse "发布成功" example({"success": true, "message": "发布成功", "data": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误或被分配的用户不是组织成员"
+// @Failure 403 {object} ErrorResponse "缺少publish_todo权限"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/todos [post]
+func (h *OrgTodoHandler) PublishOrgTodoHandler(c *gin.Context) {
+	orgID, ok := parseIDParam(c, "orgID")
+	if !ok {
+		return
+	}
+	var req services.PublishOrgTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	todo, err := h.orgTodoService.PublishOrgTodo(orgID, c.GetUint("userID"), &req)
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "发布成功",
+		"data":    todo,
+	})
+}
+
+// AssignOrgTodoHandler 分配组织待办
+// @Summary 分配组织待办
+// @Description 将组织待办分配给更多成员，已分配过的成员会被跳过，data.assigned_user_ids为本次新分配的成员
+// @Tags 组织待办
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param todoID path int true "组织待办ID"
+// @Param request body services.AssignOrgTodoRequest true "被分配的成员"
+// @Success 200 {object} SuccessResponse "分配成功" example({"success": true, "message": "分配成功", "data": {"assigned_user_ids": [2, 3]}})
+// @Failure 400 {object} ErrorResponse "请求参数错误或被分配的用户不是组织成员"
+// @Failure 403 {object} ErrorResponse "缺少publish_todo权限"
+// @Failure 404 {object} ErrorResponse "组织待办不存在"
+// @Failure 409 {object} ErrorResponse "组织待办已结束"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/todos/{todoID}/assign [post]
+func (h *OrgTodoHandler) AssignOrgTodoHandler(c *gin.Context) {
+	orgID, ok := parseIDParam(c, "orgID")
+	if !ok {
+		return
+	}
+	todoID, ok := parseIDParam(c, "todoID")
+	if !ok {
+		return
+	}
+	var req services.AssignOrgTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	assigned, err := h.orgTodoService.AssignOrgTodo(orgID, todoID, c.GetUint("userID"), req.UserIDs)
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "分配成功",
+		"data": gin.H{
+			"assigned_user_ids": assigned,
+		},
+	})
+}
+
+// ListOrgTodosHandler 获取组织待办列表
+// @Summary 获取组织待办列表
+// @Description 组织成员查看组织发布的待办，每项附带分配人数以及待回应、已接受、已拒绝、已完成的人数
+// @Tags 组织待办
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "data": [...]})
+// @Failure 403 {object} ErrorResponse "不是组织成员"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/todos [get]
+func (h *OrgTodoHandler) ListOrgTodosHandler(c *gin.Context) {
+	orgID, ok := parseIDParam(c, "orgID")
+	if !ok {
+		return
+	}
+
+	todos, err := h.orgTodoService.ListOrgTodos(orgID)
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"data":    todos,
+	})
+}
+
+// ListOrgTodoAssignmentsHandler 获取组织待办的分配记录
+// @Summary 获取组织待办的分配记录
+// @Description 查看组织待办分配给了哪些成员，以及每位成员的回应状态和个人待办的完成状态（todo_status）
+// @Tags 组织待办
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param todoID path int true "组织待办ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "data": [...]})
+// @Failure 403 {object} ErrorResponse "不是组织成员"
+// @Failure 404 {object} ErrorResponse "组织待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/todos/{todoID}/assignments [get]
+func (h *OrgTodoHandler) ListOrgTodoAssignmentsHandler(c *gin.Context) {
+	orgID, ok := parseIDParam(c, "orgID")
+	if !ok {
+		return
+	}
+	todoID, ok := parseIDParam(c, "todoID")
+	if !ok {
+		return
+	}
+
+	assignments, err := h.orgTodoService.ListAssignments(orgID, todoID)
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"data":    assignments,
+	})
+}
+
+// DeleteOrgTodoHandler 删除组织待办
+// @Summary 删除组织待办
+// @Description 拥有delete_todo权限的成员（创建者和管理员默认拥有）删除组织待办：撤回尚未回应的分配，成员已接受但未完成的个人待办改为已取消，已完成的保留
+// @Tags 组织待办
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param todoID path int true "组织待办ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 403 {object} ErrorResponse "缺少delete_todo权限"
+// @Failure 404 {object} ErrorResponse "组织待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/todos/{todoID} [delete]
+func (h *OrgTodoHandler) DeleteOrgTodoHandler(c *gin.Context) {
+	orgID, ok := parseIDParam(c, "orgID")
+	if !ok {
+		return
+	}
+	todoID, ok := parseIDParam(c, "todoID")
+	if !ok {
+		return
+	}
+
+	if err := h.orgTodoService.DeleteOrgTodo(orgID, todoID); err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "删除成功",
+	})
+}
+
+// ListMyAssignmentsHandler 获取分配给我的组织待办
+// @Summary 获取分配给我的组织待办
+// @Description 返回组织分配给当前用户的待办，每项附带来源组织名称；已接受的附带个人待办ID（personal_todo_id）及其状态
+// @Tags 组织待办
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param status query string false "分配状态" Enums(pending, accepted, rejected)
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "data": [...]})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/assignments [get]
+func (h *OrgTodoHandler) ListMyAssignmentsHandler(c *gin.Context) {
+	assignments, err := h.orgTodoService.ListMyAssignments(c.GetUint("userID"), c.Query("status"))
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"data":    assignments,
+	})
+}
+
+// AcceptAssignmentHandler 接受组织待办
+// @Summary 接受组织待办
+// @Description 接受组织分配的待办，在当前用户名下创建对应的个人待办（归入work分类，带来源组织），之后可按普通待办操作
+// @Tags 组织待办
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "分配记录ID"
+// @Success 200 {object} SuccessResponse "已接受" example({"success": true, "message": "已接受", "data": {...}})
+// @Failure 404 {object} ErrorResponse "分配记录或组织待办不存在"
+// @Failure 409 {object} ErrorResponse "该待办分配已处理"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/assignments/{id}/accept [post]
+func (h *OrgTodoHandler) AcceptAssignmentHandler(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	todo, err := h.orgTodoService.AcceptAssignment(c.GetUint("userID"), id)
+	if err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "已接受",
+		"data":    todo,
+	})
+}
+
+// RejectAssignmentHandler 拒绝组织待办
+// @Summary 拒绝组织待办
+// @Description 拒绝组织分配的待办，可附带拒绝原因，分配人会收到站内通知
+// @Tags 组织待办
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "分配记录ID"
+// @Param request body services.RejectAssignmentRequest false "拒绝原因"
+// @Success 200 {object} SuccessResponse "已拒绝"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 404 {object} ErrorResponse "分配记录不存在"
+// @Failure 409 {object} ErrorResponse "该待办分配已处理"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/assignments/{id}/reject [post]
+func (h *OrgTodoHandler) RejectAssignmentHandler(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req services.RejectAssignmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "请求参数错误: " + err.Error(),
+				Code:    "INVALID_PARAMS",
+			})
+			return
+		}
+	}
+
+	if err := h.orgTodoService.RejectAssignment(c.GetUint("userID"), id, req.Reason); err != nil {
+		respondOrgTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "已拒绝",
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -1781,7 +1782,7 @@ func (h *OrganizationHandler) GetActivityParticipantsHandler(c *gin.Context) {
 
 // ParticipateActivityHandler 用户参加活动
 // @Summary 用户参加活动/领取活动
-// @Description 用户参加指定活动，系统会检查是否重复参加，也就是领取待办的意思
+// @Description 用户参加指定活动，系统会检查是否重复参加，也就是领取待办的意思。同时检查与未完成待办和已参加活动的时间冲突，冲突列表随响应返回；conflict_policy=block时存在冲突返回409且不参加
 // @Tags 活动参与
 // @Accept json
 // @Produce json
@@ -1789,9 +1790,11 @@ func (h *OrganizationHandler) GetActivityParticipantsHandler(c *gin.Context) {
 // @Param Authorization header string true "Bearer Token" default(Bearer )
 // @Param orgID path int true "组织ID"
 // @Param activityID path int true "活动ID"
+// @Param conflict_policy query string false "时间冲突处理：warn（默认）返回冲突并继续参加，block 存在冲突时不参加"
 // @Success 201 {object} SuccessResponse "参加成功"
 // @Failure 400 {object} ErrorResponse "请求参数错误或用户已参加"
 // @Failure 404 {object} ErrorResponse "活动不存在"
+// @Failure 409 {object} ErrorResponse "存在时间冲突且conflict_policy=block"
 // @Failure 500 {object} ErrorResponse "系统内部错误"
 // @Router /api/organization/activities/{activityID}/participate [post]
 func (h *OrganizationHandler) ParticipateActivityHandler(c *gin.Context) {
@@ -1816,8 +1819,29 @@ func (h *OrganizationHandler) ParticipateActivityHandler(c *gin.Context) {
 		return
 	}
 
+	// 时间冲突处理策略
+	policy := c.DefaultQuery("conflict_policy", services.ConflictPolicyWarn)
+	if policy != services.ConflictPolicyWarn && policy != services.ConflictPolicyBlock {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "conflict_policy只能是warn或block",
+		})
+		return
+	}
+
 	// 调用服务层
-	err = h.activityService.ParticipateActivity(uint(activityID), userID.(uint))
+	conflicts, err := h.activityService.ParticipateActivity(uint(activityID), userID.(uint), policy)
+	if errors.Is(err, services.ErrScheduleConflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "参加活动失败: " + err.Error(),
+			"code":    "SCHEDULE_CONFLICT",
+			"data": gin.H{
+				"conflicts": conflicts,
+			},
+		})
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		errorMsg := err.Error()
@@ -1841,6 +1865,9 @@ func (h *OrganizationHandler) ParticipateActivityHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "成功参加活动",
+		"data": gin.H{
+			"conflicts": conflicts,
+		},
 	})
 }
 
@@ -1909,13 +1936,14 @@ func (h *OrganizationHandler) GetParticipationStatusHandler(c *gin.Context) {
 
 // BatchAssignActivityRequest 批量分配活动请求体
 type BatchAssignActivityRequest struct {
-	ActivityID uint   `json:"activity_id" binding:"required" example:"123"`
-	UserIDs    []uint `json:"user_ids" binding:"required,min=1" example:"1,2,3"`
+	ActivityID     uint   `json:"activity_id" binding:"required" example:"123"`
+	UserIDs        []uint `json:"user_ids" binding:"required,min=1" example:"1,2,3"`
+	ConflictPolicy string `json:"conflict_policy,omitempty" binding:"omitempty,oneof=warn block" example:"warn"` // 时间冲突处理：warn（默认）返回冲突并继续分配，block 任一用户存在冲突时不分配
 }
 
 // BatchAssignActivityHandler 批量分配活动
 // @Summary 批量分配活动
-// @Description 组织管理员将指定活动强制分配给一批用户（用户均未参与），并标记为“未读”状态
+// @Description 组织管理员将指定活动强制分配给一批用户（用户均未参与），并标记为“未读”状态。同时检查每个用户与未完成待办和已参加活动的时间冲突，存在冲突的用户随响应返回；conflict_policy=block时任一用户存在冲突返回409且不分配
 // @Tags 活动管理
 // @Accept json
 // @Produce json
@@ -1925,6 +1953,7 @@ type BatchAssignActivityRequest struct {
 // @Param request body BatchAssignActivityRequest true "分配请求"
 // @Success 201 {object} SuccessResponse "分配成功"
 // @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 409 {object} ErrorResponse "存在时间冲突且conflict_policy=block"
 // @Failure 500 {object} ErrorResponse "系统内部错误"
 // @Router /api/organization/{orgID}/activities/batch-assign [post]
 func (h *OrganizationHandler) BatchAssignActivityHandler(c *gin.Context) {
@@ -1937,7 +1966,19 @@ func (h *OrganizationHandler) BatchAssignActivityHandler(c *gin.Context) {
 	}
 
 	// 调用服务层
-	if err := h.activityService.ForceAssignActivityToUsers(req.ActivityID, req.UserIDs); err != nil {
+	conflicts, err := h.activityService.ForceAssignActivityToUsers(req.ActivityID, req.UserIDs, req.ConflictPolicy)
+	if errors.Is(err, services.ErrScheduleConflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "分配活动失败: " + err.Error(),
+			"code":    "SCHEDULE_CONFLICT",
+			"data": gin.H{
+				"conflicts": conflicts,
+			},
+		})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "分配活动失败: " + err.Error()})
 		return
 	}
@@ -1946,6 +1987,9 @@ func (h *OrganizationHandler) BatchAssignActivityHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "活动已成功分配给指定用户",
+		"data": gin.H{
+			"conflicts": conflicts,
+		},
 	})
 }
 
@@ -2360,3 +2404,130 @@ func (h *OrganizationHandler) SubmitReviewHandler(c *gin.Context) {
 		"message": "评价提交成功",
 	})
 }
+
+// GrantPermissionRequest 授予组织权限请求体
+type GrantPermissionRequest struct {
+	UserID         uint   `json:"user_id" binding:"required" example:"2"`
+	PermissionType string `json:"permission_type" binding:"required,oneof=publish_todo delete_todo manage_members publish_activity transfer_ownership" example:"publish_todo"`
+}
+
+// GetOrgPermissionsHandler 查询组织内单独授予的权限
+// @Summary 查询组织权限
+// @Description 组织管理员查询组织内单独授予给成员的权限，创建者和管理员默认拥有全部权限，不在列表中
+// @Tags 组织管理
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "data": [{"user_id": 2, "permission_type": "publish_todo"}]})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "权限不足"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/permissions [get]
+func (h *OrganizationHandler) GetOrgPermissionsHandler(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("orgID"), 10, 32)
+	if err != nil || orgID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的组织ID格式"})
+		return
+	}
+
+	permissions, err := h.orgService.GetOrgPermissions(uint(orgID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"data":    permissions,
+	})
+}
+
+// GrantPermissionHandler 为组织成员授予权限
+// @Summary 授予组织权限
+// @Description 组织管理员为普通成员单独授予权限，如publish_todo（发布和分配组织待办）、delete_todo（删除组织待办）。重复授予不会报错
+// @Tags 组织管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param request body GrantPermissionRequest true "授权信息"
+// @Success 200 {object} SuccessResponse "授权成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误或用户不是组织成员"
+// @Failure 403 {object} ErrorResponse "权限不足"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/permissions [post]
+func (h *OrganizationHandler) GrantPermissionHandler(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("orgID"), 10, 32)
+	if err != nil || orgID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的组织ID格式"})
+		return
+	}
+
+	var req GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "请求参数无效: " + err.Error()})
+		return
+	}
+
+	err = h.orgService.GrantPermission(uint(orgID), req.UserID, c.GetUint("userID"), req.PermissionType)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "该用户不是组织成员" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{"success": false, "message": "授予权限失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "权限授予成功",
+	})
+}
+
+// RevokePermissionHandler 撤销组织成员的权限
+// @Summary 撤销组织权限
+// @Description 组织管理员撤销单独授予给成员的权限
+// @Tags 组织管理
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param orgID path int true "组织ID"
+// @Param userID path int true "成员用户ID"
+// @Param permissionType path string true "权限类型" Enums(publish_todo, delete_todo, manage_members, publish_activity, transfer_ownership)
+// @Success 200 {object} SuccessResponse "撤销成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "权限不足"
+// @Failure 404 {object} ErrorResponse "该成员没有此权限"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/organization/{orgID}/permissions/{userID}/{permissionType} [delete]
+func (h *OrganizationHandler) RevokePermissionHandler(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("orgID"), 10, 32)
+	if err != nil || orgID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的组织ID格式"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 32)
+	if err != nil || userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的用户ID格式"})
+		return
+	}
+
+	err = h.orgService.RevokePermission(uint(orgID), uint(userID), c.Param("permissionType"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "该成员没有此权限" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{"success": false, "message": "撤销权限失败: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "权限已撤销",
+	})
+}
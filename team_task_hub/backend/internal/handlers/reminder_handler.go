@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReminderHandler 提醒处理器
+type ReminderHandler struct {
+	reminderService *services.ReminderService
+}
+
+// NewReminderHandler 构造函数
+func NewReminderHandler(reminderService *services.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// reminderErrorStatus 根据服务层返回的错误确定HTTP状态码
+func reminderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrReminderNotFound), errors.Is(err, services.ErrTodoNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrReminderTarget):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidReminder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateReminderHandler 创建提醒
+// @Summary 创建提醒
+// @Description 为自己的待办或已参与的活动创建提醒。anchor为start/end时在开始/结束前offset_minutes分钟提醒，目标时间变动后自动跟随；anchor为absolute时在remind_at提醒。channel支持email（默认）和in_app
+// @Tags 提醒
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.CreateReminderRequest true "提醒信息"
+// @Success 201 {object} SuccessResponse "创建成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权为该目标设置提醒"
+// @Failure 404 {object} ErrorResponse "目标不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/reminders [post]
+func (h *ReminderHandler) CreateReminderHandler(c *gin.Context) {
+	var req services.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	reminder, err := h.reminderService.CreateReminder(userID, &req)
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"message":  "提醒创建成功",
+		"reminder": reminder,
+	})
+}
+
+// ListRemindersHandler 获取提醒列表
+// @Summary 获取提醒列表
+// @Description 获取当前用户的提醒及其投递状态，可按目标过滤
+// @Tags 提醒
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param target_type query string false "目标类型：todo/activity"
+// @Param target_id query int false "目标ID，指定target_type时必填"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "reminders": [...]})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权查看该目标的提醒"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/reminders [get]
+func (h *ReminderHandler) ListRemindersHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+	targetType := c.Query("target_type")
+
+	var targetID uint64
+	if targetType != "" {
+		id, err := strconv.ParseUint(c.Query("target_id"), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "无效的target_id",
+			})
+			return
+		}
+		targetID = id
+	}
+
+	reminders, err := h.reminderService.ListReminders(userID, targetType, uint(targetID))
+	if err != nil {
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "查询成功",
+		"reminders": reminders,
+	})
+}
+
+// CancelReminderHandler 取消提醒
+// @Summary 取消提醒
+// @Description 取消尚未投递的提醒，已投递的提醒不能取消
+// @Tags 提醒
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "提醒ID"
+// @Success 200 {object} SuccessResponse "取消成功"
+// @Failure 400 {object} ErrorResponse "无效的ID格式"
+// @Failure 404 {object} ErrorResponse "提醒不存在或已投递"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/reminders/{id} [delete]
+func (h *ReminderHandler) CancelReminderHandler(c *gin.Context) {
+	id, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.reminderService.CancelReminder(userID, id); err != nil {
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "提醒已取消",
+	})
+}
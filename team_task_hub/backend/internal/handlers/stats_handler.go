@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 统计处理器
+type StatsHandler struct {
+	statsService *services.StatsService
+}
+
+// NewStatsHandler 构造函数
+func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// statsErrorStatus 根据服务层返回的错误确定HTTP状态码
+func statsErrorStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidStatsParams) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetStatsOverviewHandler 统计总览
+// @Summary 统计总览
+// @Description 统计截止时间在区间内、未取消的待办的完成率、按时/逾期完成情况和分类明细，并返回连续完成天数。日期按用户时区计算，to当天包含在内，均不传时统计本周
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD（包含）"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "overview": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/stats/overview [get]
+func (h *StatsHandler) GetStatsOverviewHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	overview, err := h.statsService.GetOverview(userID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(statsErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "查询成功",
+		"overview": overview,
+	})
+}
+
+// GetStatsTrendsHandler 完成趋势
+// @Summary 完成趋势
+// @Description 按周（周一开始）或按月返回最近若干周期的完成情况，最后一个周期为当前周期
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param period query string false "统计周期：week（默认）/month"
+// @Param count query int false "周期数，默认8周或6个月，最多52周或24个月"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "trends": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/stats/trends [get]
+func (h *StatsHandler) GetStatsTrendsHandler(c *gin.Context) {
+	count := 0
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "无效的count",
+			})
+			return
+		}
+		count = parsed
+	}
+	userID := c.GetUint("userID")
+
+	trends, err := h.statsService.GetTrends(userID, c.Query("period"), count)
+	if err != nil {
+		c.JSON(statsErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"trends":  trends,
+	})
+}
+
+// GetStreaksHandler 连续完成天数
+// @Summary 连续完成天数
+// @Description 返回当前连续完成天数（今天尚未完成时截至昨天）、历史最长连续天数和有完成记录的总天数
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "streaks": {"current": 3, "longest": 12}})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/stats/streaks [get]
+func (h *StatsHandler) GetStreaksHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	streaks, err := h.statsService.GetStreaks(userID)
+	if err != nil {
+		c.JSON(statsErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"streaks": streaks,
+	})
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler 待办模板处理器
+type TemplateHandler struct {
+	templateService *services.TemplateService
+}
+
+// NewTemplateHandler 构造函数
+func NewTemplateHandler(templateService *services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// parseTemplateID 从路径参数中解析模板ID，解析失败时直接写入400响应
+func parseTemplateID(c *gin.Context) (uint, bool) {
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || templateID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的模板ID格式",
+		})
+		return 0, false
+	}
+	return uint(templateID), true
+}
+
+// ListTemplatesHandler 获取待办模板列表
+// @Summary 获取待办模板列表
+// @Description 返回自己创建的模板以及共享到所在组织的模板（含模板中的待办），自己创建的在前
+// @Tags 待办模板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "templates": [...]})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates [get]
+func (h *TemplateHandler) ListTemplatesHandler(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.GetUint("userID"))
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "查询成功",
+		"templates": templates,
+	})
+}
+
+// CreateTemplateHandler 创建待办模板
+// @Summary 创建待办模板
+// @Description 创建包含一个或多个待办的模板，每个待办记录标题、内容、紧急程度、分类、相对实例化日期的开始时刻和时长、重复规则以及清单。organization_id非0时共享到该组织（需为组织成员），组织成员可查看和使用，只有创建者可修改和删除
+// @Tags 待办模板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.CreateTodoTemplateRequest true "模板内容"
+// @Success 201 {object} SuccessResponse "创建成功" example({"success": true, "message": "创建成功", "template": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "不是要共享到的组织的成员"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates [post]
+func (h *TemplateHandler) CreateTemplateHandler(c *gin.Context) {
+	var req services.CreateTodoTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.GetUint("userID"), &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"message":  "创建成功",
+		"template": template,
+	})
+}
+
+// GetTemplateHandler 获取待办模板详情
+// @Summary 获取待办模板详情
+// @Description 获取自己创建的或共享到所在组织的模板
+// @Tags 待办模板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "模板ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "template": {...}})
+// @Failure 404 {object} ErrorResponse "模板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates/{id} [get]
+func (h *TemplateHandler) GetTemplateHandler(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(c.GetUint("userID"), templateID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "查询成功",
+		"template": template,
+	})
+}
+
+// UpdateTemplateHandler 更新待办模板
+// @Summary 更新待办模板
+// @Description 修改模板名称、说明或共享的组织（传0取消共享），传入items时整体替换模板中的待办；只有创建者可以修改
+// @Tags 待办模板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "模板ID"
+// @Param request body services.UpdateTodoTemplateRequest true "更新内容"
+// @Success 200 {object} SuccessResponse "更新成功" example({"success": true, "message": "更新成功", "template": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权修改该模板"
+// @Failure 404 {object} ErrorResponse "模板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplateHandler(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	var req services.UpdateTodoTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.GetUint("userID"), templateID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "更新成功",
+		"template": template,
+	})
+}
+
+// DeleteTemplateHandler 删除待办模板
+// @Summary 删除待办模板
+// @Description 删除模板，已由模板创建的待办不受影响；只有创建者可以删除
+// @Tags 待办模板
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "模板ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 403 {object} ErrorResponse "无权修改该模板"
+// @Failure 404 {object} ErrorResponse "模板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplateHandler(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.GetUint("userID"), templateID); err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Message: "删除成功",
+	})
+}
+
+// InstantiateTemplateHandler 按模板创建待办
+// @Summary 按模板创建待办
+// @Description 以date为基准日期（按用户时区）一次创建模板中的全部待办，或只创建item_ids指定的待办。每个待办的开始时间为基准日期加day_offset天的start_minute时刻，重复规则、清单随之创建；任一待办校验失败时全部不创建。组织共享模板中的分类在当前用户下不存在时使用默认分类
+// @Tags 待办模板
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "模板ID"
+// @Param request body services.InstantiateTemplateRequest true "基准日期"
+// @Success 201 {object} SuccessResponse "创建成功" example({"success": true, "message": "已按模板创建2个待办", "result": {"template_id": 1, "date": "2024-01-15", "todos": [...]}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 404 {object} ErrorResponse "模板不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todo-templates/{id}/instantiate [post]
+func (h *TemplateHandler) InstantiateTemplateHandler(c *gin.Context) {
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+	var req services.InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.templateService.InstantiateTemplate(c.GetUint("userID"), templateID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "已按模板创建" + strconv.Itoa(len(result.Todos)) + "个待办",
+		"result":  result,
+	})
+}
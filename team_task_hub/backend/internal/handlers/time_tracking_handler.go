@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeTrackingHandler 用时记录处理器
+type TimeTrackingHandler struct {
+	timeTrackingService *services.TimeTrackingService
+}
+
+// NewTimeTrackingHandler 构造函数
+func NewTimeTrackingHandler(timeTrackingService *services.TimeTrackingService) *TimeTrackingHandler {
+	return &TimeTrackingHandler{
+		timeTrackingService: timeTrackingService,
+	}
+}
+
+// timeTrackingErrorStatus 根据服务层返回的错误确定HTTP状态码
+func timeTrackingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound), errors.Is(err, services.ErrTimeEntryNotFound),
+		errors.Is(err, services.ErrNoRunningTimer):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrTodoForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidTimeEntry):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// StartTimerHandler 开始计时
+// @Summary 开始计时
+// @Description 为待办开始计时。每个用户同时只能有一个计时器，已有计时器在运行时会先将其停止并在stopped中返回
+// @Tags 用时记录
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.StartTimerRequest true "计时信息"
+// @Success 201 {object} SuccessResponse "计时开始" example({"success": true, "message": "计时开始", "timer": {...}, "stopped": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误或待办已结束"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/timer [post]
+func (h *TimeTrackingHandler) StartTimerHandler(c *gin.Context) {
+	var req services.StartTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	result, err := h.timeTrackingService.StartTimer(userID, &req)
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "计时开始",
+		"timer":   result.Timer,
+		"stopped": result.Stopped,
+	})
+}
+
+// StopTimerHandler 停止计时
+// @Summary 停止计时
+// @Description 停止当前用户正在运行的计时器，返回记录的用时
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "计时已停止" example({"success": true, "message": "计时已停止", "entry": {...}})
+// @Failure 404 {object} ErrorResponse "当前没有正在计时的待办"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/timer/stop [post]
+func (h *TimeTrackingHandler) StopTimerHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	entry, err := h.timeTrackingService.StopTimer(userID)
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "计时已停止",
+		"entry":   entry,
+	})
+}
+
+// GetRunningTimerHandler 获取正在运行的计时器
+// @Summary 获取正在运行的计时器
+// @Description 返回当前用户正在运行的计时器，duration_seconds为截至当前的用时；没有计时器时timer为null
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "timer": null})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/timer [get]
+func (h *TimeTrackingHandler) GetRunningTimerHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	timer, err := h.timeTrackingService.GetRunningTimer(userID)
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"timer":   timer,
+	})
+}
+
+// AddTimeEntryHandler 手动补录用时
+// @Summary 手动补录用时
+// @Description 为待办补录一段已经发生的用时，ended_at和duration_minutes二选一，单条记录不超过24小时
+// @Tags 用时记录
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.ManualTimeEntryRequest true "用时信息"
+// @Success 201 {object} SuccessResponse "补录成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/entries [post]
+func (h *TimeTrackingHandler) AddTimeEntryHandler(c *gin.Context) {
+	var req services.ManualTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	entry, err := h.timeTrackingService.AddManualEntry(userID, &req)
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "用时补录成功",
+		"entry":   entry,
+	})
+}
+
+// ListTimeEntriesHandler 获取待办的用时记录
+// @Summary 获取待办的用时记录
+// @Description 按开始时间倒序返回待办的计时和手动记录
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param todo_id query int true "待办ID"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "entries": [...]})
+// @Failure 400 {object} ErrorResponse "无效的todo_id"
+// @Failure 403 {object} ErrorResponse "无权查看该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/entries [get]
+func (h *TimeTrackingHandler) ListTimeEntriesHandler(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Query("todo_id"), 10, 32)
+	if err != nil || todoID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的todo_id",
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	entries, err := h.timeTrackingService.ListTodoEntries(userID, uint(todoID))
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"entries": entries,
+	})
+}
+
+// DeleteTimeEntryHandler 删除用时记录
+// @Summary 删除用时记录
+// @Description 删除一条已停止的用时记录，正在计时的记录需先停止
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "用时记录ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 400 {object} ErrorResponse "无效的ID格式"
+// @Failure 404 {object} ErrorResponse "用时记录不存在或正在计时"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/entries/{id} [delete]
+func (h *TimeTrackingHandler) DeleteTimeEntryHandler(c *gin.Context) {
+	id, ok := parseLabelID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.timeTrackingService.DeleteEntry(userID, id); err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "用时记录已删除",
+	})
+}
+
+// GetTimeSummaryHandler 用时汇总
+// @Summary 用时汇总
+// @Description 按待办、分类或日期汇总一段时间内的实际用时。日期按用户时区计算，to当天包含在内，均不传时统计本周；跨天的记录按日期拆分
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD（包含）"
+// @Param group_by query string false "分组方式：todo（默认）/category/day"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "summary": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/summary [get]
+func (h *TimeTrackingHandler) GetTimeSummaryHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	summary, err := h.timeTrackingService.GetTimeSummary(userID, c.Query("from"), c.Query("to"), c.Query("group_by"))
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"summary": summary,
+	})
+}
+
+// GetTimeReportHandler 预计与实际用时对比
+// @Summary 预计与实际用时对比
+// @Description 对区间内开始的待办（不含已取消和重复待办的抽象待办），比较计划时长（结束时间减开始时间）与实际记录的用时
+// @Tags 用时记录
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD（包含）"
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "report": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/time/report [get]
+func (h *TimeTrackingHandler) GetTimeReportHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	report, err := h.timeTrackingService.GetTimeReport(userID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(timeTrackingErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"report":  report,
+	})
+}
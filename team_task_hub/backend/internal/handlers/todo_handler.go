@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"team_task_hub/backend/internal/models"
 	"team_task_hub/backend/internal/services"
@@ -26,11 +31,12 @@ func NewTodoHandler(todoService *services.TodoService) *TodoHandler {
 type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty" example:"ILLEGAL_TRANSITION"`
 }
 
 // CreateTodo 创建待办事项
 // @Summary 创建待办事项
-// @Description 创建普通待办或重复待办事项。重复待办会根据规则及ChildDates生成子实例。
+// @Description 创建普通待办或重复待办事项。重复待办优先使用ChildDates生成子实例，未提供时由服务端根据rrule（RFC 5545）或repeat_type展开实例。创建前检查与未完成待办和已参加活动的时间冲突（重复待办只检查首次出现），冲突列表随响应返回；conflict_policy=block时存在冲突返回409且不创建。
 // @Tags 待办事项
 // @Accept json
 // @Produce json
@@ -39,6 +45,7 @@ type ErrorResponse struct {
 // @Param request body services.CreateTodoRequest true "创建待办请求"
 // @Success 201 {object} services.CreateTodoResponse "创建成功" example({"success": true, "message": "待办创建成功", "created_at": "2024-01-15T10:30:00Z"})
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "标题不能为空"})
+// @Failure 409 {object} services.CreateTodoResponse "存在时间冲突且conflict_policy=block"
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "创建待办失败: 数据库连接错误"})
 // @Router /api/todos/createTodo [post]
 func (h *TodoHandler) AddTodoHandler(c *gin.Context) {
@@ -74,8 +81,12 @@ func (h *TodoHandler) AddTodoHandler(c *gin.Context) {
 
 	// 调用服务层创建待办
 	response, err := h.todoService.CreateTodo(userIDUint, &req)
+	if errors.Is(err, services.ErrScheduleConflict) {
+		c.JSON(http.StatusConflict, response)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		c.JSON(todoErrorStatus(err), ErrorResponse{
 			Success: false,
 			Message: "创建待办失败: " + err.Error(),
 		})
@@ -160,7 +171,7 @@ type CancelTodoResponse struct {
 
 // CancelTodoByDetails 根据详情取消待办
 // @Summary 取消待办事项
-// @Description 根据待办的具体信息将其状态改为已取消
+// @Description 根据待办的具体信息将其状态改为已取消。已废弃，请使用 PATCH /api/todos/{id}
 // @Tags 待办事项
 // @Accept json
 // @Produce json
@@ -171,6 +182,7 @@ type CancelTodoResponse struct {
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "请求参数格式错误"})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "取消待办失败: 数据库错误"})
+// @Deprecated
 // @Router /api/todos/cancel [post]
 func (h *TodoHandler) CancelTodoByDetails(c *gin.Context) {
 	var req CancelTodoRequest
@@ -230,7 +242,7 @@ type CancelTodoAndChildrenRequest struct {
 
 // CancelTodoAndChildren 取消待办及其子待办
 // @Summary 取消待办及其所有子待办
-// @Description 根据待办标题和描述取消父待办及其所有子待办（使用事务确保一致性）
+// @Description 根据待办标题和描述取消父待办及其所有子待办（使用事务确保一致性）。已废弃，请使用 PATCH /api/todos/{id}
 // @Tags 待办事项
 // @Accept json
 // @Produce json
@@ -241,6 +253,7 @@ type CancelTodoAndChildrenRequest struct {
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "请求参数格式错误"})
 // @Failure 404 {object} string "未找到待办" example({"success": false, "message": "未找到匹配的待办事项"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "取消操作失败: 数据库错误"})
+// @Deprecated
 // @Router /api/todos/cancel-with-children [post]
 func (h *TodoHandler) CancelTodoAndChildren(c *gin.Context) {
 	var req CancelTodoAndChildrenRequest
@@ -317,7 +330,7 @@ type CompleteTodoResponse struct {
 
 // CompleteTodoByDetails 完成待办事项
 // @Summary 完成待办事项
-// @Description 根据待办的具体信息将其状态改为已完成，并自动记录完成时间
+// @Description 根据待办的具体信息将其状态改为已完成，并自动记录完成时间。已废弃，请使用 PATCH /api/todos/{id}
 // @Tags 待办事项
 // @Accept json
 // @Produce json
@@ -327,6 +340,7 @@ type CompleteTodoResponse struct {
 // @Success 200 {object} CompleteTodoResponse "完成成功" example({"success": true, "message": "待办已完成于 2024-01-15 14:30"})
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "标题和内容不能为空"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "完成待办失败: 数据库错误"})
+// @Deprecated
 // @Router /api/todos/complete [post]
 func (h *TodoHandler) CompleteTodoByDetails(c *gin.Context) {
 	var req CompleteTodoRequest
@@ -399,6 +413,8 @@ type TodoListResponse struct {
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} TodoListResponse "查询成功" example({"success": true, "message": "查询成功", "todos": [...], "count": 5})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "查询今日待办失败: 数据库错误"})
@@ -425,7 +441,7 @@ func (h *TodoHandler) GetTodayTodos(c *gin.Context) {
 	}
 
 	// 调用服务层获取今日待办
-	todos, err := h.todoService.GetTodayTodos(userIDUint)
+	todos, err := h.todoService.GetTodayTodos(userIDUint, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -459,6 +475,8 @@ type CompletedTodoResponse struct {
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
 // @Param date query string true "查询日期" example("2024-01-15")
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} CompletedTodoResponse "查询成功" example({"success": true, "message": "查询成功", "date": "2024-01-15", "todos": [...], "count": 3})
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "日期参数不能为空"})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
@@ -496,7 +514,7 @@ func (h *TodoHandler) GetCompletedTodosByDate(c *gin.Context) {
 	}
 
 	// 调用服务层获取指定日期完成的待办
-	todos, err := h.todoService.GetCompletedTodosByDate(userIDUint, dateStr)
+	todos, err := h.todoService.GetCompletedTodosByDate(userIDUint, dateStr, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -521,6 +539,8 @@ func (h *TodoHandler) GetCompletedTodosByDate(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} TodoListResponse "查询成功" example({"success": true, "message": "查询成功", "todos": [...], "count": 5})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "查询未来七天开始的待办失败: 数据库错误"})
@@ -547,7 +567,7 @@ func (h *TodoHandler) GetComingStartTodos(c *gin.Context) {
 	}
 
 	// 调用服务层获取未来七天会开始的待办
-	todos, err := h.todoService.GetTodosStartingInNext7Days(userIDUint)
+	todos, err := h.todoService.GetTodosStartingInNext7Days(userIDUint, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -571,6 +591,8 @@ func (h *TodoHandler) GetComingStartTodos(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} TodoListResponse "查询成功" example({"success": true, "message": "查询成功", "todos": [...], "count": 3})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "查询未来七天结束的待办失败: 数据库错误"})
@@ -597,7 +619,7 @@ func (h *TodoHandler) GetComingEndTodos(c *gin.Context) {
 	}
 
 	// 调用服务层获取未来七天会结束的待办
-	todos, err := h.todoService.GetTodosEndingInNext7Days(userIDUint)
+	todos, err := h.todoService.GetTodosEndingInNext7Days(userIDUint, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -630,7 +652,7 @@ type CancelCompletedTodoResponse struct {
 
 // CancelCompletedTodo 取消已完成待办
 // @Summary 取消已完成待办事项
-// @Description 将已完成的待办事项状态改为未完成
+// @Description 将已完成的待办事项状态改为未完成。已废弃，请使用 PATCH /api/todos/{id}
 // @Tags 待办事项
 // @Accept json
 // @Produce json
@@ -641,6 +663,7 @@ type CancelCompletedTodoResponse struct {
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "标题不能为空"})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
 // @Failure 500 {object} string "系统内部错误" example({"success": false, "message": "无法取消完成状态: 数据库错误"})
+// @Deprecated
 // @Router /api/todos/cancel-completedTodo [post]
 func (h *TodoHandler) CancelCompletedTodo(c *gin.Context) {
 	var req CancelCompletedTodoRequest
@@ -685,6 +708,8 @@ func (h *TodoHandler) CancelCompletedTodo(c *gin.Context) {
 			strings.Contains(errorMsg, "不能晚于") ||
 			strings.Contains(errorMsg, "格式错误") {
 			statusCode = http.StatusBadRequest
+		} else if errors.Is(err, services.ErrIllegalTransition) {
+			statusCode = http.StatusConflict
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -708,6 +733,8 @@ func (h *TodoHandler) CancelCompletedTodo(c *gin.Context) {
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
 // @Param date query string true "查询日期" example("2024-01-15")
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} TodoListResponse "查询成功" example({"success": true, "message": "查询成功", "date": "2024-01-15", "todos": [...], "count": 5})
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "日期参数不能为空"})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
@@ -745,7 +772,7 @@ func (h *TodoHandler) GetOneDayTodos(c *gin.Context) {
 	}
 
 	// 调用服务层获取指定日期开始的待办
-	todos, err := h.todoService.GetOneDayTodos(userIDUint, dateStr)
+	todos, err := h.todoService.GetOneDayTodos(userIDUint, dateStr, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -771,6 +798,8 @@ func (h *TodoHandler) GetOneDayTodos(c *gin.Context) {
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer Token" default(Bearer )
 // @Param date query string true "查询日期" example("2024-01-15")
+// @Param tags query string false "按标签筛选，多个标签用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param category query string false "按分类名称筛选" example("work")
 // @Success 200 {object} string "查询成功" example({"success": true, "message": "查询成功", "date": "2024-01-15", "todos": [...], "count": 3})
 // @Failure 400 {object} string "请求参数错误" example({"success": false, "message": "日期参数不能为空"})
 // @Failure 401 {object} string "未授权" example({"success": false, "message": "用户未认证"})
@@ -808,7 +837,7 @@ func (h *TodoHandler) GetOneDayExpiredTodos(c *gin.Context) {
 	}
 
 	// 调用服务层获取指定日期过期的待办
-	todos, err := h.todoService.GetOneDayExpiredTodos(userIDUint, dateStr)
+	todos, err := h.todoService.GetOneDayExpiredTodos(userIDUint, dateStr, parseTodoListFilter(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -826,6 +855,850 @@ func (h *TodoHandler) GetOneDayExpiredTodos(c *gin.Context) {
 	})
 }
 
+// parseTodoID 从路径参数中解析待办ID，解析失败时直接写入400响应
+func parseTodoID(c *gin.Context) (uint, bool) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || todoID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "无效的待办ID格式",
+		})
+		return 0, false
+	}
+	return uint(todoID), true
+}
+
+// parseTodoListFilter 从查询参数解析待办列表的筛选条件
+func parseTodoListFilter(c *gin.Context) services.TodoListFilter {
+	return services.ParseTodoListFilter(c.Query("tags"), c.Query("category"))
+}
+
+// todoErrorStatus 根据服务层返回的错误确定HTTP状态码
+func todoErrorStatus(err error) int {
+	status, _ := todoErrorInfo(err)
+	return status
+}
+
+// todoErrorInfo 根据服务层返回的错误确定HTTP状态码和错误码
+func todoErrorInfo(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound):
+		return http.StatusNotFound, "TODO_NOT_FOUND"
+	case errors.Is(err, services.ErrChecklistItemNotFound):
+		return http.StatusNotFound, "CHECKLIST_ITEM_NOT_FOUND"
+	case errors.Is(err, services.ErrBoardNotFound):
+		return http.StatusNotFound, "BOARD_NOT_FOUND"
+	case errors.Is(err, services.ErrTemplateNotFound):
+		return http.StatusNotFound, "TEMPLATE_NOT_FOUND"
+	case errors.Is(err, services.ErrTemplateForbidden):
+		return http.StatusForbidden, "TEMPLATE_FORBIDDEN"
+	case errors.Is(err, services.ErrTodoForbidden):
+		return http.StatusForbidden, "TODO_FORBIDDEN"
+	case errors.Is(err, services.ErrInvalidTodoParams):
+		return http.StatusBadRequest, "INVALID_PARAMS"
+	case errors.Is(err, services.ErrIllegalTransition):
+		return http.StatusConflict, "ILLEGAL_TRANSITION"
+	case errors.Is(err, services.ErrTodoBlocked):
+		return http.StatusConflict, "TODO_BLOCKED"
+	case errors.Is(err, services.ErrDependencyCycle):
+		return http.StatusConflict, "DEPENDENCY_CYCLE"
+	case errors.Is(err, services.ErrDependencyExists):
+		return http.StatusConflict, "DEPENDENCY_EXISTS"
+	case errors.Is(err, services.ErrTodoParentTrashed):
+		return http.StatusConflict, "PARENT_IN_TRASH"
+	case errors.Is(err, services.ErrNothingToUndo):
+		return http.StatusNotFound, "NOTHING_TO_UNDO"
+	case errors.Is(err, services.ErrUndoConflict):
+		return http.StatusConflict, "UNDO_CONFLICT"
+	case errors.Is(err, services.ErrScheduleConflict):
+		return http.StatusConflict, "SCHEDULE_CONFLICT"
+	default:
+		return http.StatusInternalServerError, "INTERNAL_ERROR"
+	}
+}
+
+// respondTodoError 写入统一格式的待办错误响应
+func respondTodoError(c *gin.Context, err error) {
+	status, code := todoErrorInfo(err)
+	c.JSON(status, ErrorResponse{
+		Success: false,
+		Message: err.Error(),
+		Code:    code,
+	})
+}
+
+// maxImportFileSize 导入文件的大小上限
+const maxImportFileSize = 2 << 20
+
+// ImportTodosResponse 导入待办响应结构
+type ImportTodosResponse struct {
+	Success bool                   `json:"success"`
+	Message string                 `json:"message"`
+	Report  *services.ImportReport `json:"report"`
+}
+
+// ImportTodosHandler 导入待办
+// @Summary 从ICS或CSV导入待办
+// @Description 上传.ics（VEVENT/VTODO，支持RRULE）或.csv文件批量创建待办。每个条目按创建待办的规则校验，并与已有待办（标题、描述、开始和结束时间均相同）去重。dry_run=true时只返回报告不创建；否则在一个事务中创建全部校验通过的条目。CSV首行为表头，列：title, description, start_time, end_time, urgency, category, tags, rrule, exdates，tags和exdates的多个值用分号分隔
+// @Tags 待办事项
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param file formData file true "导入文件，最大2MB"
+// @Param format formData string false "文件格式：ics或csv，默认根据文件扩展名判断"
+// @Param dry_run formData bool false "试运行，只校验不创建"
+// @Success 200 {object} ImportTodosResponse "导入完成"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/import [post]
+func (h *TodoHandler) ImportTodosHandler(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请上传导入文件",
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "导入文件不能超过2MB",
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.PostForm("format")))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+	dryRun := c.PostForm("dry_run") == "true" || c.Query("dry_run") == "true"
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondTodoError(c, fmt.Errorf("读取导入文件失败: %v", err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondTodoError(c, fmt.Errorf("读取导入文件失败: %v", err))
+		return
+	}
+
+	userID := c.GetUint("userID")
+	report, err := h.todoService.ImportTodos(userID, format, data, dryRun)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	message := fmt.Sprintf("导入完成：创建 %d 条，重复 %d 条，失败 %d 条", report.Created, report.Duplicates, report.Invalid)
+	if dryRun {
+		message = fmt.Sprintf("试运行完成：可导入 %d 条，重复 %d 条，失败 %d 条", report.Valid, report.Duplicates, report.Invalid)
+	}
+	c.JSON(http.StatusOK, ImportTodosResponse{
+		Success: true,
+		Message: message,
+		Report:  report,
+	})
+}
+
+// QuickAddTodoResponse 快速添加待办响应结构
+type QuickAddTodoResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Result  *services.QuickAddResult `json:"result"`
+}
+
+// QuickAddTodoHandler 一句话快速添加待办
+// @Summary 一句话快速添加待办
+// @Description 从一句中文或英文中识别标题、日期、时间段、时长、紧急程度、重复规则和#标签，如“每周三下午3点 小组会议 高优先级”或“review PR tomorrow 10am for 1h #work”。#标签与已有分类同名时作为分类，其余作为标签。preview=true时只返回解析出的创建请求供确认，确认或修改后可提交到创建待办接口；否则直接创建
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.QuickAddRequest true "快速添加内容"
+// @Success 200 {object} QuickAddTodoResponse "解析或创建成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误或无法识别"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/quick-add [post]
+func (h *TodoHandler) QuickAddTodoHandler(c *gin.Context) {
+	var req services.QuickAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	userID := c.GetUint("userID")
+	result, err := h.todoService.QuickAddTodo(userID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	message := "待办创建成功"
+	if req.Preview {
+		message = "解析成功，请确认后创建"
+	}
+	c.JSON(http.StatusOK, QuickAddTodoResponse{
+		Success: true,
+		Message: message,
+		Result:  result,
+	})
+}
+
+// CheckConflictsQuery 时间冲突查询参数
+type CheckConflictsQuery struct {
+	StartTime time.Time `form:"start_time" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime   time.Time `form:"end_time" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// CheckConflictsResponse 时间冲突查询响应结构
+type CheckConflictsResponse struct {
+	Success   bool                        `json:"success"`
+	Message   string                      `json:"message"`
+	Conflicts []services.ScheduleConflict `json:"conflicts"`
+}
+
+// CheckConflictsHandler 查询时间冲突
+// @Summary 查询时间段内的日程冲突
+// @Description 返回与给定时间段重叠的未完成待办实例（待处理和进行中）以及已参加且尚未完成的活动，首尾相接不算冲突。可在创建待办或参加活动前调用
+// @Tags 待办查询
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param start_time query string true "开始时间，RFC3339"
+// @Param end_time query string true "结束时间，RFC3339"
+// @Success 200 {object} CheckConflictsResponse "查询成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/conflicts [get]
+func (h *TodoHandler) CheckConflictsHandler(c *gin.Context) {
+	var query CheckConflictsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	userID := c.GetUint("userID")
+	conflicts, err := h.todoService.CheckConflicts(userID, query.StartTime, query.EndTime)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	message := "没有时间冲突"
+	if len(conflicts) > 0 {
+		message = fmt.Sprintf("存在 %d 项时间冲突", len(conflicts))
+	}
+	c.JSON(http.StatusOK, CheckConflictsResponse{
+		Success:   true,
+		Message:   message,
+		Conflicts: conflicts,
+	})
+}
+
+// TodoPageResponse 分页待办列表响应结构
+type TodoPageResponse struct {
+	Success    bool          `json:"success"`
+	Message    string        `json:"message"`
+	Todos      []models.Todo `json:"todos"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// ListTodosHandler 按条件查询待办
+// @Summary 按条件查询待办
+// @Description 按状态、紧急程度、分类、标签、时间范围、所属重复待办和关键字筛选当前用户的待办，支持排序和游标分页。翻页时把上一页返回的next_cursor原样传入cursor，排序参数需保持一致
+// @Tags 待办查询
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param status query string false "状态，多个用逗号分隔：pending,in_progress,completed,cancelled" example("pending,in_progress")
+// @Param urgency query string false "紧急程度，多个用逗号分隔：low,medium,high" example("high")
+// @Param category query string false "分类名称，多个用逗号分隔" example("work")
+// @Param tags query string false "标签名称，多个用逗号分隔，命中任意一个即可" example("会议,重要")
+// @Param parent_id query int false "只查询某个重复待办的实例"
+// @Param include_series query bool false "是否包含重复待办的抽象待办，默认不包含"
+// @Param overdue query bool false "按逾期标记筛选"
+// @Param q query string false "在标题和描述中搜索"
+// @Param start_from query string false "开始时间下限（含），YYYY-MM-DD或RFC3339"
+// @Param start_to query string false "开始时间上限（不含，日期格式包含当天）"
+// @Param end_from query string false "结束时间下限（含）"
+// @Param end_to query string false "结束时间上限（不含，日期格式包含当天）"
+// @Param completed_from query string false "完成时间下限（含）"
+// @Param completed_to query string false "完成时间上限（不含，日期格式包含当天）"
+// @Param sort query string false "排序字段：start_time（默认）,end_time,created_at,completed_at"
+// @Param order query string false "排序方向：asc（默认）,desc"
+// @Param cursor query string false "分页游标"
+// @Param limit query int false "每页条数，默认20，最大100"
+// @Success 200 {object} TodoPageResponse "查询成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos [get]
+func (h *TodoHandler) ListTodosHandler(c *gin.Context) {
+	var req services.TodoQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	page, err := h.todoService.QueryTodos(userID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TodoPageResponse{
+		Success:    true,
+		Message:    "查询成功",
+		Todos:      page.Todos,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	})
+}
+
+// AgendaResponse 日程响应结构
+type AgendaResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Agenda  *services.Agenda `json:"agenda"`
+}
+
+// GetAgendaHandler 查询个人日程
+// @Summary 查询个人日程
+// @Description 按时间顺序合并当前用户的个人待办实例和参与的组织活动，返回统一结构的条目（type区分todo和activity），默认不包含已完成和已取消的条目。from和to为用户时区下的日期（均包含），默认本周，最长366天。group_by为day（默认）、week（周一开始）、month时返回包括空分组在内的连续分组，跨越多个分组的条目在每个分组中都会出现；为none时只返回items
+// @Tags 待办查询
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param from query string false "起始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param group_by query string false "分组方式：day、week、month、none"
+// @Param types query string false "条目类型，逗号分隔：todo,activity"
+// @Param include_completed query bool false "包含已完成的待办和活动"
+// @Success 200 {object} AgendaResponse "查询成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/agenda [get]
+func (h *TodoHandler) GetAgendaHandler(c *gin.Context) {
+	var req services.AgendaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "请求参数错误: " + err.Error(),
+			Code:    "INVALID_PARAMS",
+		})
+		return
+	}
+
+	userID := c.GetUint("userID")
+	agenda, err := h.todoService.GetAgenda(userID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AgendaResponse{
+		Success: true,
+		Message: "查询成功",
+		Agenda:  agenda,
+	})
+}
+
+// TodoDetailResponse 单个待办响应结构
+type TodoDetailResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Todo    models.Todo `json:"todo"`
+}
+
+// GetTodoHandler 根据ID获取待办
+// @Summary 获取待办详情
+// @Description 根据待办ID获取当前用户的待办详情
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} TodoDetailResponse "查询成功"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id} [get]
+func (h *TodoHandler) GetTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.GetTodoDetail(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "查询成功",
+		"todo":    todo,
+	})
+}
+
+// ReplaceTodoHandler 全量更新待办
+// @Summary 全量更新待办
+// @Description 使用请求体中的全部字段覆盖待办的可编辑字段
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.UpdateTodoRequest true "全量更新请求"
+// @Success 200 {object} TodoDetailResponse "更新成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 409 {object} ErrorResponse "非法的状态转换"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id} [put]
+func (h *TodoHandler) ReplaceTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.ReplaceTodo(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办更新成功",
+		"todo":    todo,
+	})
+}
+
+// PatchTodoHandler 部分更新待办
+// @Summary 部分更新待办
+// @Description 只更新请求体中提供的字段，可用于完成、取消、恢复待办或修改任意可编辑字段；取消抽象待办会同时取消其未完成的实例。completed_at只能用于已完成的待办；重复规则字段只能在抽象待办上修改，编辑系列中的实例请使用 PATCH /api/todos/{id}/edit
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.PatchTodoRequest true "部分更新请求"
+// @Success 200 {object} TodoDetailResponse "更新成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 409 {object} ErrorResponse "非法的状态转换"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id} [patch]
+func (h *TodoHandler) PatchTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.PatchTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.PatchTodo(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办更新成功",
+		"todo":    todo,
+	})
+}
+
+// StartTodoHandler 开始待办
+// @Summary 开始待办
+// @Description 将待办状态从 pending 变为 in_progress 并记录开始执行时间（started_at），暂停后再次开始保留首次开始时间，重新打开已完成或已取消的待办时清空。待办状态遵循状态机：pending → in_progress → completed，已完成或已取消的待办可重新打开为 pending；非法转换返回 409 与错误码 ILLEGAL_TRANSITION
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} TodoDetailResponse "已开始"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 409 {object} ErrorResponse "非法的状态转换"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/start [post]
+func (h *TodoHandler) StartTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.StartTodo(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办已开始",
+		"todo":    todo,
+	})
+}
+
+// SnoozeTodoHandler 推迟待办
+// @Summary 推迟待办
+// @Description 将待开始或进行中的待办整体后移，持续时长不变。duration 按时长推迟（如 30m、2h、1d，按天推迟时保持本地钟点），date 推迟到指定日期（YYYY-MM-DD，保持本地钟点），二者只能提供一个。首次推迟时记录原定时间（original_start_time/original_end_time），每次推迟累加 snooze_count；重复实例按原定时间续期，推迟不影响系列中的其他实例。推迟可通过撤销接口恢复
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.SnoozeTodoRequest true "推迟方式"
+// @Success 200 {object} TodoDetailResponse "推迟成功"
+// @Failure 400 {object} ErrorResponse "请求参数错误或待办状态不允许推迟"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/snooze [post]
+func (h *TodoHandler) SnoozeTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	var req services.SnoozeTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.SnoozeTodo(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办已推迟",
+		"todo":    todo,
+	})
+}
+
+// EditTodoHandler 按范围编辑待办
+// @Summary 编辑待办（支持重复待办的编辑范围）
+// @Description 修改待办的标题、内容、紧急程度、分类和时间。对重复待办，scope=this 只改当前实例（记录原定时间，续期仍按原定时间推算），scope=following 将系列从当前实例处拆分为新系列并修改当前及之后的实例，scope=all 改整个系列；已完成的实例不受影响。整体跨天调整时间时BYDAY随之平移，带序号的BYDAY或含BYMONTHDAY、BYMONTH、BYSETPOS的规则不能跨天调整
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param request body services.EditTodoRequest true "编辑请求"
+// @Success 200 {object} SuccessResponse "编辑成功" example({"success": true, "message": "编辑成功", "edited_count": 4})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/edit [patch]
+func (h *TodoHandler) EditTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+
+	var req services.EditTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	editedCount, err := h.todoService.EditTodo(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "编辑成功",
+		"edited_count": editedCount,
+	})
+}
+
+// DeleteTodoHandler 删除待办
+// @Summary 删除待办
+// @Description 根据ID将待办移入回收站，抽象待办会连同其所有实例一起移入，保留期内可恢复
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id} [delete]
+func (h *TodoHandler) DeleteTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.todoService.DeleteTodo(userID, todoID); err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办已移入回收站",
+	})
+}
+
+// TodoHistoryResponse 变更历史响应结构
+type TodoHistoryResponse struct {
+	Success bool                      `json:"success"`
+	Message string                    `json:"message"`
+	Data    *services.TodoHistoryPage `json:"data"`
+}
+
+// GetTodoHistoryHandler 获取待办的变更历史
+// @Summary 获取待办变更历史
+// @Description 按时间倒序返回待办的变更集，包括批量取消、编辑系列等操作中对该待办的修改；每个变更集包含操作人、时间和各字段修改前后的值
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Param before query int false "上一页返回的 next_before"
+// @Param limit query int false "每页条数，默认20，最大100"
+// @Success 200 {object} TodoHistoryResponse "查询成功"
+// @Failure 400 {object} ErrorResponse "参数错误"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/history [get]
+func (h *TodoHandler) GetTodoHistoryHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	var req services.TodoHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	page, err := h.todoService.GetTodoHistory(userID, todoID, &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TodoHistoryResponse{
+		Success: true,
+		Message: "查询成功",
+		Data:    page,
+	})
+}
+
+// UndoTodoHandler 撤销待办最近一次变更
+// @Summary 撤销最近一次变更
+// @Description 撤销与待办相关的最近一次变更集，批量操作会整体撤销；相关字段在此之后又被修改过时返回409
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} map[string]interface{} "撤销成功，change_set 为撤销操作本身的变更集"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "待办不存在或没有可撤销的变更"
+// @Failure 409 {object} ErrorResponse "变更之后已被修改，无法撤销"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/undo [post]
+func (h *TodoHandler) UndoTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	changeSet, err := h.todoService.UndoLastChange(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"message":    "已撤销",
+		"change_set": changeSet,
+	})
+}
+
+// TrashResponse 回收站列表响应结构
+type TrashResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Todos   []models.Todo `json:"todos"`
+}
+
+// ListTrashHandler 获取回收站中的待办
+// @Summary 获取回收站
+// @Description 获取已删除但尚未彻底删除的待办，最近删除的在前；抽象待办的实例随抽象待办一起显示，不单独列出
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} TrashResponse "查询成功"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/trash [get]
+func (h *TodoHandler) ListTrashHandler(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	todos, err := h.todoService.ListTrash(userID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TrashResponse{
+		Success: true,
+		Message: "查询成功",
+		Todos:   todos,
+	})
+}
+
+// RestoreTodoHandler 从回收站恢复待办
+// @Summary 恢复待办
+// @Description 从回收站恢复待办，抽象待办会连同与它一起删除的实例一起恢复；所属重复待办仍在回收站中的实例无法单独恢复
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} TodoDetailResponse "恢复成功"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "回收站中不存在该待办"
+// @Failure 409 {object} ErrorResponse "所属重复待办在回收站中"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/{id}/restore [post]
+func (h *TodoHandler) RestoreTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	todo, err := h.todoService.RestoreTodo(userID, todoID)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办已恢复",
+		"todo":    todo,
+	})
+}
+
+// PurgeTodoHandler 彻底删除回收站中的待办
+// @Summary 彻底删除待办
+// @Description 彻底删除回收站中的待办及其清单、标签、依赖关系、用时记录、提醒和变更历史，删除后无法恢复
+// @Tags 待办事项
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param id path int true "待办ID"
+// @Success 200 {object} SuccessResponse "删除成功"
+// @Failure 400 {object} ErrorResponse "无效的待办ID格式"
+// @Failure 403 {object} ErrorResponse "无权操作该待办"
+// @Failure 404 {object} ErrorResponse "回收站中不存在该待办"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/trash/{id} [delete]
+func (h *TodoHandler) PurgeTodoHandler(c *gin.Context) {
+	todoID, ok := parseTodoID(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.todoService.PurgeTodo(userID, todoID); err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "待办已彻底删除",
+	})
+}
+
 // GetTodayOrganizationTodos 获取今日有活动的组织列表
 // @Summary 获取今日有活动的组织列表
 // @Description 查询用户今日有待办活动的组织列表
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateFlexibleTodoHandler 创建弹性待办
+// @Summary 创建弹性待办
+// @Description 只指定时长和截止时间，由自动排程安排到截止前最早的空闲工作时段（避开已有待办和参与的活动，全天和跨多天的待办不占用时段）。截止前没有足够空闲时段时待办标记为unplaced，之后有空闲时会自动重新安排。手动修改时间或推迟后转为固定待办
+// @Tags 待办自动排程
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.CreateFlexibleTodoRequest true "弹性待办内容"
+// @Success 201 {object} SuccessResponse "创建成功" example({"success": true, "message": "创建成功", "todo": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/flexible [post]
+func (h *TodoHandler) CreateFlexibleTodoHandler(c *gin.Context) {
+	var req services.CreateFlexibleTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	todo, err := h.todoService.CreateFlexibleTodo(c.GetUint("userID"), &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "创建成功",
+		"todo":    todo,
+	})
+}
+
+// ReplanFlexibleTodosHandler 重新安排弹性待办
+// @Summary 重新安排弹性待办
+// @Description 立即对当前用户的弹性待办执行自动排程。默认只移动失效的安排（与其他日程冲突、超出工作时间或已过开始时间）并尝试安排unplaced的待办；full=true时按紧急程度和截止时间重新安排全部未开始的弹性待办
+// @Tags 待办自动排程
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param full query bool false "是否全部重新安排" default(false)
+// @Success 200 {object} SuccessResponse "排程完成" example({"success": true, "message": "排程完成", "result": {"kept": 2, "placed": [...], "unplaced": []}})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/flexible/replan [post]
+func (h *TodoHandler) ReplanFlexibleTodosHandler(c *gin.Context) {
+	full := c.Query("full") == "true"
+
+	result, err := h.todoService.ReplanFlexibleTodos(c.GetUint("userID"), full)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "排程完成",
+		"result":  result,
+	})
+}
+
+// GetSchedulePreferenceHandler 获取排程偏好
+// @Summary 获取排程偏好
+// @Description 返回自动排程使用的工作时间和工作日，未设置时返回默认值（周一至周五 9:00-18:00）
+// @Tags 待办自动排程
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Success 200 {object} SuccessResponse "查询成功" example({"success": true, "message": "查询成功", "preference": {...}})
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/schedule-preferences [get]
+func (h *TodoHandler) GetSchedulePreferenceHandler(c *gin.Context) {
+	pref, err := h.todoService.GetSchedulePreference(c.GetUint("userID"))
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"message":    "查询成功",
+		"preference": pref,
+	})
+}
+
+// UpdateSchedulePreferenceHandler 更新排程偏好
+// @Summary 更新排程偏好
+// @Description 设置自动排程使用的每日工作时间（0点起的分钟数）和工作日（0表示周日），保存后立即重新检查弹性待办的安排
+// @Tags 待办自动排程
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Authorization header string true "Bearer Token" default(Bearer )
+// @Param request body services.SchedulePreferenceRequest true "排程偏好"
+// @Success 200 {object} SuccessResponse "更新成功" example({"success": true, "message": "更新成功", "preference": {...}})
+// @Failure 400 {object} ErrorResponse "请求参数错误"
+// @Failure 500 {object} ErrorResponse "系统内部错误"
+// @Router /api/todos/schedule-preferences [put]
+func (h *TodoHandler) UpdateSchedulePreferenceHandler(c *gin.Context) {
+	var req services.SchedulePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	pref, err := h.todoService.UpdateSchedulePreference(c.GetUint("userID"), &req)
+	if err != nil {
+		respondTodoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"message":    "更新成功",
+		"preference": pref,
+	})
+}
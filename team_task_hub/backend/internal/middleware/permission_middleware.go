@@ -159,6 +159,54 @@ func RequireOrganizationMember(orgService *services.OrganizationService) gin.Han
 	}
 }
 
+// RequireOrganizationPermission 要求用户拥有组织的某项权限（创建者和管理员默认拥有）
+func RequireOrganizationPermission(orgService *services.OrganizationService, permissionType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "用户未认证",
+			})
+			c.Abort()
+			return
+		}
+
+		// 从URL参数获取组织ID
+		orgID, err := getOrganizationIDFromParam(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		// 验证用户是否拥有该权限
+		allowed, err := orgService.HasPermission(orgID, userID.(uint), permissionType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "权限验证失败: " + err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "权限不足：需要" + permissionType + "权限",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // getOrganizationIDFromParam 从URL参数中获取组织ID
 func getOrganizationIDFromParam(c *gin.Context) (uint, error) {
 	orgIDStr := c.Param("orgID")
@@ -184,6 +232,11 @@ func WithAuth(authService *services.AuthService, permissionMiddleware gin.Handle
 	}
 }
 
+// CreatePermissionChain 创建要求组织权限的认证链
+func CreatePermissionChain(authService *services.AuthService, orgService *services.OrganizationService, permissionType string) []gin.HandlerFunc {
+	return WithAuth(authService, RequireOrganizationPermission(orgService, permissionType))
+}
+
 // CreateAuthChain 创建认证链的函数
 func CreateAuthChain(authService *services.AuthService, orgService *services.OrganizationService, permissionLevel string) []gin.HandlerFunc {
 	switch permissionLevel {
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// 看板的分列方式
+const (
+	BoardGroupByStatus = "status" // 每列对应一个待办状态，移动即转换状态
+	BoardGroupByTag    = "tag"    // 每列对应一个标签，移动即替换看板上的标签
+)
+
+// Board 用户的待办看板，列按状态或标签划分
+type Board struct {
+	ID        uint          `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID    uint          `gorm:"not null;type:BIGINT UNSIGNED;index" json:"user_id"`
+	Name      string        `gorm:"size:100;not null" json:"name"`
+	GroupBy   string        `gorm:"type:ENUM('status','tag');default:'status'" json:"group_by"`
+	IsDefault bool          `gorm:"default:false" json:"is_default"`
+	CreatedAt time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	Columns   []BoardColumn `gorm:"foreignKey:BoardID" json:"columns,omitempty"`
+}
+
+func (Board) TableName() string {
+	return "boards"
+}
+
+// BoardColumn 看板的一列，Status或TagID二选一，取决于看板的分列方式
+type BoardColumn struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	BoardID  uint   `gorm:"not null;type:BIGINT UNSIGNED;index" json:"board_id"`
+	Name     string `gorm:"size:50;not null" json:"name"`
+	Position int    `gorm:"not null;default:0" json:"position"`
+	Status   string `gorm:"size:20;not null;default:''" json:"status,omitempty"`
+	TagID    uint   `gorm:"not null;default:0;type:BIGINT UNSIGNED" json:"tag_id,omitempty"`
+	WIPLimit int    `gorm:"not null;default:0" json:"wip_limit"` // 在制品上限，0表示不限，仅用于提示
+}
+
+func (BoardColumn) TableName() string {
+	return "board_columns"
+}
+
+// BoardCard 待办在看板中的排序键，同一列内按Position的字节序排列
+type BoardCard struct {
+	BoardID   uint      `gorm:"primaryKey;type:BIGINT UNSIGNED" json:"board_id"`
+	TodoID    uint      `gorm:"primaryKey;type:BIGINT UNSIGNED;index" json:"todo_id"`
+	Position  string    `gorm:"type:VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin;not null" json:"position"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (BoardCard) TableName() string {
+	return "board_cards"
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// CalendarFeed 用户的日历订阅链接，持有token即可免登录读取日历
+type CalendarFeed struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID         uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex" json:"user_id"`
+	Token          string    `gorm:"size:64;not null;uniqueIndex" json:"token"`
+	LastAccessedAt time.Time `gorm:"default:'1900-01-01'" json:"last_accessed_at"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ChecklistItem 待办内的清单项（步骤）
+type ChecklistItem struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	TodoID    uint      `gorm:"not null;type:BIGINT UNSIGNED;index" json:"todo_id"`
+	Title     string    `gorm:"size:200;not null" json:"title"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	Done      bool      `gorm:"default:false" json:"done"`
+	DoneAt    time.Time `gorm:"default:'1900-01-01'" json:"done_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "checklist_items"
+}
+
+// ChecklistProgress 清单完成进度
+type ChecklistProgress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
+}
+
+// NewChecklistProgress 根据总数和已完成数计算进度，百分比向下取整
+func NewChecklistProgress(total, done int) ChecklistProgress {
+	progress := ChecklistProgress{Total: total, Done: done}
+	if total > 0 {
+		progress.Percent = done * 100 / total
+	}
+	return progress
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TodoDependency 待办之间的依赖关系：BlockerID 完成之前，BlockedID 不能开始
+type TodoDependency struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	BlockerID     uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_dependency_pair" json:"blocker_id"`
+	BlockedID     uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_dependency_pair;index" json:"blocked_id"`
+	CreatorUserID uint      `gorm:"not null;type:BIGINT UNSIGNED;index" json:"creator_user_id"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (TodoDependency) TableName() string {
+	return "todo_dependencies"
+}
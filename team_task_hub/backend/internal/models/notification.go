@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID    uint      `gorm:"not null;type:BIGINT UNSIGNED;index" json:"user_id"`
+	Type      string    `gorm:"size:50;not null" json:"type"`
+	Title     string    `gorm:"size:200;not null" json:"title"`
+	Content   string    `gorm:"type:text" json:"content"`
+	TodoID    uint      `gorm:"not null;default:0;type:BIGINT UNSIGNED" json:"todo_id,omitempty"`
+	IsUnread  bool      `gorm:"default:true;not null;index" json:"is_unread"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// 通知类型
+const (
+	NotificationTodoUnblocked = "todo_unblocked"
+	NotificationReminder      = "reminder"
+	NotificationTodoAssigned  = "todo_assigned"
+	NotificationTodoResponded = "todo_assignment_responded"
+)
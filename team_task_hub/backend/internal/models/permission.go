@@ -6,18 +6,27 @@ import (
 
 type Permission struct {
 	ID             uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
-	OrganizationID uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"organization_id"`
-	UserID         uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"user_id"`
-	PermissionType string    `gorm:"type:ENUM('publish_todo','delete_todo','manage_members','publish_activity','transfer_ownership');not null" json:"permission_type"`
+	OrganizationID uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_permission_org_user_type" json:"organization_id"`
+	UserID         uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_permission_org_user_type" json:"user_id"`
+	PermissionType string    `gorm:"type:ENUM('publish_todo','delete_todo','manage_members','publish_activity','transfer_ownership');not null;uniqueIndex:idx_permission_org_user_type" json:"permission_type"`
 	GrantedBy      uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"granted_by"`
 	GrantedAt      time.Time `json:"granted_at"`
 
 	// 简化外键约束
-	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization"`
+	Organization Organization `gorm:"foreignKey:OrganizationID" json:"-"`
 	User         User         `gorm:"foreignKey:UserID" json:"user"`
-	Granter      User         `gorm:"foreignKey:GrantedBy" json:"granter"`
+	Granter      User         `gorm:"foreignKey:GrantedBy" json:"-"`
 }
 
 func (Permission) TableName() string {
 	return "permissions"
 }
+
+// 组织权限类型，组织创建者和管理员默认拥有全部权限
+const (
+	PermissionPublishTodo       = "publish_todo"
+	PermissionDeleteTodo        = "delete_todo"
+	PermissionManageMembers     = "manage_members"
+	PermissionPublishActivity   = "publish_activity"
+	PermissionTransferOwnership = "transfer_ownership"
+)
@@ -0,0 +1,539 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrencePeriods 展开重复规则时最多遍历的周期数，防止规则写错导致死循环
+const maxRecurrencePeriods = 10000
+
+// RecurrenceRule RFC 5545 重复规则（RRULE）
+// 支持 FREQ、INTERVAL、COUNT、UNTIL、BYDAY、BYMONTHDAY、BYMONTH、BYSETPOS、WKST
+type RecurrenceRule struct {
+	Freq       string // DAILY / WEEKLY / MONTHLY / YEARLY
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// WeekdayNum BYDAY中的一项，例如 2TU 表示第二个周二，-1FR 表示最后一个周五，N为0表示每一个
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRRule 解析RRULE字符串，可带或不带 "RRULE:" 前缀
+// loc为系列所属时区，不带Z的UNTIL（浮动时间或纯日期）按该时区解释
+func ParseRRule(rule string, loc *time.Location) (*RecurrenceRule, error) {
+	rule = strings.TrimSpace(rule)
+	if len(rule) >= 6 && strings.EqualFold(rule[:6], "RRULE:") {
+		rule = rule[6:]
+	}
+	if rule == "" {
+		return nil, fmt.Errorf("重复规则不能为空")
+	}
+
+	r := &RecurrenceRule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("无效的规则片段 '%s'", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.Freq = value
+			default:
+				err = fmt.Errorf("不支持的频率 '%s'", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parsePositiveInt(value)
+		case "COUNT":
+			r.Count, err = parsePositiveInt(value)
+		case "UNTIL":
+			r.Until, err = parseRRuleTime(value, loc)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(value, -31, 31)
+		case "BYMONTH":
+			r.ByMonth, err = parseIntList(value, 1, 12)
+		case "BYSETPOS":
+			r.BySetPos, err = parseIntList(value, -366, 366)
+		case "WKST":
+			day, ok := weekdayCodes[value]
+			if !ok {
+				err = fmt.Errorf("无效的WKST '%s'", value)
+			}
+			r.WeekStart = day
+		default:
+			err = fmt.Errorf("不支持的规则项 '%s'", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("重复规则缺少FREQ")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT和UNTIL不能同时使用")
+	}
+	if len(r.BySetPos) > 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByMonth) == 0 {
+		return nil, fmt.Errorf("BYSETPOS必须与其他BY规则一起使用")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != "MONTHLY" && r.Freq != "YEARLY" {
+			return nil, fmt.Errorf("只有MONTHLY和YEARLY规则的BYDAY可以带序号")
+		}
+	}
+	return r, nil
+}
+
+// NewSimpleRule 根据旧的重复类型（daily/weekly/monthly/yearly）构造等价的重复规则
+func NewSimpleRule(repeatType string, interval int, until time.Time) (*RecurrenceRule, error) {
+	freq := strings.ToUpper(repeatType)
+	switch freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("不支持的重复类型 '%s'", repeatType)
+	}
+	if interval < 1 {
+		interval = 1
+	}
+	return &RecurrenceRule{Freq: freq, Interval: interval, Until: until, WeekStart: time.Monday}, nil
+}
+
+// RepeatType 返回与规则频率对应的待办重复类型
+func (r *RecurrenceRule) RepeatType() string {
+	return strings.ToLower(r.Freq)
+}
+
+// String 序列化为规范的RRULE字符串（不带前缀）
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			if wd.N != 0 {
+				days = append(days, strconv.Itoa(wd.N)+weekdayNames[wd.Day])
+			} else {
+				days = append(days, weekdayNames[wd.Day])
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// ResolveCount 将COUNT换算为最后一次出现的时间作为UNTIL
+// 这样系列中的任何一个实例都可以作为新的起点继续展开，而不必知道之前已经出现了多少次
+func (r *RecurrenceRule) ResolveCount(dtstart time.Time) {
+	if r.Count == 0 {
+		return
+	}
+	var last time.Time
+	r.iterate(dtstart, func(t time.Time) bool {
+		last = t
+		return true
+	})
+	r.Count = 0
+	if last.IsZero() {
+		last = dtstart
+	}
+	r.Until = last
+}
+
+// ShiftWeekdays 将BYDAY中的星期整体平移days天，用于跨天调整整个系列的时间
+// 带序号的BYDAY以及BYMONTHDAY、BYMONTH、BYSETPOS无法等价平移，此时返回错误且不修改规则
+func (r *RecurrenceRule) ShiftWeekdays(days int) error {
+	if days == 0 {
+		return nil
+	}
+	if len(r.ByMonthDay) > 0 || len(r.ByMonth) > 0 || len(r.BySetPos) > 0 {
+		return fmt.Errorf("规则包含BYMONTHDAY、BYMONTH或BYSETPOS，不能跨天平移")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 {
+			return fmt.Errorf("规则包含带序号的BYDAY，不能跨天平移")
+		}
+	}
+	for i := range r.ByDay {
+		r.ByDay[i].Day = time.Weekday(((int(r.ByDay[i].Day)+days)%7 + 7) % 7)
+	}
+	return nil
+}
+
+// Between 返回 [from, to] 内的所有出现时间，跳过被排除的日期，limit大于0时最多返回limit个
+func (r *RecurrenceRule) Between(dtstart, from, to time.Time, exdates map[string]bool, limit int) []time.Time {
+	var occurrences []time.Time
+	r.iterate(dtstart, func(t time.Time) bool {
+		if t.After(to) {
+			return false
+		}
+		if t.Before(from) || exdates[t.Format("2006-01-02")] {
+			return true
+		}
+		occurrences = append(occurrences, t)
+		return limit <= 0 || len(occurrences) < limit
+	})
+	return occurrences
+}
+
+// After 返回严格晚于after的第一次出现时间，跳过被排除的日期
+func (r *RecurrenceRule) After(dtstart, after time.Time, exdates map[string]bool) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.iterate(dtstart, func(t time.Time) bool {
+		if !t.After(after) || exdates[t.Format("2006-01-02")] {
+			return true
+		}
+		next, found = t, true
+		return false
+	})
+	return next, found
+}
+
+// iterate 按时间顺序依次产生出现时间，yield返回false时停止
+// COUNT按规则产生的实例计数（包括之后被EXDATE排除的实例），与RFC 5545一致
+func (r *RecurrenceRule) iterate(dtstart time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, t := range r.expandPeriod(dtstart, period) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+			emitted++
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// expandPeriod 展开第n个周期（按INTERVAL跳跃）内的所有候选时间，结果按时间升序排列
+func (r *RecurrenceRule) expandPeriod(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	y, m, d := dtstart.Date()
+	step := n * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case "DAILY":
+		day := time.Date(y, m, d+step, 0, 0, 0, 0, loc)
+		if r.matchMonth(day) && r.matchMonthDay(day) && r.matchWeekday(day) {
+			days = append(days, day)
+		}
+	case "WEEKLY":
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := time.Date(y, m, d-offset+7*step, 0, 0, 0, 0, loc)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if r.matchWeekday(day) && r.matchMonth(day) {
+				days = append(days, day)
+			}
+		}
+	case "MONTHLY":
+		first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, loc)
+		if r.matchMonth(first) {
+			days = r.monthDays(first.Year(), first.Month(), d, loc)
+		}
+	case "YEARLY":
+		year := y + step
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range r.ByMonth {
+				days = append(days, r.monthDays(year, time.Month(month), d, loc)...)
+			}
+		case len(r.ByMonthDay) > 0:
+			for month := time.January; month <= time.December; month++ {
+				days = append(days, r.monthDays(year, month, d, loc)...)
+			}
+		case len(r.ByDay) > 0:
+			days = r.weekdaysInRange(time.Date(year, 1, 1, 0, 0, 0, 0, loc), time.Date(year+1, 1, 1, 0, 0, 0, 0, loc))
+		default:
+			day := time.Date(year, m, d, 0, 0, 0, 0, loc)
+			if day.Month() == m {
+				days = append(days, day)
+			}
+		}
+	}
+
+	days = sortUniqueDays(days)
+	if len(r.BySetPos) > 0 {
+		days = applySetPos(days, r.BySetPos)
+	}
+
+	hour, minute, sec := dtstart.Clock()
+	occurrences := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		dy, dm, dd := day.Date()
+		occurrences = append(occurrences, time.Date(dy, dm, dd, hour, minute, sec, dtstart.Nanosecond(), loc))
+	}
+	return occurrences
+}
+
+// monthDays 根据BYMONTHDAY和BYDAY展开某个月中的日期，两者都为空时使用默认日期
+func (r *RecurrenceRule) monthDays(year int, month time.Month, defaultDay int, loc *time.Location) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+
+	var byMonthDay []time.Time
+	for _, md := range r.ByMonthDay {
+		day := md
+		if md < 0 {
+			day = daysInMonth + md + 1
+		}
+		if day >= 1 && day <= daysInMonth {
+			byMonthDay = append(byMonthDay, time.Date(year, month, day, 0, 0, 0, 0, loc))
+		}
+	}
+
+	var byDay []time.Time
+	if len(r.ByDay) > 0 {
+		byDay = r.weekdaysInRange(first, first.AddDate(0, 1, 0))
+	}
+
+	switch {
+	case len(r.ByMonthDay) > 0 && len(r.ByDay) > 0:
+		return intersectDays(byMonthDay, byDay)
+	case len(r.ByMonthDay) > 0:
+		return byMonthDay
+	case len(r.ByDay) > 0:
+		return byDay
+	default:
+		if defaultDay > daysInMonth {
+			return nil
+		}
+		return []time.Time{time.Date(year, month, defaultDay, 0, 0, 0, 0, loc)}
+	}
+}
+
+// weekdaysInRange 在 [start, end) 内展开BYDAY，带序号的项按该范围计算第N个或倒数第N个
+func (r *RecurrenceRule) weekdaysInRange(start, end time.Time) []time.Time {
+	var result []time.Time
+	for _, wd := range r.ByDay {
+		var matches []time.Time
+		for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == wd.Day {
+				matches = append(matches, day)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			result = append(result, matches...)
+		case wd.N > 0 && wd.N <= len(matches):
+			result = append(result, matches[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matches):
+			result = append(result, matches[len(matches)+wd.N])
+		}
+	}
+	return result
+}
+
+func (r *RecurrenceRule) matchMonth(day time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, month := range r.ByMonth {
+		if day.Month() == time.Month(month) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RecurrenceRule) matchMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, md := range r.ByMonthDay {
+		if md == day.Day() || md < 0 && daysInMonth+md+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RecurrenceRule) matchWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// applySetPos 按BYSETPOS从一个周期的候选集合中挑选实例
+func applySetPos(days []time.Time, positions []int) []time.Time {
+	var result []time.Time
+	for _, pos := range positions {
+		idx := pos - 1
+		if pos < 0 {
+			idx = len(days) + pos
+		}
+		if idx >= 0 && idx < len(days) {
+			result = append(result, days[idx])
+		}
+	}
+	return sortUniqueDays(result)
+}
+
+func intersectDays(a, b []time.Time) []time.Time {
+	set := make(map[int64]bool, len(b))
+	for _, day := range b {
+		set[day.Unix()] = true
+	}
+	var result []time.Time
+	for _, day := range a {
+		if set[day.Unix()] {
+			result = append(result, day)
+		}
+	}
+	return result
+}
+
+func sortUniqueDays(days []time.Time) []time.Time {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	var result []time.Time
+	for _, day := range days {
+		if len(result) == 0 || !day.Equal(result[len(result)-1]) {
+			result = append(result, day)
+		}
+	}
+	return result
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var result []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("无效的BYDAY '%s'", item)
+		}
+		day, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("无效的BYDAY '%s'", item)
+		}
+		wd := WeekdayNum{Day: day}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("无效的BYDAY序号 '%s'", item)
+			}
+			wd.N = n
+		}
+		result = append(result, wd)
+	}
+	return result, nil
+}
+
+func parseIntList(value string, min, max int) ([]int, error) {
+	var result []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("无效的取值 '%s'", item)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func parsePositiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("无效的正整数 '%s'", value)
+	}
+	return n, nil
+}
+
+// parseRRuleTime 解析UNTIL，支持UTC时间、本地时间和纯日期三种格式，后两种按loc解释
+func parseRRuleTime(value string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		// 纯日期表示包含当天，按日历天计算，跨越夏令时切换时仍是当天的最后一秒
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("无效的UNTIL '%s'", value)
+}
+
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
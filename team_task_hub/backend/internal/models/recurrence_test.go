@@ -0,0 +1,332 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 展开结果以本地时间 YYYYMMDDTHHMMSS 表示，便于与 RFC 5545 中的示例对照
+const rruleLayout = "20060102T150405"
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("加载时区 %s 失败: %v", name, err)
+	}
+	return loc
+}
+
+func expandRule(t *testing.T, rrule, dtstart string, loc *time.Location, limit int) []string {
+	t.Helper()
+	rule, err := ParseRRule(rrule, loc)
+	if err != nil {
+		t.Fatalf("ParseRRule(%q) error: %v", rrule, err)
+	}
+	start, err := time.ParseInLocation(rruleLayout, dtstart, loc)
+	if err != nil {
+		t.Fatalf("无效的DTSTART %q: %v", dtstart, err)
+	}
+	far := start.AddDate(50, 0, 0)
+	var result []string
+	for _, occurrence := range rule.Between(start, start, far, nil, limit) {
+		result = append(result, occurrence.In(loc).Format(rruleLayout))
+	}
+	return result
+}
+
+// TestRecurrenceRFC5545Examples RFC 5545 第3.8.5.3节中的示例，DTSTART均为America/New_York时区
+func TestRecurrenceRFC5545Examples(t *testing.T) {
+	ny := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		rrule   string
+		dtstart string
+		limit   int
+		want    string
+	}{
+		{
+			name:    "每天，共10次",
+			rrule:   "FREQ=DAILY;COUNT=10",
+			dtstart: "19970902T090000",
+			want:    "0902 0903 0904 0905 0906 0907 0908 0909 0910 0911",
+		},
+		{
+			name:    "隔天一次",
+			rrule:   "FREQ=DAILY;INTERVAL=2",
+			dtstart: "19970902T090000",
+			limit:   5,
+			want:    "0902 0904 0906 0908 0910",
+		},
+		{
+			name:    "每10天，共5次",
+			rrule:   "FREQ=DAILY;INTERVAL=10;COUNT=5",
+			dtstart: "19970902T090000",
+			want:    "0902 0912 0922 1002 1012",
+		},
+		{
+			// 跨越10月26日夏令时结束，钟点保持09:00
+			name:    "每周，共10次",
+			rrule:   "FREQ=WEEKLY;COUNT=10",
+			dtstart: "19970902T090000",
+			want:    "0902 0909 0916 0923 0930 1007 1014 1021 1028 1104",
+		},
+		{
+			name:    "每周二和周四，UNTIL为UTC时间",
+			rrule:   "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			dtstart: "19970902T090000",
+			want:    "0902 0904 0909 0911 0916 0918 0923 0925 0930 1002",
+		},
+		{
+			name:    "每周二和周四，共10次",
+			rrule:   "FREQ=WEEKLY;COUNT=10;WKST=SU;BYDAY=TU,TH",
+			dtstart: "19970902T090000",
+			want:    "0902 0904 0909 0911 0916 0918 0923 0925 0930 1002",
+		},
+		{
+			name:    "隔周的周一、周三、周五",
+			rrule:   "FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR",
+			dtstart: "19970901T090000",
+			want: "0901 0903 0905 0915 0917 0919 0929 1001 1003 1013 1015 1017 1027 1029 1031 " +
+				"1110 1112 1114 1124 1126 1128 1208 1210 1212 1222",
+		},
+		{
+			name:    "WKST=MO时隔周的周二和周日",
+			rrule:   "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			dtstart: "19970805T090000",
+			want:    "0805 0810 0819 0824",
+		},
+		{
+			name:    "WKST=SU时隔周的周二和周日",
+			rrule:   "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			dtstart: "19970805T090000",
+			want:    "0805 0817 0819 0831",
+		},
+		{
+			name:    "每月第一个周五，共10次",
+			rrule:   "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			dtstart: "19970905T090000",
+			want:    "19970905 19971003 19971107 19971205 19980102 19980206 19980306 19980403 19980501 19980605",
+		},
+		{
+			name:    "每月倒数第二个周一，共6次",
+			rrule:   "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			dtstart: "19970922T090000",
+			want:    "19970922 19971020 19971117 19971222 19980119 19980216",
+		},
+		{
+			name:    "每月倒数第三天",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=-3",
+			dtstart: "19970928T090000",
+			limit:   6,
+			want:    "19970928 19971029 19971128 19971229 19980129 19980226",
+		},
+		{
+			name:    "每月2号和15号，共10次",
+			rrule:   "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			dtstart: "19970902T090000",
+			want:    "19970902 19970915 19971002 19971015 19971102 19971115 19971202 19971215 19980102 19980115",
+		},
+		{
+			name:    "每月第一天和最后一天，共10次",
+			rrule:   "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=1,-1",
+			dtstart: "19970930T090000",
+			want:    "19970930 19971001 19971031 19971101 19971130 19971201 19971231 19980101 19980131 19980201",
+		},
+		{
+			// DTSTART本身不是13号星期五，不计入结果
+			name:    "每个13号星期五",
+			rrule:   "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			dtstart: "19970902T090000",
+			limit:   5,
+			want:    "19980213 19980313 19981113 19990813 20001013",
+		},
+		{
+			name:    "每月最后一个工作日",
+			rrule:   "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			dtstart: "19970930T090000",
+			limit:   6,
+			want:    "19970930 19971031 19971128 19971231 19980130 19980227",
+		},
+		{
+			name:    "每月第三个周二、周三或周四，共3次",
+			rrule:   "FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			dtstart: "19970904T090000",
+			want:    "19970904 19971007 19971106",
+		},
+		{
+			name:    "每月15号和30号，跳过不存在的2月30日",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=15,30;COUNT=5",
+			dtstart: "20070115T090000",
+			want:    "20070115 20070130 20070215 20070315 20070330",
+		},
+		{
+			name:    "每年6月和7月，共10次",
+			rrule:   "FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			dtstart: "19970610T090000",
+			want:    "19970610 19970710 19980610 19980710 19990610 19990710 20000610 20000710 20010610 20010710",
+		},
+		{
+			name:    "每年第20个周一",
+			rrule:   "FREQ=YEARLY;BYDAY=20MO",
+			dtstart: "19970519T090000",
+			limit:   3,
+			want:    "19970519 19980518 19990517",
+		},
+		{
+			name:    "美国总统选举日",
+			rrule:   "FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			dtstart: "19961105T090000",
+			limit:   3,
+			want:    "19961105 20001107 20041102",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expandRule(t, tt.rrule, tt.dtstart, ny, tt.limit)
+			want := strings.Fields(tt.want)
+			for i, day := range want {
+				// 省略年份的写法与DTSTART同年
+				if len(day) == 4 {
+					day = tt.dtstart[:4] + day
+				}
+				want[i] = day + tt.dtstart[8:]
+			}
+			if strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("展开结果不符\n got: %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+// TestRecurrenceUntilLocation 不带Z的UNTIL按系列所属时区解释，与服务器时区无关
+func TestRecurrenceUntilLocation(t *testing.T) {
+	ny := mustLocation(t, "America/New_York")
+	tokyo := mustLocation(t, "Asia/Tokyo")
+
+	tests := []struct {
+		name    string
+		rrule   string
+		dtstart string
+		loc     *time.Location
+		want    int
+	}{
+		{name: "纯日期包含当天，纽约晚上", rrule: "FREQ=DAILY;UNTIL=20240105", dtstart: "20240101T200000", loc: ny, want: 5},
+		{name: "纯日期包含当天，东京深夜", rrule: "FREQ=DAILY;UNTIL=20240105", dtstart: "20240101T233000", loc: tokyo, want: 5},
+		{name: "浮动时间包含等于UNTIL的实例", rrule: "FREQ=DAILY;UNTIL=20240105T200000", dtstart: "20240101T200000", loc: ny, want: 5},
+		{name: "浮动时间早于当天实例", rrule: "FREQ=DAILY;UNTIL=20240105T195959", dtstart: "20240101T200000", loc: ny, want: 4},
+		{name: "UTC时间不受系列时区影响", rrule: "FREQ=DAILY;UNTIL=20240106T000000Z", dtstart: "20240101T200000", loc: ny, want: 4},
+		{name: "纯日期跨越夏令时开始", rrule: "FREQ=DAILY;UNTIL=20240310", dtstart: "20240308T230000", loc: ny, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expandRule(t, tt.rrule, tt.dtstart, tt.loc, 0)
+			if len(got) != tt.want {
+				t.Errorf("实例数量 = %d, want %d: %v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecurrenceResolveCount(t *testing.T) {
+	ny := mustLocation(t, "America/New_York")
+	rule, err := ParseRRule("FREQ=WEEKLY;COUNT=3;BYDAY=MO,FR", ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(2024, 3, 4, 9, 0, 0, 0, ny)
+	rule.ResolveCount(dtstart)
+
+	if rule.Count != 0 {
+		t.Errorf("Count = %d, want 0", rule.Count)
+	}
+	if want := time.Date(2024, 3, 11, 9, 0, 0, 0, ny); !rule.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", rule.Until, want)
+	}
+	if got, want := rule.String(), "FREQ=WEEKLY;UNTIL=20240311T130000Z;BYDAY=MO,FR"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// 从任意实例开始展开，都在原来的最后一次结束
+	reparsed, err := ParseRRule(rule.String(), ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reparsed.Between(time.Date(2024, 3, 8, 9, 0, 0, 0, ny), dtstart, dtstart.AddDate(1, 0, 0), nil, 0)
+	if len(got) != 2 {
+		t.Errorf("从第二个实例展开得到 %d 个实例, want 2: %v", len(got), got)
+	}
+}
+
+func TestRecurrenceExDates(t *testing.T) {
+	rule, err := ParseRRule("FREQ=DAILY;COUNT=5", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	exdates := map[string]bool{"2024-01-02": true, "2024-01-04": true}
+
+	// COUNT包括被排除的实例
+	got := rule.Between(dtstart, dtstart, dtstart.AddDate(1, 0, 0), exdates, 0)
+	if len(got) != 3 {
+		t.Errorf("实例数量 = %d, want 3: %v", len(got), got)
+	}
+	next, ok := rule.After(dtstart, dtstart, exdates)
+	if !ok || !next.Equal(dtstart.AddDate(0, 0, 2)) {
+		t.Errorf("After = %v, %v, want %v", next, ok, dtstart.AddDate(0, 0, 2))
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, rrule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+		"FREQ=DAILY;BYYEARDAY=1",
+	} {
+		if _, err := ParseRRule(rrule, time.UTC); err == nil {
+			t.Errorf("ParseRRule(%q) expected error", rrule)
+		}
+	}
+}
+
+func TestRecurrenceShiftWeekdays(t *testing.T) {
+	rule, err := ParseRRule("FREQ=WEEKLY;BYDAY=MO,SA", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rule.ShiftWeekdays(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rule.String(), "FREQ=WEEKLY;BYDAY=WE,MO"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if err := rule.ShiftWeekdays(-3); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rule.String(), "FREQ=WEEKLY;BYDAY=SU,FR"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for _, rrule := range []string{"FREQ=MONTHLY;BYDAY=1FR", "FREQ=MONTHLY;BYMONTHDAY=15", "FREQ=YEARLY;BYMONTH=6"} {
+		rule, err := ParseRRule(rrule, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rule.ShiftWeekdays(1); err == nil {
+			t.Errorf("ShiftWeekdays(%q) expected error", rrule)
+		}
+		if rule.String() != rrule {
+			t.Errorf("失败时不应修改规则: %q", rule.String())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// 提醒目标类型
+const (
+	ReminderTargetTodo     = "todo"
+	ReminderTargetActivity = "activity"
+)
+
+// 提醒时间锚点：相对开始/结束时间，或绝对时间
+const (
+	ReminderAnchorStart    = "start"
+	ReminderAnchorEnd      = "end"
+	ReminderAnchorAbsolute = "absolute"
+)
+
+// 提醒投递渠道
+const (
+	ReminderChannelEmail = "email"
+	ReminderChannelInApp = "in_app"
+)
+
+// 提醒投递状态
+const (
+	ReminderStatusPending   = "pending"
+	ReminderStatusSending   = "sending"
+	ReminderStatusSent      = "sent"
+	ReminderStatusFailed    = "failed"
+	ReminderStatusCancelled = "cancelled"
+)
+
+// Reminder 待办/活动提醒
+// 相对提醒保存锚点与提前分钟数，RemindAt为计算出的触发时间；
+// Status记录投递状态，已投递的提醒重启后不会再次发送
+type Reminder struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID        uint      `gorm:"not null;type:BIGINT UNSIGNED;index" json:"user_id"`
+	TargetType    string    `gorm:"type:ENUM('todo','activity');not null;index:idx_reminder_target" json:"target_type"`
+	TargetID      uint      `gorm:"not null;type:BIGINT UNSIGNED;index:idx_reminder_target" json:"target_id"`
+	Anchor        string    `gorm:"type:ENUM('start','end','absolute');not null;default:'start'" json:"anchor"`
+	OffsetMinutes int       `gorm:"not null;default:0" json:"offset_minutes"`
+	RemindAt      time.Time `gorm:"not null;index:idx_reminder_due" json:"remind_at"`
+	Channel       string    `gorm:"size:20;not null;default:'email'" json:"channel"`
+	Status        string    `gorm:"type:ENUM('pending','sending','sent','failed','cancelled');not null;default:'pending';index:idx_reminder_due" json:"status"`
+	Attempts      int       `gorm:"not null;default:0" json:"attempts"`
+	LastError     string    `gorm:"type:text" json:"last_error,omitempty"`
+	ClaimToken    string    `gorm:"size:64;index" json:"-"`
+	ClaimedAt     time.Time `gorm:"default:'1900-01-01'" json:"-"`
+	SentAt        time.Time `gorm:"default:'1900-01-01'" json:"sent_at"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+// ComputeRemindAt 根据锚点和提前分钟数计算触发时间
+func (r *Reminder) ComputeRemindAt(start, end time.Time) time.Time {
+	offset := time.Duration(r.OffsetMinutes) * time.Minute
+	switch r.Anchor {
+	case ReminderAnchorStart:
+		return start.Add(-offset)
+	case ReminderAnchorEnd:
+		return end.Add(-offset)
+	default:
+		return r.RemindAt
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认工作时间：周一至周五 9:00-18:00
+const (
+	DefaultWorkStartMinute = 9 * 60
+	DefaultWorkEndMinute   = 18 * 60
+	DefaultWorkDays        = "1,2,3,4,5"
+)
+
+// SchedulePreference 用户的自动排程偏好，弹性待办只会安排在工作时间内
+type SchedulePreference struct {
+	UserID          uint      `gorm:"primaryKey;type:BIGINT UNSIGNED" json:"user_id"`
+	WorkStartMinute int       `gorm:"not null;default:0" json:"work_start_minute"`  // 每天开始工作的时刻，0点起的分钟数
+	WorkEndMinute   int       `gorm:"not null;default:0" json:"work_end_minute"`    // 每天结束工作的时刻，0点起的分钟数
+	WorkDays        string    `gorm:"size:20;not null;default:''" json:"work_days"` // 工作日，逗号分隔，0表示周日
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (SchedulePreference) TableName() string {
+	return "schedule_preferences"
+}
+
+// DefaultSchedulePreference 返回未设置偏好时使用的默认工作时间
+func DefaultSchedulePreference(userID uint) *SchedulePreference {
+	return &SchedulePreference{
+		UserID:          userID,
+		WorkStartMinute: DefaultWorkStartMinute,
+		WorkEndMinute:   DefaultWorkEndMinute,
+		WorkDays:        DefaultWorkDays,
+	}
+}
+
+// IsWorkDay 判断某天是否为工作日
+func (p *SchedulePreference) IsWorkDay(day time.Weekday) bool {
+	for _, part := range strings.Split(p.WorkDays, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && time.Weekday(n) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkMinutesPerDay 每个工作日的工作分钟数
+func (p *SchedulePreference) WorkMinutesPerDay() int {
+	return p.WorkEndMinute - p.WorkStartMinute
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// DefaultCategoryNames 预置分类，对应旧版待办ENUM分类，新用户及历史数据迁移时自动创建
+var DefaultCategoryNames = []string{"work", "study", "fun", "personal"}
+
+// DefaultCategoryName 未指定分类时使用的分类
+const DefaultCategoryName = "personal"
+
+// Category 用户自定义的待办分类，每个待办属于一个分类
+type Category struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID    uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_category_user_name" json:"user_id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex:idx_category_user_name" json:"name"`
+	Color     string    `gorm:"size:20" json:"color,omitempty"`
+	IsDefault bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Tag 用户自定义标签，一个待办可以有多个标签
+type Tag struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID    uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_tag_user_name" json:"user_id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex:idx_tag_user_name" json:"name"`
+	Color     string    `gorm:"size:20" json:"color,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TodoTag 待办与标签的关联
+type TodoTag struct {
+	TodoID uint `gorm:"primaryKey;type:BIGINT UNSIGNED" json:"todo_id"`
+	TagID  uint `gorm:"primaryKey;type:BIGINT UNSIGNED;index" json:"tag_id"`
+}
+
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// 用时记录来源
+const (
+	TimeEntrySourceTimer  = "timer"  // 计时器开始/停止产生
+	TimeEntrySourceManual = "manual" // 手动补录
+)
+
+// TimeEntry 待办的一段实际用时
+// 计时器开始时创建，EndedAt为1900年表示仍在计时；停止时写入结束时间和时长。
+// RunningUserID仅在计时中等于UserID，借助唯一索引保证每个用户同时只有一个计时器
+type TimeEntry struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID          uint      `gorm:"not null;type:BIGINT UNSIGNED;index:idx_time_entry_user" json:"user_id"`
+	TodoID          uint      `gorm:"not null;type:BIGINT UNSIGNED;index" json:"todo_id"`
+	Source          string    `gorm:"type:ENUM('timer','manual');not null;default:'timer'" json:"source"`
+	StartedAt       time.Time `gorm:"not null;index:idx_time_entry_user" json:"started_at"`
+	EndedAt         time.Time `gorm:"default:'1900-01-01'" json:"ended_at"`
+	DurationSeconds int64     `gorm:"not null;default:0" json:"duration_seconds"`
+	Note            string    `gorm:"size:255" json:"note"`
+	RunningUserID   *uint     `gorm:"type:BIGINT UNSIGNED;uniqueIndex" json:"-"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联查询时填充的待办信息，只读不建列
+	TodoTitle string `gorm:"->;-:migration" json:"todo_title,omitempty"`
+	Category  string `gorm:"->;-:migration" json:"category,omitempty"`
+
+	Running bool `gorm:"-" json:"running"` // 是否仍在计时
+}
+
+func (TimeEntry) TableName() string {
+	return "time_entries"
+}
+
+// IsRunning 判断计时器是否仍在计时
+func (e *TimeEntry) IsRunning() bool {
+	return e.EndedAt.Year() <= 1900
+}
+
+// Elapsed 返回截至now的用时，已停止的记录返回保存的时长
+func (e *TimeEntry) Elapsed(now time.Time) time.Duration {
+	if !e.IsRunning() {
+		return time.Duration(e.DurationSeconds) * time.Second
+	}
+	if now.Before(e.StartedAt) {
+		return 0
+	}
+	return now.Sub(e.StartedAt)
+}
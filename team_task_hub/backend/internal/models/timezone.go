@@ -0,0 +1,35 @@
+package models
+
+import (
+	"sync"
+	"time"
+	_ "time/tzdata" // 内嵌时区数据库，精简镜像中没有 /usr/share/zoneinfo 时也能加载时区
+)
+
+// locationCache 已加载的时区，避免每次重新解析时区文件
+var locationCache sync.Map
+
+// LoadLocation 按IANA名称加载时区，名称为空或无法识别时使用服务器时区
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	if loc, ok := locationCache.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	locationCache.Store(name, loc)
+	return loc
+}
+
+// ValidTimezone 判断是否为可识别的IANA时区名称
+func ValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Todo struct {
@@ -10,33 +13,117 @@ type Todo struct {
 	Description string `gorm:"type:text" json:"description,omitempty"`
 	Status      string `gorm:"type:ENUM('pending','in_progress','completed','cancelled');default:'pending';index" json:"status"`
 	Urgency     string `gorm:"type:ENUM('low','medium','high');default:'medium'" json:"urgency"`
-	Category    string `gorm:"type:ENUM('work','study','fun','personal');default:'personal'" json:"category"`
+	Category    string `gorm:"size:50;default:'personal'" json:"category"`
+	CategoryID  uint   `gorm:"not null;default:0;index" json:"category_id"`
+	Tags        []Tag  `gorm:"-" json:"tags,omitempty"`
+
+	// 清单
+	AutoComplete bool               `gorm:"default:false" json:"auto_complete"` // 清单全部勾选后自动完成待办
+	Checklist    *ChecklistProgress `gorm:"-" json:"checklist,omitempty"`
+
+	// 存在尚未完成的前置待办
+	Blocked bool `gorm:"-" json:"blocked"`
 
 	StartTime   time.Time `gorm:"not null;index" json:"start_time"`
 	EndTime     time.Time `gorm:"not null;index" json:"end_time"`
-	CompletedAt time.Time `gorm:"default:'1900-01-01'" json:"completed_at"`
+	StartedAt   time.Time `gorm:"default:'1900-01-01'" json:"started_at"`
+	CompletedAt time.Time `gorm:"default:'1900-01-01';index" json:"completed_at"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	IsOverdue   bool      `gorm:"default:false;index" json:"is_overdue"` // 已过截止时间仍未完成，由后台任务维护
+
+	// 推迟记录：首次推迟前的原定时间和累计推迟次数，重复实例按原定时间续期
+	SnoozeCount       int       `gorm:"not null;default:0" json:"snooze_count"`
+	OriginalStartTime time.Time `gorm:"default:'1900-01-01'" json:"original_start_time"`
+	OriginalEndTime   time.Time `gorm:"default:'1900-01-01'" json:"original_end_time"`
+
+	// 弹性待办：只指定时长和截止时间，开始/结束时间由自动排程安排在空闲的工作时段
+	Flexible        bool      `gorm:"default:false;index" json:"flexible"`
+	DurationMinutes int       `gorm:"not null;default:0" json:"duration_minutes,omitempty"`
+	Deadline        time.Time `gorm:"default:'1900-01-01'" json:"deadline"`
+	Unplaced        bool      `gorm:"default:false" json:"unplaced"` // 截止前没有足够的空闲时段，暂按截止时间倒排
+
+	// 创建时的IANA时区，重复实例按该时区的本地时间展开，为空时使用服务器时区
+	Timezone string `gorm:"size:64;not null;default:''" json:"timezone"`
+
+	// 移入回收站的时间，非空时默认查询不再返回该待办
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 
 	// 父子关系
 	ParentID    uint `gorm:"not null;default:0;index" json:"parent_id"`
 	HasChildren bool `gorm:"default:false;" json:"has_children"`
 
 	// 重复规则
-	RepeatType     string    `gorm:"type:ENUM('none','daily','weekly','monthly');default:'none'" json:"repeat_type"`
+	RepeatType     string    `gorm:"type:ENUM('none','daily','weekly','monthly','yearly');default:'none'" json:"repeat_type"`
 	RepeatInterval int       `gorm:"default:1" json:"repeat_interval"`
 	RepeatEndDate  time.Time `gorm:"default:'2125-01-01'" json:"repeat_end_date"`
+	RRule          string    `gorm:"size:500" json:"rrule,omitempty"`    // RFC 5545 重复规则，非空时优先于RepeatType
+	ExDates        string    `gorm:"type:text" json:"exdates,omitempty"` // 排除的日期，逗号分隔的 YYYY-MM-DD
 
 	CreatorUserID  uint `gorm:"not null;default:0;index" json:"creator_user_id"`
 	CreatorOrganID uint `gorm:"not null;default:0;index" json:"creator_organ_id"`
+
+	// 来源组织名称，组织下发的待办才有
+	OrganizationName string `gorm:"-" json:"organization_name,omitempty"`
+}
+
+// Location 返回待办所属的时区，未设置或无法识别时使用服务器时区
+func (t *Todo) Location() *time.Location {
+	return LoadLocation(t.Timezone)
+}
+
+// LocalStartTime 返回待办所属时区下的开始时间，重复规则按该时区的本地时间展开，跨越夏令时切换时保持钟点不变
+func (t *Todo) LocalStartTime() time.Time {
+	return t.StartTime.In(t.Location())
+}
+
+// Localize 将时间字段转换到待办所属的时区，使接口返回带明确偏移量的本地时间
+// 1900年及以前的“未设置”值保持原样
+func (t *Todo) Localize() {
+	loc := t.Location()
+	for _, field := range []*time.Time{&t.StartTime, &t.EndTime, &t.StartedAt, &t.CompletedAt, &t.CreatedAt, &t.RepeatEndDate, &t.OriginalStartTime, &t.OriginalEndTime, &t.Deadline} {
+		if field.Year() > 1900 {
+			*field = field.In(loc)
+		}
+	}
+}
+
+// Rescheduled 判断重复实例是否偏离过原定时间（推迟或单独修改时间），偏离后原定时间记录在original_*字段
+func (t *Todo) Rescheduled() bool {
+	return t.OriginalStartTime.Year() > 1900
+}
+
+// ScheduledTimes 返回待办原定的开始和结束时间，偏离过原定时间的待办返回首次偏离前的时间
+func (t *Todo) ScheduledTimes() (time.Time, time.Time) {
+	if t.Rescheduled() {
+		return t.OriginalStartTime, t.OriginalEndTime
+	}
+	return t.StartTime, t.EndTime
 }
 
 // CalculateNextInstance 计算下一个实例时间
+// 以当前待办的开始时间为起点，返回晚于afterTime且晚于自身开始时间的下一次出现
 func (t *Todo) CalculateNextInstance(afterTime time.Time) (time.Time, bool) {
 	if t.RepeatType == "none" {
 		return afterTime, false
 	}
+	start := t.LocalStartTime()
+	if afterTime.Before(start) {
+		afterTime = start
+	}
+
+	if t.RRule != "" {
+		rule, err := ParseRRule(t.RRule, t.Location())
+		if err != nil {
+			return afterTime, false
+		}
+		next, ok := rule.After(start, afterTime, t.ExcludedDates())
+		if !ok || next.After(t.RepeatEndDate) {
+			return afterTime, false
+		}
+		return next, true
+	}
 
-	current := t.StartTime
+	current := start
 	for !current.After(afterTime) {
 		switch t.RepeatType {
 		case "daily":
@@ -45,6 +132,8 @@ func (t *Todo) CalculateNextInstance(afterTime time.Time) (time.Time, bool) {
 			current = current.AddDate(0, 0, 7*t.RepeatInterval)
 		case "monthly":
 			current = current.AddDate(0, t.RepeatInterval, 0)
+		case "yearly":
+			current = current.AddDate(t.RepeatInterval, 0, 0)
 		default:
 			return afterTime, false
 		}
@@ -57,25 +146,52 @@ func (t *Todo) CalculateNextInstance(afterTime time.Time) (time.Time, bool) {
 	return current, true
 }
 
+// ExcludedDates 解析被排除的日期集合，键为 YYYY-MM-DD
+func (t *Todo) ExcludedDates() map[string]bool {
+	dates := make(map[string]bool)
+	for _, date := range strings.Split(t.ExDates, ",") {
+		if date = strings.TrimSpace(date); date != "" {
+			dates[date] = true
+		}
+	}
+	return dates
+}
+
 func (Todo) TableName() string {
 	return "todos"
 }
 
+// TodoAssignment 组织待办分配给成员的记录，TodoID指向组织发布的待办，成员接受后为其创建PersonalTodoID对应的个人待办
 type TodoAssignment struct {
 	ID         uint      `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
-	TodoID     uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"todo_id"`
-	AssignedTo uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"assigned_to"`
+	TodoID     uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_assignment_todo_user" json:"todo_id"`
+	AssignedTo uint      `gorm:"not null;type:BIGINT UNSIGNED;uniqueIndex:idx_assignment_todo_user;index" json:"assigned_to"`
 	AssignedBy uint      `gorm:"not null;type:BIGINT UNSIGNED" json:"assigned_by"`
 	AssignedAt time.Time `json:"assigned_at"`
 	Status     string    `gorm:"type:ENUM('pending','accepted','rejected');default:'pending'" json:"status"`
-	AcceptedAt time.Time `json:"accepted_at"`
+	AcceptedAt time.Time `gorm:"default:'1900-01-01'" json:"accepted_at"` // 接受或拒绝的时间
+
+	PersonalTodoID uint   `gorm:"not null;default:0;type:BIGINT UNSIGNED" json:"personal_todo_id,omitempty"`
+	RejectReason   string `gorm:"size:500" json:"reject_reason,omitempty"`
+
+	// 查询时关联的信息
+	AssigneeName     string `gorm:"->;-:migration" json:"assignee_name,omitempty"`     // 被分配成员的用户名
+	OrganizationName string `gorm:"->;-:migration" json:"organization_name,omitempty"` // 发布待办的组织名称
+	TodoStatus       string `gorm:"->;-:migration" json:"todo_status,omitempty"`       // 成员个人待办的状态
 
 	// 简化外键约束
-	Todo     Todo `gorm:"foreignKey:TodoID" json:"todo"`
-	Assignee User `gorm:"foreignKey:AssignedTo" json:"assignee"`
-	Assigner User `gorm:"foreignKey:AssignedBy" json:"assigner"`
+	Todo     *Todo `gorm:"foreignKey:TodoID" json:"todo,omitempty"`
+	Assignee *User `gorm:"foreignKey:AssignedTo" json:"-"`
+	Assigner *User `gorm:"foreignKey:AssignedBy" json:"-"`
 }
 
 func (TodoAssignment) TableName() string {
 	return "todo_assignments"
 }
+
+// 组织待办分配状态
+const (
+	AssignmentPending  = "pending"
+	AssignmentAccepted = "accepted"
+	AssignmentRejected = "rejected"
+)
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// 变更集对应的操作
+const (
+	TodoActionUpdate       = "update"        // 修改字段
+	TodoActionStatus       = "status"        // 状态转换
+	TodoActionEditSeries   = "edit_series"   // 编辑重复待办的多个实例
+	TodoActionCancelSeries = "cancel_series" // 取消重复待办及其实例
+	TodoActionDelete       = "delete"        // 移入回收站
+	TodoActionRestore      = "restore"       // 从回收站恢复
+	TodoActionUndo         = "undo"          // 撤销之前的变更集
+	TodoActionSnooze       = "snooze"        // 推迟待办
+	TodoActionAutoSchedule = "auto_schedule" // 自动排程安排弹性待办的时间
+)
+
+// TodoChangeSet 一次操作对待办产生的全部变更，只追加不修改，撤销以变更集为单位
+type TodoChangeSet struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	OwnerID   uint         `gorm:"not null;type:BIGINT UNSIGNED;index" json:"owner_id"`            // 待办所属用户
+	ActorID   uint         `gorm:"not null;type:BIGINT UNSIGNED;default:0" json:"actor_id"`        // 执行操作的用户，0表示系统
+	TodoID    uint         `gorm:"not null;type:BIGINT UNSIGNED;index" json:"todo_id"`             // 操作的目标待办
+	Action    string       `gorm:"size:30;not null" json:"action"`                                 // 见 TodoAction* 常量
+	RevertOf  uint         `gorm:"not null;type:BIGINT UNSIGNED;default:0" json:"revert_of"`       // 撤销操作所撤销的变更集ID
+	UndoneBy  uint         `gorm:"not null;type:BIGINT UNSIGNED;default:0;index" json:"undone_by"` // 已被撤销时，对应撤销操作的变更集ID
+	CreatedAt time.Time    `gorm:"autoCreateTime;index" json:"created_at"`
+	Changes   []TodoChange `gorm:"foreignKey:ChangeSetID" json:"changes"`
+}
+
+func (TodoChangeSet) TableName() string {
+	return "todo_change_sets"
+}
+
+// TodoChange 单个待办某个字段的变更，值为格式化后的字符串，时间使用RFC3339
+type TodoChange struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	ChangeSetID uint   `gorm:"not null;type:BIGINT UNSIGNED;index" json:"change_set_id"`
+	TodoID      uint   `gorm:"not null;type:BIGINT UNSIGNED;index" json:"todo_id"`
+	Field       string `gorm:"size:50;not null" json:"field"`
+	OldValue    string `gorm:"type:text" json:"old_value"`
+	NewValue    string `gorm:"type:text" json:"new_value"`
+}
+
+func (TodoChange) TableName() string {
+	return "todo_changes"
+}
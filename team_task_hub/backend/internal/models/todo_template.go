@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// TodoTemplate 用户的待办模板，一个模板可包含多个待办，按指定日期一次性创建
+// 共享到组织后，组织成员都可以查看和使用，只有创建者可以修改和删除
+type TodoTemplate struct {
+	ID             uint               `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	UserID         uint               `gorm:"not null;type:BIGINT UNSIGNED;index" json:"user_id"`
+	OrganizationID uint               `gorm:"not null;type:BIGINT UNSIGNED;default:0;index" json:"organization_id"` // 共享到的组织，0表示仅自己可见
+	Name           string             `gorm:"size:100;not null" json:"name"`
+	Description    string             `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt      time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+	Items          []TodoTemplateItem `gorm:"foreignKey:TemplateID" json:"items,omitempty"`
+}
+
+func (TodoTemplate) TableName() string {
+	return "todo_templates"
+}
+
+// TodoTemplateItem 模板中的一个待办，时间以实例化日期为基准
+type TodoTemplateItem struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement;type:BIGINT UNSIGNED" json:"id"`
+	TemplateID      uint   `gorm:"not null;type:BIGINT UNSIGNED;index" json:"template_id"`
+	Position        int    `gorm:"not null;default:0" json:"position"`
+	Title           string `gorm:"size:200;not null" json:"title"`
+	Description     string `gorm:"type:text" json:"description"`
+	Urgency         string `gorm:"type:ENUM('low','medium','high');default:'medium'" json:"urgency"`
+	Category        string `gorm:"size:50;not null;default:''" json:"category"`
+	DayOffset       int    `gorm:"not null;default:0" json:"day_offset"`       // 相对实例化日期的天数
+	StartMinute     int    `gorm:"not null;default:0" json:"start_minute"`     // 开始时刻，当天0点起的分钟数
+	DurationMinutes int    `gorm:"not null;default:0" json:"duration_minutes"` // 持续时长（分钟）
+
+	// 重复规则，与创建待办相同，rrule非空时优先于repeat_type
+	RepeatType     string `gorm:"type:ENUM('none','daily','weekly','monthly','yearly');default:'none'" json:"repeat_type"`
+	RepeatInterval int    `gorm:"default:1" json:"repeat_interval"`
+	RRule          string `gorm:"size:500" json:"rrule,omitempty"`
+
+	// 清单：数据库中按换行分隔保存，接口中以数组返回
+	Checklist      string   `gorm:"type:text" json:"-"`
+	ChecklistItems []string `gorm:"-" json:"checklist,omitempty"`
+	AutoComplete   bool     `gorm:"default:false" json:"auto_complete"`
+}
+
+func (TodoTemplateItem) TableName() string {
+	return "todo_template_items"
+}
+
+// SetChecklist 设置清单项标题
+func (i *TodoTemplateItem) SetChecklist(titles []string) {
+	i.Checklist = strings.Join(titles, "\n")
+	i.ChecklistItems = titles
+}
+
+// LoadChecklist 从数据库中的字段还原清单项标题
+func (i *TodoTemplateItem) LoadChecklist() {
+	i.ChecklistItems = nil
+	if i.Checklist != "" {
+		i.ChecklistItems = strings.Split(i.Checklist, "\n")
+	}
+}
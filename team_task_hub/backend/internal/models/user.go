@@ -8,6 +8,7 @@ type User struct {
 	TokenVersion uint   `gorm:"default:1;not null" json:"token_version"`
 	AvatarURL    string `gorm:"size:255" json:"avatar_url"`
 	Role         uint   `gorm:"type:TINYINT UNSIGNED;default:0;not null" json:"role"`
+	Timezone     string `gorm:"size:64;not null;default:''" json:"timezone"` // IANA时区名称，为空时使用服务器时区
 }
 
 func (User) TableName() string {
@@ -217,6 +217,29 @@ func (r *ActivityRepository) FindOrganizationsWithActivitiesInTimeRange(userID u
 	return organizations, nil
 }
 
+// FindParticipatedActivitiesEndingAfter 查找用户参与的、在指定时间之后结束且仍有效的活动
+func (r *ActivityRepository) FindParticipatedActivitiesEndingAfter(userID uint, after time.Time) ([]models.Activity, error) {
+	var activities []models.Activity
+
+	err := r.db.
+		Joins("JOIN activity_participations ON activities.id = activity_participations.activity_id").
+		Where("activity_participations.user_id = ?", userID).
+		Where("activity_participations.status != ?", "cancelled").
+		Where("activities.status = ?", "active").
+		Where("activities.end_time >= ?", after).
+		Preload("Organization", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name")
+		}).
+		Order("activities.start_time ASC").
+		Find(&activities).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("查询参与的活动失败: %v", err)
+	}
+
+	return activities, nil
+}
+
 // FindUserActivitiesStartingInRange 查找用户在时间范围内开始的活动
 func (r *ActivityRepository) FindUserActivitiesStartingInRange(userID uint, startTime, endTime time.Time) ([]models.Activity, error) {
 	var activities []models.Activity
@@ -304,6 +327,69 @@ func (r *ActivityRepository) FindUserActivitiesOverlappingRange(userID uint, sta
 	return activities, nil
 }
 
+// FindUserConflictingActivities 查找用户尚未完成、与[startTime, endTime)有交集的进行中活动，首尾相接不算交集
+func (r *ActivityRepository) FindUserConflictingActivities(userID uint, startTime, endTime time.Time) ([]models.Activity, error) {
+	var activities []models.Activity
+
+	err := r.db.
+		Joins("JOIN activity_participations ON activities.id = activity_participations.activity_id").
+		Where("activity_participations.user_id = ?", userID).
+		Where("activity_participations.status = 'pending'").
+		Where("activities.status = 'active'").
+		Where("activities.start_time < ? AND activities.end_time > ?", endTime, startTime).
+		Preload("Organization", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "LogoURL")
+		}).
+		Order("activities.start_time ASC").
+		Find(&activities).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("查询冲突活动失败: %v", err)
+	}
+
+	return activities, nil
+}
+
+// FindUserParticipationsInRange 查找用户参与且与[startTime, endTime)有交集的未取消活动，
+// 只保留参与状态在statuses中的记录，同时返回按活动ID索引的参与记录
+func (r *ActivityRepository) FindUserParticipationsInRange(userID uint, startTime, endTime time.Time, statuses []string) ([]models.Activity, map[uint]models.ActivityParticipation, error) {
+	var participations []models.ActivityParticipation
+	err := r.db.
+		Joins("JOIN activities ON activities.id = activity_participations.activity_id").
+		Where("activity_participations.user_id = ?", userID).
+		Where("activity_participations.status IN ?", statuses).
+		Where("activities.status != 'cancelled'").
+		Where("activities.start_time < ? AND activities.end_time > ?", endTime, startTime).
+		Find(&participations).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询活动参与记录失败: %v", err)
+	}
+	if len(participations) == 0 {
+		return nil, map[uint]models.ActivityParticipation{}, nil
+	}
+
+	byActivity := make(map[uint]models.ActivityParticipation, len(participations))
+	activityIDs := make([]uint, 0, len(participations))
+	for _, p := range participations {
+		byActivity[p.ActivityID] = p
+		activityIDs = append(activityIDs, p.ActivityID)
+	}
+
+	var activities []models.Activity
+	err = r.db.
+		Where("id IN ?", activityIDs).
+		Preload("Organization", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "LogoURL")
+		}).
+		Order("start_time ASC").
+		Find(&activities).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询参与的活动失败: %v", err)
+	}
+
+	return activities, byActivity, nil
+}
+
 // 在 activityRepository 结构体中实现
 // FindUserPendingActivitiesEndingOnDate 查找用户某个时间段结束的活动
 func (r *ActivityRepository) FindUserPendingActivitiesEndingOnDate(userID uint, startTime, endTime time.Time) ([]models.Activity, error) {
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BoardRepository 看板、看板列及待办排序键数据访问层
+type BoardRepository struct {
+	db *gorm.DB
+}
+
+// NewBoardRepository 构造函数：创建BoardRepository实例
+func NewBoardRepository(db *gorm.DB) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+// preloadColumns 按列顺序预加载看板列
+func preloadColumns(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// FindByUser 查询用户的全部看板（含列），默认看板在前
+func (r *BoardRepository) FindByUser(userID uint) ([]models.Board, error) {
+	var boards []models.Board
+	err := r.db.
+		Where("user_id = ?", userID).
+		Preload("Columns", preloadColumns).
+		Order("is_default DESC, id ASC").
+		Find(&boards).Error
+	return boards, err
+}
+
+// FindByID 根据ID查询用户的看板（含列）
+func (r *BoardRepository) FindByID(userID, id uint) (*models.Board, error) {
+	var board models.Board
+	err := r.db.
+		Where("user_id = ?", userID).
+		Preload("Columns", preloadColumns).
+		First(&board, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// CountByUser 统计用户的看板数量
+func (r *BoardRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Board{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Create 创建看板及其列
+func (r *BoardRepository) Create(board *models.Board) error {
+	return r.db.Create(board).Error
+}
+
+// UpdateWithColumns 更新看板名称，columns非nil时整体替换看板列
+func (r *BoardRepository) UpdateWithColumns(board *models.Board, name string, columns []models.BoardColumn) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(board).Update("name", name).Error; err != nil {
+			return err
+		}
+		if columns == nil {
+			return nil
+		}
+		if err := tx.Where("board_id = ?", board.ID).Delete(&models.BoardColumn{}).Error; err != nil {
+			return err
+		}
+		for i := range columns {
+			columns[i].BoardID = board.ID
+		}
+		return tx.Create(&columns).Error
+	})
+}
+
+// Delete 删除看板及其列和排序键
+func (r *BoardRepository) Delete(board *models.Board) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("board_id = ?", board.ID).Delete(&models.BoardCard{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("board_id = ?", board.ID).Delete(&models.BoardColumn{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(board).Error
+	})
+}
+
+// LockWithTx 支持事务的版本：锁定看板，同一看板的移动操作串行执行，避免生成相同的排序键
+func (r *BoardRepository) LockWithTx(tx *gorm.DB, userID, id uint) error {
+	var board models.Board
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&board, id).Error
+}
+
+// FindCardPositions 查询看板中待办的排序键，按待办ID索引
+func (r *BoardRepository) FindCardPositions(boardID uint) (map[uint]string, error) {
+	return r.FindCardPositionsWithTx(r.db, boardID)
+}
+
+// FindCardPositionsWithTx 支持事务的版本：查询看板中待办的排序键
+func (r *BoardRepository) FindCardPositionsWithTx(tx *gorm.DB, boardID uint) (map[uint]string, error) {
+	var cards []models.BoardCard
+	if err := tx.Where("board_id = ?", boardID).Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	positions := make(map[uint]string, len(cards))
+	for _, card := range cards {
+		positions[card.TodoID] = card.Position
+	}
+	return positions, nil
+}
+
+// SaveCardsWithTx 支持事务的版本：写入待办的排序键，已存在时覆盖
+func (r *BoardRepository) SaveCardsWithTx(tx *gorm.DB, cards []models.BoardCard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "board_id"}, {Name: "todo_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "updated_at"}),
+	}).CreateInBatches(cards, 100).Error
+}
+
+// DeleteCardsByTodoWithTx 支持事务的版本：删除待办（及其实例）在所有看板中的排序键
+func (r *BoardRepository) DeleteCardsByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("todo_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("todo_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	return query.Delete(&models.BoardCard{}).Error
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository 日历订阅数据访问层
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository 构造函数：创建CalendarFeedRepository实例
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// FindByUser 查询用户的订阅
+func (r *CalendarFeedRepository) FindByUser(userID uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	if err := r.db.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindByToken 根据token查询订阅
+func (r *CalendarFeedRepository) FindByToken(token string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	if err := r.db.Where("token = ?", token).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Create 创建订阅
+func (r *CalendarFeedRepository) Create(feed *models.CalendarFeed) error {
+	return r.db.Create(feed).Error
+}
+
+// UpdateToken 更换订阅token，旧链接随即失效
+func (r *CalendarFeedRepository) UpdateToken(id uint, token string) error {
+	return r.db.Model(&models.CalendarFeed{}).Where("id = ?", id).Update("token", token).Error
+}
+
+// DeleteByUser 删除用户的订阅，返回是否删除了记录
+func (r *CalendarFeedRepository) DeleteByUser(userID uint) (bool, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.CalendarFeed{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchAccess 记录订阅最近一次被读取的时间
+func (r *CalendarFeedRepository) TouchAccess(id uint, at time.Time) error {
+	return r.db.Model(&models.CalendarFeed{}).Where("id = ?", id).Update("last_accessed_at", at).Error
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CategoryRepository 待办分类数据访问层
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository 构造函数：创建CategoryRepository实例
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// EnsureDefaults 确保用户拥有全部预置分类，已存在的不会重复创建
+func (r *CategoryRepository) EnsureDefaults(userID uint) error {
+	for _, name := range models.DefaultCategoryNames {
+		category := models.Category{UserID: userID, Name: name}
+		err := r.db.
+			Where(models.Category{UserID: userID, Name: name}).
+			Attrs(models.Category{IsDefault: true}).
+			FirstOrCreate(&category).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByUser 查询用户的全部分类
+func (r *CategoryRepository) FindByUser(userID uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.
+		Where("user_id = ?", userID).
+		Order("is_default DESC, id ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
+// FindByID 根据ID查询用户的分类
+func (r *CategoryRepository) FindByID(userID, id uint) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("user_id = ?", userID).First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindByName 根据名称查询用户的分类
+func (r *CategoryRepository) FindByName(userID uint, name string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Create 创建分类
+func (r *CategoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+// Update 更新分类，同时同步待办上冗余存储的分类名称
+func (r *CategoryRepository) Update(category *models.Category, updates map[string]any) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(category).Updates(updates).Error; err != nil {
+			return err
+		}
+		if name, ok := updates["name"]; ok {
+			return tx.Model(&models.Todo{}).
+				Where("category_id = ?", category.ID).
+				Update("category", name).Error
+		}
+		return nil
+	})
+}
+
+// DeleteAndReassign 删除分类，并把该分类下的待办转移到目标分类
+func (r *CategoryRepository) DeleteAndReassign(category *models.Category, target *models.Category) (int64, error) {
+	var reassigned int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Todo{}).
+			Where("category_id = ?", category.ID).
+			Updates(map[string]any{
+				"category_id": target.ID,
+				"category":    target.Name,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		reassigned = result.RowsAffected
+		return tx.Delete(category).Error
+	})
+	return reassigned, err
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistRepository 待办清单项数据访问层
+type ChecklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository 构造函数：创建ChecklistRepository实例
+func NewChecklistRepository(db *gorm.DB) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+// FindByTodo 按顺序查询待办的全部清单项
+func (r *ChecklistRepository) FindByTodo(todoID uint) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	err := r.db.
+		Where("todo_id = ?", todoID).
+		Order("position ASC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// FindByID 查询待办下的某个清单项
+func (r *ChecklistRepository) FindByID(todoID, itemID uint) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := r.db.Where("todo_id = ?", todoID).First(&item, itemID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create 创建清单项，排在当前最后一项之后
+func (r *ChecklistRepository) Create(item *models.ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		err := tx.Model(&models.ChecklistItem{}).
+			Where("todo_id = ?", item.TodoID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&maxPosition).Error
+		if err != nil {
+			return err
+		}
+		item.Position = maxPosition + 1
+		return tx.Create(item).Error
+	})
+}
+
+// BatchCreate 批量创建清单项
+func (r *ChecklistRepository) BatchCreate(items []models.ChecklistItem) error {
+	return r.BatchCreateWithTx(r.db, items)
+}
+
+// BatchCreateWithTx 支持事务的版本：批量创建清单项
+func (r *ChecklistRepository) BatchCreateWithTx(tx *gorm.DB, items []models.ChecklistItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(items, 100).Error
+}
+
+// CopyToTodosWithTx 支持事务的版本：将源待办的清单项（重置为未完成）复制给新待办，key为新待办ID，value为源待办ID
+func (r *ChecklistRepository) CopyToTodosWithTx(tx *gorm.DB, targetToSource map[uint]uint) error {
+	if len(targetToSource) == 0 {
+		return nil
+	}
+	sourceIDs := make([]uint, 0, len(targetToSource))
+	for _, sourceID := range targetToSource {
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+
+	var sourceItems []models.ChecklistItem
+	err := tx.Where("todo_id IN ?", sourceIDs).Order("position ASC, id ASC").Find(&sourceItems).Error
+	if err != nil {
+		return err
+	}
+	itemsBySource := make(map[uint][]models.ChecklistItem)
+	for _, item := range sourceItems {
+		itemsBySource[item.TodoID] = append(itemsBySource[item.TodoID], item)
+	}
+
+	var copies []models.ChecklistItem
+	for targetID, sourceID := range targetToSource {
+		for _, item := range itemsBySource[sourceID] {
+			copies = append(copies, models.ChecklistItem{
+				TodoID:   targetID,
+				Title:    item.Title,
+				Position: item.Position,
+			})
+		}
+	}
+	return r.BatchCreateWithTx(tx, copies)
+}
+
+// Update 更新清单项
+func (r *ChecklistRepository) Update(item *models.ChecklistItem, updates map[string]any) error {
+	return r.db.Model(item).Updates(updates).Error
+}
+
+// Delete 删除清单项
+func (r *ChecklistRepository) Delete(item *models.ChecklistItem) error {
+	return r.db.Delete(item).Error
+}
+
+// Reorder 按给定的ID顺序重排清单项
+func (r *ChecklistRepository) Reorder(todoID uint, itemIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, itemID := range itemIDs {
+			err := tx.Model(&models.ChecklistItem{}).
+				Where("id = ? AND todo_id = ?", itemID, todoID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountByTodoIDs 统计若干待办的清单项总数和已完成数
+func (r *ChecklistRepository) CountByTodoIDs(todoIDs []uint) (map[uint]models.ChecklistProgress, error) {
+	result := make(map[uint]models.ChecklistProgress)
+	if len(todoIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		TodoID uint
+		Total  int
+		Done   int
+	}
+	err := r.db.Model(&models.ChecklistItem{}).
+		Select("todo_id, COUNT(*) AS total, SUM(CASE WHEN done THEN 1 ELSE 0 END) AS done").
+		Where("todo_id IN ?", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.TodoID] = models.NewChecklistProgress(row.Total, row.Done)
+	}
+	return result, nil
+}
+
+// DeleteByTodoWithTx 支持事务的版本：删除待办的清单项，includeChildren为true时一并删除其实例（包括回收站中的实例）的清单项
+func (r *ChecklistRepository) DeleteByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("todo_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("todo_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	return query.Delete(&models.ChecklistItem{}).Error
+}
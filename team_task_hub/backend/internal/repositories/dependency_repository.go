@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DependencyRepository 待办依赖关系数据访问层
+type DependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository 构造函数：创建DependencyRepository实例
+func NewDependencyRepository(db *gorm.DB) *DependencyRepository {
+	return &DependencyRepository{db: db}
+}
+
+// Create 创建依赖关系
+func (r *DependencyRepository) Create(dependency *models.TodoDependency) error {
+	return r.db.Create(dependency).Error
+}
+
+// Delete 删除依赖关系，返回是否删除了记录
+func (r *DependencyRepository) Delete(blockerID, blockedID uint) (bool, error) {
+	result := r.db.
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.TodoDependency{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists 判断依赖关系是否已存在
+func (r *DependencyRepository) Exists(blockerID, blockedID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TodoDependency{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindByUser 查询用户的全部依赖关系，用于环检测
+func (r *DependencyRepository) FindByUser(userID uint) ([]models.TodoDependency, error) {
+	var dependencies []models.TodoDependency
+	err := r.db.Where("creator_user_id = ?", userID).Find(&dependencies).Error
+	return dependencies, err
+}
+
+// FindBlockers 查询阻塞某个待办的前置待办
+func (r *DependencyRepository) FindBlockers(todoID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.
+		Joins("JOIN todo_dependencies ON todo_dependencies.blocker_id = todos.id").
+		Where("todo_dependencies.blocked_id = ?", todoID).
+		Order("todos.start_time ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindBlocked 查询被某个待办阻塞的后续待办
+func (r *DependencyRepository) FindBlocked(todoID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.
+		Joins("JOIN todo_dependencies ON todo_dependencies.blocked_id = todos.id").
+		Where("todo_dependencies.blocker_id = ?", todoID).
+		Order("todos.start_time ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindBlockedTodoIDs 在给定的待办中找出仍有未完成前置待办的待办
+// 前置待办已完成、已取消或已移入回收站都视为不再阻塞
+func (r *DependencyRepository) FindBlockedTodoIDs(todoIDs []uint) ([]uint, error) {
+	var blockedIDs []uint
+	if len(todoIDs) == 0 {
+		return blockedIDs, nil
+	}
+	err := r.db.Model(&models.TodoDependency{}).
+		Joins("JOIN todos ON todos.id = todo_dependencies.blocker_id").
+		Where("todo_dependencies.blocked_id IN ?", todoIDs).
+		Where("todos.status NOT IN ?", []string{"completed", "cancelled"}).
+		Where("todos.deleted_at IS NULL").
+		Distinct().
+		Pluck("todo_dependencies.blocked_id", &blockedIDs).Error
+	return blockedIDs, err
+}
+
+// DeleteByTodoWithTx 支持事务的版本：删除与待办相关的全部依赖，includeChildren为true时包括其实例
+func (r *DependencyRepository) DeleteByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("blocker_id = ? OR blocked_id = ?", todoID, todoID)
+	if includeChildren {
+		children := tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID)
+		query = query.Or("blocker_id IN (?) OR blocked_id IN (?)", children, children)
+	}
+	return query.Delete(&models.TodoDependency{}).Error
+}
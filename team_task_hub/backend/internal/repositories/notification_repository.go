@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 站内通知数据访问层
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 构造函数：创建NotificationRepository实例
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// BatchCreate 批量创建通知
+func (r *NotificationRepository) BatchCreate(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, 100).Error
+}
+
+// FindByUser 按时间倒序查询用户的通知
+func (r *NotificationRepository) FindByUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_unread = ?", true)
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread 统计用户的未读通知数量
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_unread = ?", userID, true).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的指定通知标记为已读，ids为空时标记全部
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint) (int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ? AND is_unread = ?", userID, true)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Update("is_unread", false)
+	return result.RowsAffected, result.Error
+}
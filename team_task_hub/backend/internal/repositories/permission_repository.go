@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"fmt"
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PermissionRepository 组织权限数据访问层
+type PermissionRepository struct {
+	db *gorm.DB
+}
+
+// NewPermissionRepository 构造函数：创建PermissionRepository实例
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+// Has 检查用户在组织中是否被授予了某项权限
+func (r *PermissionRepository) Has(organizationID, userID uint, permissionType string) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.Permission{}).
+		Where("organization_id = ? AND user_id = ? AND permission_type = ?", organizationID, userID, permissionType).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("查询权限失败: %v", result.Error)
+	}
+	return count > 0, nil
+}
+
+// Grant 授予权限，已存在时保持不变
+func (r *PermissionRepository) Grant(permission *models.Permission) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(permission)
+	if result.Error != nil {
+		return fmt.Errorf("授予权限失败: %v", result.Error)
+	}
+	return nil
+}
+
+// Revoke 撤销权限，返回是否存在被撤销的记录
+func (r *PermissionRepository) Revoke(organizationID, userID uint, permissionType string) (bool, error) {
+	result := r.db.
+		Where("organization_id = ? AND user_id = ? AND permission_type = ?", organizationID, userID, permissionType).
+		Delete(&models.Permission{})
+	if result.Error != nil {
+		return false, fmt.Errorf("撤销权限失败: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAll 撤销用户在组织中的全部权限，用于成员退出或被移除
+func (r *PermissionRepository) RevokeAll(organizationID, userID uint) error {
+	result := r.db.
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&models.Permission{})
+	if result.Error != nil {
+		return fmt.Errorf("撤销成员权限失败: %v", result.Error)
+	}
+	return nil
+}
+
+// FindByOrganization 查询组织内授予的全部权限
+func (r *PermissionRepository) FindByOrganization(organizationID uint) ([]models.Permission, error) {
+	var permissions []models.Permission
+	result := r.db.Where("organization_id = ?", organizationID).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "AvatarURL")
+		}).
+		Order("user_id ASC, permission_type ASC").
+		Find(&permissions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("查询组织权限失败: %v", result.Error)
+	}
+	return permissions, nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReminderRepository 提醒数据访问层
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository 构造函数：创建ReminderRepository实例
+func NewReminderRepository(db *gorm.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// Create 创建提醒
+func (r *ReminderRepository) Create(reminder *models.Reminder) error {
+	return r.db.Create(reminder).Error
+}
+
+// FindByID 查询用户的某条提醒
+func (r *ReminderRepository) FindByID(userID, id uint) (*models.Reminder, error) {
+	var reminder models.Reminder
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&reminder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// FindByUser 查询用户的提醒，targetType为空时不按目标过滤
+func (r *ReminderRepository) FindByUser(userID uint, targetType string, targetID uint) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	query := r.db.Where("user_id = ?", userID)
+	if targetType != "" {
+		query = query.Where("target_type = ? AND target_id = ?", targetType, targetID)
+	}
+	err := query.Order("remind_at ASC, id ASC").Find(&reminders).Error
+	return reminders, err
+}
+
+// Cancel 取消尚未投递的提醒，返回是否有记录被取消
+func (r *ReminderRepository) Cancel(userID, id uint) (bool, error) {
+	result := r.db.Model(&models.Reminder{}).
+		Where("id = ? AND user_id = ? AND status = ?", id, userID, models.ReminderStatusPending).
+		Update("status", models.ReminderStatusCancelled)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SyncRelativeRemindAt 目标的开始/结束时间修改后，重新计算其相对提醒的触发时间
+func (r *ReminderRepository) SyncRelativeRemindAt(targetType string, targetID uint) error {
+	return r.SyncRelativeRemindAtWithTx(r.db, targetType, targetID)
+}
+
+// SyncRelativeRemindAtWithTx 在事务中重新计算某个目标的相对提醒的触发时间
+func (r *ReminderRepository) SyncRelativeRemindAtWithTx(tx *gorm.DB, targetType string, targetID uint) error {
+	table := "todos"
+	if targetType == models.ReminderTargetActivity {
+		table = "activities"
+	}
+	return tx.Exec(`UPDATE reminders r JOIN `+table+` t ON r.target_id = t.id
+		SET r.remind_at = DATE_SUB(IF(r.anchor = ?, t.start_time, t.end_time), INTERVAL r.offset_minutes MINUTE)
+		WHERE r.target_type = ? AND r.target_id = ? AND r.status = ? AND r.anchor IN ?`,
+		models.ReminderAnchorStart, targetType, targetID, models.ReminderStatusPending,
+		[]string{models.ReminderAnchorStart, models.ReminderAnchorEnd},
+	).Error
+}
+
+// ClaimDue 以token原子认领到期的待投递提醒并返回认领结果，
+// 多实例同时调度时同一条提醒只会被一个实例认领
+func (r *ReminderRepository) ClaimDue(now time.Time, token string, limit int) ([]models.Reminder, error) {
+	err := r.db.Exec(`UPDATE reminders SET status = ?, claim_token = ?, claimed_at = ?
+		WHERE status = ? AND remind_at <= ? ORDER BY remind_at ASC LIMIT ?`,
+		models.ReminderStatusSending, token, now, models.ReminderStatusPending, now, limit,
+	).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []models.Reminder
+	err = r.db.Where("claim_token = ? AND status = ?", token, models.ReminderStatusSending).
+		Order("remind_at ASC").Find(&reminders).Error
+	return reminders, err
+}
+
+// MarkSent 标记提醒已投递
+func (r *ReminderRepository) MarkSent(id uint, sentAt time.Time) error {
+	return r.db.Model(&models.Reminder{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     models.ReminderStatusSent,
+		"sent_at":    sentAt,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": "",
+	}).Error
+}
+
+// MarkFailed 记录投递失败，retry为true时退回待投递状态等待下次重试
+func (r *ReminderRepository) MarkFailed(id uint, errMsg string, retry bool) error {
+	status := models.ReminderStatusFailed
+	if retry {
+		status = models.ReminderStatusPending
+	}
+	return r.db.Model(&models.Reminder{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": errMsg,
+	}).Error
+}
+
+// MarkCancelled 将提醒标记为已取消（目标已完成或不存在）
+func (r *ReminderRepository) MarkCancelled(id uint, reason string) error {
+	return r.db.Model(&models.Reminder{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     models.ReminderStatusCancelled,
+		"last_error": reason,
+	}).Error
+}
+
+// FailStaleClaims 将认领后长时间未完成的提醒标记为失败。
+// 进程可能在发送后、落库前崩溃，为避免重复发送不再自动重试
+func (r *ReminderRepository) FailStaleClaims(before time.Time) (int64, error) {
+	result := r.db.Model(&models.Reminder{}).
+		Where("status = ? AND claimed_at < ?", models.ReminderStatusSending, before).
+		Updates(map[string]interface{}{
+			"status":     models.ReminderStatusFailed,
+			"last_error": "投递过程中断",
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByTodoWithTx 支持事务的版本：删除待办的提醒，includeChildren时同时删除其实例的提醒
+func (r *ReminderRepository) DeleteByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("target_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("target_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	return tx.Where("target_type = ?", models.ReminderTargetTodo).Where(query).Delete(&models.Reminder{}).Error
+}
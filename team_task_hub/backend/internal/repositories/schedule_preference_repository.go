@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchedulePreferenceRepository 自动排程偏好数据访问层
+type SchedulePreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewSchedulePreferenceRepository 构造函数：创建SchedulePreferenceRepository实例
+func NewSchedulePreferenceRepository(db *gorm.DB) *SchedulePreferenceRepository {
+	return &SchedulePreferenceRepository{db: db}
+}
+
+// FindByUser 查询用户的排程偏好
+func (r *SchedulePreferenceRepository) FindByUser(userID uint) (*models.SchedulePreference, error) {
+	var pref models.SchedulePreference
+	if err := r.db.First(&pref, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// Save 保存用户的排程偏好，已存在时覆盖
+func (r *SchedulePreferenceRepository) Save(pref *models.SchedulePreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"work_start_minute", "work_end_minute", "work_days", "updated_at"}),
+	}).Create(pref).Error
+}
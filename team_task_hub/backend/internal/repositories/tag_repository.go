@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository 标签数据访问层，同时负责待办与标签的关联
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 构造函数：创建TagRepository实例
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// FindByUser 查询用户的全部标签
+func (r *TagRepository) FindByUser(userID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByID 根据ID查询用户的标签
+func (r *TagRepository) FindByID(userID, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("user_id = ?", userID).First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByName 根据名称查询用户的标签
+func (r *TagRepository) FindByName(userID uint, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindOrCreateByNames 按名称查找用户的标签，不存在的自动创建
+func (r *TagRepository) FindOrCreateByNames(userID uint, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tag := models.Tag{UserID: userID, Name: name}
+		if err := r.db.Where(models.Tag{UserID: userID, Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Create 创建标签
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// Update 更新标签
+func (r *TagRepository) Update(tag *models.Tag, updates map[string]any) error {
+	return r.db.Model(tag).Updates(updates).Error
+}
+
+// Delete 删除标签及其与待办的关联
+func (r *TagRepository) Delete(tag *models.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.TodoTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+}
+
+// ReplaceTodoTagsWithTx 支持事务的版本：将若干待办的标签替换为指定标签
+func (r *TagRepository) ReplaceTodoTagsWithTx(tx *gorm.DB, todoIDs []uint, tagIDs []uint) error {
+	if len(todoIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("todo_id IN ?", todoIDs).Delete(&models.TodoTag{}).Error; err != nil {
+		return err
+	}
+	return r.addTodoTags(tx, todoIDs, tagIDs)
+}
+
+// AddTodoTags 为若干待办添加相同的标签
+func (r *TagRepository) AddTodoTags(todoIDs []uint, tagIDs []uint) error {
+	return r.addTodoTags(r.db, todoIDs, tagIDs)
+}
+
+// AddTodoTagsWithTx 支持事务的版本：为若干待办添加相同的标签
+func (r *TagRepository) AddTodoTagsWithTx(tx *gorm.DB, todoIDs []uint, tagIDs []uint) error {
+	return r.addTodoTags(tx, todoIDs, tagIDs)
+}
+
+func (r *TagRepository) addTodoTags(db *gorm.DB, todoIDs []uint, tagIDs []uint) error {
+	if len(todoIDs) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+	links := make([]models.TodoTag, 0, len(todoIDs)*len(tagIDs))
+	for _, todoID := range todoIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, models.TodoTag{TodoID: todoID, TagID: tagID})
+		}
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500).Error
+}
+
+// CopyTodoTagsWithTx 支持事务的版本：将源待办的标签复制给新待办，key为新待办ID，value为源待办ID
+func (r *TagRepository) CopyTodoTagsWithTx(tx *gorm.DB, targetToSource map[uint]uint) error {
+	if len(targetToSource) == 0 {
+		return nil
+	}
+	sourceIDs := make([]uint, 0, len(targetToSource))
+	for _, sourceID := range targetToSource {
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+
+	var sourceLinks []models.TodoTag
+	if err := tx.Where("todo_id IN ?", sourceIDs).Find(&sourceLinks).Error; err != nil {
+		return err
+	}
+	tagsBySource := make(map[uint][]uint)
+	for _, link := range sourceLinks {
+		tagsBySource[link.TodoID] = append(tagsBySource[link.TodoID], link.TagID)
+	}
+
+	var links []models.TodoTag
+	for targetID, sourceID := range targetToSource {
+		for _, tagID := range tagsBySource[sourceID] {
+			links = append(links, models.TodoTag{TodoID: targetID, TagID: tagID})
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500).Error
+}
+
+// FindTagsByTodoIDs 查询若干待办的标签，返回待办ID到标签列表的映射
+func (r *TagRepository) FindTagsByTodoIDs(todoIDs []uint) (map[uint][]models.Tag, error) {
+	result := make(map[uint][]models.Tag)
+	if len(todoIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		TodoID uint
+		models.Tag
+	}
+	err := r.db.Table("todo_tags").
+		Select("todo_tags.todo_id, tags.*").
+		Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+		Where("todo_tags.todo_id IN ?", todoIDs).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.TodoID] = append(result[row.TodoID], row.Tag)
+	}
+	return result, nil
+}
+
+// DeleteTodoLinksWithTx 支持事务的版本：删除待办的标签关联，includeChildren为true时一并删除其实例的关联
+func (r *TagRepository) DeleteTodoLinksWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("todo_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("todo_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	return query.Delete(&models.TodoTag{}).Error
+}
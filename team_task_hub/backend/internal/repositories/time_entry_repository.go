@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// timeEntryWithTodo 关联待办标题和分类的查询列
+const timeEntryWithTodo = "time_entries.*, todos.title AS todo_title, todos.category AS category"
+
+// TimeEntryRepository 用时记录数据访问层
+type TimeEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeEntryRepository 构造函数：创建TimeEntryRepository实例
+func NewTimeEntryRepository(db *gorm.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+// withTodo 关联待办查询，回收站中的待办同样关联
+func (r *TimeEntryRepository) withTodo(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.TimeEntry{}).
+		Select(timeEntryWithTodo).
+		Joins("LEFT JOIN todos ON todos.id = time_entries.todo_id")
+}
+
+// Create 创建用时记录
+func (r *TimeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// CreateWithTx 支持事务的版本：创建用时记录
+func (r *TimeEntryRepository) CreateWithTx(tx *gorm.DB, entry *models.TimeEntry) error {
+	return tx.Create(entry).Error
+}
+
+// LockUserWithTx 锁定用户行，串行化同一用户的计时器操作
+func (r *TimeEntryRepository) LockUserWithTx(tx *gorm.DB, userID uint) error {
+	var user models.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&user, userID).Error
+}
+
+// FindRunning 查询用户正在计时的记录
+func (r *TimeEntryRepository) FindRunning(userID uint) (*models.TimeEntry, error) {
+	return r.findRunning(r.db, userID)
+}
+
+// FindRunningWithTx 支持事务的版本：查询用户正在计时的记录
+func (r *TimeEntryRepository) FindRunningWithTx(tx *gorm.DB, userID uint) (*models.TimeEntry, error) {
+	return r.findRunning(tx, userID)
+}
+
+func (r *TimeEntryRepository) findRunning(db *gorm.DB, userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.withTodo(db).
+		Where("time_entries.running_user_id = ?", userID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StopWithTx 支持事务的版本：停止计时并写入结束时间和时长，已停止时返回false
+func (r *TimeEntryRepository) StopWithTx(tx *gorm.DB, id uint, endedAt time.Time, durationSeconds int64) (bool, error) {
+	result := tx.Model(&models.TimeEntry{}).
+		Where("id = ? AND running_user_id IS NOT NULL", id).
+		Updates(map[string]any{
+			"ended_at":         endedAt,
+			"duration_seconds": durationSeconds,
+			"running_user_id":  nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByID 查询用户的某条用时记录
+func (r *TimeEntryRepository) FindByID(userID, id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.withTodo(r.db).
+		Where("time_entries.id = ? AND time_entries.user_id = ?", id, userID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByTodos 查询用户在指定待办上的全部用时记录，按开始时间倒序
+func (r *TimeEntryRepository) FindByTodos(userID uint, todoIDs []uint) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	if len(todoIDs) == 0 {
+		return entries, nil
+	}
+	err := r.withTodo(r.db).
+		Where("time_entries.user_id = ? AND time_entries.todo_id IN ?", userID, todoIDs).
+		Order("time_entries.started_at DESC, time_entries.id DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindOverlapping 查询与[from, to)有交集的用时记录，正在计时的记录视为持续到当前
+func (r *TimeEntryRepository) FindOverlapping(userID uint, from, to time.Time) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.withTodo(r.db).
+		Where("time_entries.user_id = ? AND time_entries.started_at < ?", userID, to).
+		Where("time_entries.ended_at > ? OR time_entries.running_user_id IS NOT NULL", from).
+		Order("time_entries.started_at ASC, time_entries.id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// Delete 删除用户已停止的用时记录，返回是否有记录被删除
+func (r *TimeEntryRepository) Delete(userID, id uint) (bool, error) {
+	result := r.db.
+		Where("id = ? AND user_id = ? AND running_user_id IS NULL", id, userID).
+		Delete(&models.TimeEntry{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Transaction 在事务中执行
+func (r *TimeEntryRepository) Transaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// DeleteByTodoWithTx 支持事务的版本：删除待办的用时记录，includeChildren时同时删除其实例的记录
+func (r *TimeEntryRepository) DeleteByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("todo_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("todo_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	return query.Delete(&models.TimeEntry{}).Error
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TodoAssignmentRepository 组织待办及其分配记录数据访问层
+type TodoAssignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewTodoAssignmentRepository 构造函数：创建TodoAssignmentRepository实例
+func NewTodoAssignmentRepository(db *gorm.DB) *TodoAssignmentRepository {
+	return &TodoAssignmentRepository{db: db}
+}
+
+// AssignmentSummaryRow 分配记录按状态的统计，TodoStatus为成员个人待办的状态
+type AssignmentSummaryRow struct {
+	TodoID     uint
+	Status     string
+	TodoStatus string
+	Count      int64
+}
+
+// orgTodoCondition 组织发布的待办：由组织创建且不属于任何个人
+const orgTodoCondition = "creator_organ_id = ? AND creator_user_id = 0"
+
+// FindOrgTodos 查询组织发布的待办，按开始时间倒序
+func (r *TodoAssignmentRepository) FindOrgTodos(organizationID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Where(orgTodoCondition, organizationID).
+		Order("start_time DESC, id DESC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindOrgTodo 查询组织发布的某个待办
+func (r *TodoAssignmentRepository) FindOrgTodo(organizationID, todoID uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := r.db.Where(orgTodoCondition, organizationID).First(&todo, todoID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// FindOrgTodoForUpdateWithTx 支持事务的版本：锁定组织发布的某个待办
+func (r *TodoAssignmentRepository) FindOrgTodoForUpdateWithTx(tx *gorm.DB, organizationID, todoID uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(orgTodoCondition, organizationID).
+		First(&todo, todoID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// FindOrgTodoByIDForUpdateWithTx 支持事务的版本：按ID锁定组织发布的待办，不限定组织
+func (r *TodoAssignmentRepository) FindOrgTodoByIDForUpdateWithTx(tx *gorm.DB, todoID uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("creator_organ_id > 0 AND creator_user_id = 0").
+		First(&todo, todoID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// BatchCreateWithTx 支持事务的版本：批量创建分配记录，同一成员已分配过的跳过
+func (r *TodoAssignmentRepository) BatchCreateWithTx(tx *gorm.DB, assignments []models.TodoAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(assignments, 100).Error
+}
+
+// FindAssignedUserIDs 查询某个组织待办已分配的成员
+func (r *TodoAssignmentRepository) FindAssignedUserIDs(todoID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.TodoAssignment{}).
+		Where("todo_id = ?", todoID).
+		Pluck("assigned_to", &userIDs).Error
+	return userIDs, err
+}
+
+// SummarizeByTodos 统计组织待办的分配情况，按分配状态和成员个人待办状态分组
+func (r *TodoAssignmentRepository) SummarizeByTodos(todoIDs []uint) ([]AssignmentSummaryRow, error) {
+	var rows []AssignmentSummaryRow
+	if len(todoIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Model(&models.TodoAssignment{}).
+		Select("todo_assignments.todo_id, todo_assignments.status, COALESCE(todos.status, '') AS todo_status, COUNT(*) AS count").
+		Joins("LEFT JOIN todos ON todos.id = todo_assignments.personal_todo_id AND todos.deleted_at IS NULL").
+		Where("todo_assignments.todo_id IN ?", todoIDs).
+		Group("todo_assignments.todo_id, todo_assignments.status, todos.status").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// FindByTodo 查询组织待办的分配记录，附带成员用户名和个人待办状态
+func (r *TodoAssignmentRepository) FindByTodo(todoID uint) ([]models.TodoAssignment, error) {
+	var assignments []models.TodoAssignment
+	err := r.db.Model(&models.TodoAssignment{}).
+		Select("todo_assignments.*, users.username AS assignee_name, COALESCE(todos.status, '') AS todo_status").
+		Joins("LEFT JOIN users ON users.id = todo_assignments.assigned_to").
+		Joins("LEFT JOIN todos ON todos.id = todo_assignments.personal_todo_id AND todos.deleted_at IS NULL").
+		Where("todo_assignments.todo_id = ?", todoID).
+		Order("todo_assignments.id ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// FindByUser 查询分配给用户的组织待办，status为空时返回全部，附带组织名称和个人待办状态
+func (r *TodoAssignmentRepository) FindByUser(userID uint, status string) ([]models.TodoAssignment, error) {
+	var assignments []models.TodoAssignment
+	query := r.db.Model(&models.TodoAssignment{}).
+		Select("todo_assignments.*, organizations.name AS organization_name, COALESCE(personal.status, '') AS todo_status").
+		Joins("JOIN todos org_todo ON org_todo.id = todo_assignments.todo_id AND org_todo.deleted_at IS NULL").
+		Joins("LEFT JOIN organizations ON organizations.id = org_todo.creator_organ_id").
+		Joins("LEFT JOIN todos personal ON personal.id = todo_assignments.personal_todo_id AND personal.deleted_at IS NULL").
+		Where("todo_assignments.assigned_to = ?", userID)
+	if status != "" {
+		query = query.Where("todo_assignments.status = ?", status)
+	}
+	err := query.Preload("Todo").
+		Order("todo_assignments.assigned_at DESC, todo_assignments.id DESC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// FindByIDForUpdateWithTx 支持事务的版本：锁定用户的某条分配记录
+func (r *TodoAssignmentRepository) FindByIDForUpdateWithTx(tx *gorm.DB, userID, id uint) (*models.TodoAssignment, error) {
+	var assignment models.TodoAssignment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("assigned_to = ?", userID).
+		First(&assignment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// UpdateWithTx 支持事务的版本：按ID更新分配记录
+func (r *TodoAssignmentRepository) UpdateWithTx(tx *gorm.DB, id uint, updates map[string]any) error {
+	return tx.Model(&models.TodoAssignment{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeletePendingByTodoWithTx 支持事务的版本：删除组织待办下尚未处理的分配
+func (r *TodoAssignmentRepository) DeletePendingByTodoWithTx(tx *gorm.DB, todoID uint) (int64, error) {
+	result := tx.Where("todo_id = ? AND status = ?", todoID, models.AssignmentPending).
+		Delete(&models.TodoAssignment{})
+	return result.RowsAffected, result.Error
+}
+
+// CancelPersonalTodosWithTx 支持事务的版本：取消成员接受后尚未完成的个人待办，返回取消的数量
+func (r *TodoAssignmentRepository) CancelPersonalTodosWithTx(tx *gorm.DB, todoID uint) (int64, error) {
+	result := tx.Model(&models.Todo{}).
+		Where("id IN (?)", tx.Model(&models.TodoAssignment{}).
+			Select("personal_todo_id").
+			Where("todo_id = ? AND status = ?", todoID, models.AssignmentAccepted)).
+		Where("status IN ?", []string{"pending", "in_progress"}).
+		Update("status", "cancelled")
+	return result.RowsAffected, result.Error
+}
+
+// FindAcceptedUserIDs 查询已接受组织待办的成员
+func (r *TodoAssignmentRepository) FindAcceptedUserIDs(todoID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.TodoAssignment{}).
+		Where("todo_id = ? AND status = ?", todoID, models.AssignmentAccepted).
+		Pluck("assigned_to", &userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TodoHistoryRepository 待办变更历史数据访问层
+type TodoHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewTodoHistoryRepository 构造函数：创建TodoHistoryRepository实例
+func NewTodoHistoryRepository(db *gorm.DB) *TodoHistoryRepository {
+	return &TodoHistoryRepository{db: db}
+}
+
+// CreateWithTx 支持事务的版本：保存变更集及其字段变更
+func (r *TodoHistoryRepository) CreateWithTx(tx *gorm.DB, set *models.TodoChangeSet) error {
+	return tx.Create(set).Error
+}
+
+// touchingTodo 以待办为目标或包含该待办字段变更的变更集
+func (r *TodoHistoryRepository) touchingTodo(db *gorm.DB, ownerID, todoID uint) *gorm.DB {
+	return db.Model(&models.TodoChangeSet{}).
+		Where("owner_id = ?", ownerID).
+		Where("todo_id = ? OR id IN (?)", todoID,
+			r.db.Model(&models.TodoChange{}).Select("change_set_id").Where("todo_id = ?", todoID))
+}
+
+// FindByTodo 查询与待办相关的变更集，按时间倒序，beforeID非零时只返回更早的记录
+func (r *TodoHistoryRepository) FindByTodo(ownerID, todoID, beforeID uint, limit int) ([]models.TodoChangeSet, error) {
+	query := r.touchingTodo(r.db, ownerID, todoID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var sets []models.TodoChangeSet
+	err := query.
+		Preload("Changes", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("id DESC").
+		Limit(limit).
+		Find(&sets).Error
+	return sets, err
+}
+
+// FindLatestUndoable 查询与待办相关、最近一次尚未撤销且本身不是撤销操作的变更集
+func (r *TodoHistoryRepository) FindLatestUndoable(ownerID, todoID uint) (*models.TodoChangeSet, error) {
+	var set models.TodoChangeSet
+	err := r.touchingTodo(r.db, ownerID, todoID).
+		Where("undone_by = 0 AND action <> ?", models.TodoActionUndo).
+		Preload("Changes", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("id DESC").
+		First(&set).Error
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// MarkUndoneWithTx 支持事务的版本：标记变更集已被撤销，已被其他操作撤销时返回false
+func (r *TodoHistoryRepository) MarkUndoneWithTx(tx *gorm.DB, id, undoneBy uint) (bool, error) {
+	result := tx.Model(&models.TodoChangeSet{}).
+		Where("id = ? AND undone_by = 0", id).
+		Update("undone_by", undoneBy)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByTodoWithTx 支持事务的版本：删除以待办为目标的变更集及该待办的字段变更，
+// includeChildren时同时删除其实例的历史
+func (r *TodoHistoryRepository) DeleteByTodoWithTx(tx *gorm.DB, todoID uint, includeChildren bool) error {
+	query := tx.Where("todo_id = ?", todoID)
+	if includeChildren {
+		query = query.Or("todo_id IN (?)", tx.Unscoped().Model(&models.Todo{}).Select("id").Where("parent_id = ?", todoID))
+	}
+	sets := tx.Model(&models.TodoChangeSet{}).Select("id").Where(query)
+	if err := tx.Where(query).Or("change_set_id IN (?)", sets).Delete(&models.TodoChange{}).Error; err != nil {
+		return err
+	}
+	return tx.Where(query).Delete(&models.TodoChangeSet{}).Error
+}
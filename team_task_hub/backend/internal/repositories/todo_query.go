@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// 待办查询支持的排序字段
+var todoSortFields = map[string]bool{
+	"start_time":   true,
+	"end_time":     true,
+	"created_at":   true,
+	"completed_at": true,
+}
+
+// IsTodoSortField 判断是否为支持的排序字段
+func IsTodoSortField(field string) bool {
+	return todoSortFields[field]
+}
+
+// TodoQuery 可组合的待办查询构造器，每个方法追加一个筛选条件，
+// 排序使用 (排序字段, id) 作为游标键，保证翻页稳定
+type TodoQuery struct {
+	db        *gorm.DB
+	userID    uint
+	sortField string
+	desc      bool
+	limit     int
+}
+
+// Query 创建某个用户的待办查询，默认按开始时间升序
+func (r *TodoRepository) Query(userID uint) *TodoQuery {
+	return &TodoQuery{
+		db:        r.db.Model(&models.Todo{}).Where("todos.creator_user_id = ?", userID),
+		userID:    userID,
+		sortField: "start_time",
+	}
+}
+
+// Statuses 按状态筛选
+func (q *TodoQuery) Statuses(statuses ...string) *TodoQuery {
+	if len(statuses) > 0 {
+		q.db = q.db.Where("todos.status IN ?", statuses)
+	}
+	return q
+}
+
+// Urgencies 按紧急程度筛选
+func (q *TodoQuery) Urgencies(urgencies ...string) *TodoQuery {
+	if len(urgencies) > 0 {
+		q.db = q.db.Where("todos.urgency IN ?", urgencies)
+	}
+	return q
+}
+
+// Categories 按分类名称筛选
+func (q *TodoQuery) Categories(names ...string) *TodoQuery {
+	if len(names) > 0 {
+		q.db = q.db.Where("todos.category IN ?", names)
+	}
+	return q
+}
+
+// AnyTags 筛选带有任意一个指定标签的待办
+func (q *TodoQuery) AnyTags(names ...string) *TodoQuery {
+	if len(names) > 0 {
+		q.db = q.db.Where(`EXISTS (SELECT 1 FROM todo_tags tt JOIN tags t ON t.id = tt.tag_id
+			WHERE tt.todo_id = todos.id AND t.user_id = ? AND t.name IN ?)`, q.userID, names)
+	}
+	return q
+}
+
+// Parent 只查询某个重复待办的实例
+func (q *TodoQuery) Parent(parentID uint) *TodoQuery {
+	q.db = q.db.Where("todos.parent_id = ?", parentID)
+	return q
+}
+
+// ParentIn 只查询若干重复待办的实例
+func (q *TodoQuery) ParentIn(parentIDs []uint) *TodoQuery {
+	q.db = q.db.Where("todos.parent_id IN ?", parentIDs)
+	return q
+}
+
+// InstancesOnly 排除重复待办的抽象待办
+func (q *TodoQuery) InstancesOnly() *TodoQuery {
+	q.db = q.db.Where("todos.has_children = ?", false)
+	return q
+}
+
+// Overdue 按逾期标记筛选
+func (q *TodoQuery) Overdue(overdue bool) *TodoQuery {
+	q.db = q.db.Where("todos.is_overdue = ?", overdue)
+	return q
+}
+
+// Text 在标题和描述中模糊搜索
+func (q *TodoQuery) Text(keyword string) *TodoQuery {
+	if keyword != "" {
+		pattern := "%" + escapeLike(keyword) + "%"
+		q.db = q.db.Where("(todos.title LIKE ? OR todos.description LIKE ?)", pattern, pattern)
+	}
+	return q
+}
+
+// TimeRange 按时间字段筛选，from包含、to不包含，零值表示不限
+func (q *TodoQuery) TimeRange(field string, from, to time.Time) *TodoQuery {
+	if !todoSortFields[field] {
+		return q
+	}
+	if !from.IsZero() {
+		q.db = q.db.Where("todos."+field+" >= ?", from)
+	}
+	if !to.IsZero() {
+		q.db = q.db.Where("todos."+field+" < ?", to)
+	}
+	return q
+}
+
+// Overlapping 筛选与[from, to)时间段有交集的待办，首尾相接不算交集
+func (q *TodoQuery) Overlapping(from, to time.Time) *TodoQuery {
+	q.db = q.db.Where("todos.start_time < ? AND todos.end_time > ?", to, from)
+	return q
+}
+
+// OrderBy 设置排序字段和方向，不支持的字段保持默认
+func (q *TodoQuery) OrderBy(field string, desc bool) *TodoQuery {
+	if todoSortFields[field] {
+		q.sortField = field
+	}
+	q.desc = desc
+	return q
+}
+
+// After 从游标之后开始查询，游标为上一页最后一条记录的排序值和ID，需在OrderBy之后调用
+func (q *TodoQuery) After(sortValue time.Time, id uint) *TodoQuery {
+	column := "todos." + q.sortField
+	op := ">"
+	if q.desc {
+		op = "<"
+	}
+	q.db = q.db.Where("("+column+" "+op+" ? OR ("+column+" = ? AND todos.id "+op+" ?))", sortValue, sortValue, id)
+	return q
+}
+
+// Limit 设置返回条数
+func (q *TodoQuery) Limit(limit int) *TodoQuery {
+	q.limit = limit
+	return q
+}
+
+// Find 执行查询
+func (q *TodoQuery) Find() ([]models.Todo, error) {
+	direction := " ASC"
+	if q.desc {
+		direction = " DESC"
+	}
+	query := q.db.Order("todos." + q.sortField + direction).Order("todos.id" + direction)
+	if q.limit > 0 {
+		query = query.Limit(q.limit)
+	}
+
+	var todos []models.Todo
+	err := query.Find(&todos).Error
+	return todos, err
+}
+
+// escapeLike 转义LIKE通配符
+func escapeLike(s string) string {
+	escaped := make([]rune, 0, len(s))
+	for _, r := range s {
+		if r == '%' || r == '_' || r == '\\' {
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, r)
+	}
+	return string(escaped)
+}
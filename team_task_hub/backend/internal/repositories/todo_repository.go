@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TodoRepository struct {
@@ -20,6 +21,11 @@ func (r *TodoRepository) Create(todo *models.Todo) error {
 	return r.db.Create(todo).Error
 }
 
+// CreateWithTx 支持事务的版本：创建待办
+func (r *TodoRepository) CreateWithTx(tx *gorm.DB, todo *models.Todo) error {
+	return tx.Create(todo).Error
+}
+
 func (r *TodoRepository) FindByID(id uint) (*models.Todo, error) {
 	var todo models.Todo
 	err := r.db.First(&todo, id).Error
@@ -174,43 +180,6 @@ func (r *TodoRepository) FindTodoByDetails(userID uint, startTime, endTime time.
 	return &todo, nil
 }
 
-// UpdateStatus 更新单个待办事项的状态
-func (r *TodoRepository) UpdateStatus(id uint, status string) error {
-	result := r.db.Model(&models.Todo{}).
-		Where("id = ?", id).
-		Update("status", status)
-	return result.Error
-}
-
-// UpdateTodoStateByDetails 根据多条件更新待办状态
-func (r *TodoRepository) UpdateTodoStateByDetails(userID uint, title, description string, startTime, endTime time.Time, newState string) error {
-	result := r.db.Model(&models.Todo{}).
-		Where("creator_user_id = ?", userID).
-		Where("title = ?", title).
-		Where("description = ?", description).
-		Where("start_time = ?", startTime).
-		Where("end_time = ?", endTime).
-		Update("status", newState)
-
-	return result.Error
-}
-
-// UpdateTodoStateAndCompletedTime 更新待办状态和完成时间
-func (r *TodoRepository) UpdateTodoStateAndCompletedTime(userID uint, title, description string, startTime, endTime time.Time, newState string, completedAt time.Time) error {
-	result := r.db.Model(&models.Todo{}).
-		Where("creator_user_id = ?", userID).
-		Where("title = ?", title).
-		Where("description = ?", description).
-		Where("start_time = ?", startTime).
-		Where("end_time = ?", endTime).
-		Updates(map[string]any{
-			"status":       newState,
-			"completed_at": completedAt,
-		})
-
-	return result.Error
-}
-
 // BatchUpdateChildrenStatus 批量更新子待办事项状态
 func (r *TodoRepository) BatchUpdateChildrenStatus(parentID uint, oldStatus, newStatus string) (int64, error) {
 	result := r.db.Model(&models.Todo{}).
@@ -237,15 +206,6 @@ func (r *TodoRepository) FindRootTodoByDetailsWithTx(tx *gorm.DB, userID uint, t
 	return &todo, nil
 }
 
-// UpdateStatusWithTx 支持事务的版本：更新单个待办事项的状态
-func (r *TodoRepository) UpdateStatusWithTx(tx *gorm.DB, id uint, status string) error {
-	// 使用传入的 tx 进行更新
-	result := tx.Model(&models.Todo{}).
-		Where("id = ?", id).
-		Update("status", status)
-	return result.Error
-}
-
 // BatchUpdateChildrenStatusWithTx 支持事务的版本：批量更新子待办事项状态
 func (r *TodoRepository) BatchUpdateChildrenStatusWithTx(tx *gorm.DB, parentID uint, oldStatus, newStatus string) (int64, error) {
 	// 使用传入的 tx 进行批量更新
@@ -255,6 +215,166 @@ func (r *TodoRepository) BatchUpdateChildrenStatusWithTx(tx *gorm.DB, parentID u
 	return result.RowsAffected, result.Error
 }
 
+// UpdateWithTx 支持事务的版本：按ID更新待办的多个字段
+// 调用方已确认待办存在；值未变化时MySQL报告0行受影响，因此不按RowsAffected判断
+func (r *TodoRepository) UpdateWithTx(tx *gorm.DB, id uint, updates map[string]any) error {
+	return tx.Model(&models.Todo{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteWithTx 支持事务的版本：删除单个待办
+func (r *TodoRepository) DeleteWithTx(tx *gorm.DB, id uint) error {
+	result := tx.Delete(&models.Todo{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteChildrenWithTx 支持事务的版本：删除某个抽象待办下的所有实例
+func (r *TodoRepository) DeleteChildrenWithTx(tx *gorm.DB, parentID uint) (int64, error) {
+	result := tx.Where("parent_id = ?", parentID).Delete(&models.Todo{})
+	return result.RowsAffected, result.Error
+}
+
+// trashRootCondition 回收站中的顶层条目：单个待办、抽象待办，或所属抽象待办未被删除的实例
+const trashRootCondition = `todos.deleted_at IS NOT NULL AND NOT EXISTS (
+	SELECT 1 FROM todos p WHERE p.id = todos.parent_id AND p.deleted_at IS NOT NULL)`
+
+// FindTrashGroupWithTx 支持事务的版本：查找删除待办时会一起移入回收站的待办（自身及未删除的实例）
+func (r *TodoRepository) FindTrashGroupWithTx(tx *gorm.DB, id uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? OR parent_id = ?", id, id).
+		Find(&todos).Error
+	return todos, err
+}
+
+// MoveToTrashWithTx 支持事务的版本：将若干待办移入回收站，同一批使用相同的删除时间以便整体恢复
+func (r *TodoRepository) MoveToTrashWithTx(tx *gorm.DB, ids []uint, at time.Time) (int64, error) {
+	result := tx.Model(&models.Todo{}).
+		Where("id IN ?", ids).
+		Update("deleted_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// FindTrash 查找用户回收站中的待办，最近删除的在前
+func (r *TodoRepository) FindTrash(userID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Unscoped().
+		Where("creator_user_id = ?", userID).
+		Where(trashRootCondition).
+		Order("deleted_at DESC, id DESC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindTrashedByID 查找回收站中的待办
+func (r *TodoRepository) FindTrashedByID(id uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&todo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// IsTrashed 判断待办是否在回收站中
+func (r *TodoRepository) IsTrashed(id uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Todo{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindRestoreGroupWithTx 支持事务的版本：查找恢复待办时会一起恢复的待办（自身及与它一起删除的实例）
+// 在此之前单独删除的实例保留在回收站中
+func (r *TodoRepository) FindRestoreGroupWithTx(tx *gorm.DB, todo *models.Todo) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? OR (parent_id = ? AND deleted_at = ?)", todo.ID, todo.ID, todo.DeletedAt.Time).
+		Find(&todos).Error
+	return todos, err
+}
+
+// RestoreWithTx 支持事务的版本：将若干待办从回收站恢复
+func (r *TodoRepository) RestoreWithTx(tx *gorm.DB, ids []uint) (int64, error) {
+	result := tx.Unscoped().Model(&models.Todo{}).
+		Where("id IN ?", ids).
+		Update("deleted_at", nil)
+	return result.RowsAffected, result.Error
+}
+
+// FindExpiredTrash 查找删除时间早于before的回收站条目
+func (r *TodoRepository) FindExpiredTrash(before time.Time, limit int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Unscoped().
+		Where(trashRootCondition).
+		Where("todos.deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&todos).Error
+	return todos, err
+}
+
+// PurgeWithTx 支持事务的版本：彻底删除待办及其全部实例（包括回收站中的）
+func (r *TodoRepository) PurgeWithTx(tx *gorm.DB, id uint) (int64, error) {
+	result := tx.Unscoped().Where("id = ? OR parent_id = ?", id, id).Delete(&models.Todo{})
+	return result.RowsAffected, result.Error
+}
+
+// FindChildrenByStatusWithTx 支持事务的版本：查找并锁定抽象待办下处于某个状态的实例
+func (r *TodoRepository) FindChildrenByStatusWithTx(tx *gorm.DB, parentID uint, status string) ([]models.Todo, error) {
+	var children []models.Todo
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("parent_id = ? AND status = ?", parentID, status).
+		Find(&children).Error
+	return children, err
+}
+
+// FindByIDsIncludingTrashedWithTx 支持事务的版本：按ID查找并锁定待办，包括回收站中的待办
+func (r *TodoRepository) FindByIDsIncludingTrashedWithTx(tx *gorm.DB, ids []uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Find(&todos).Error
+	return todos, err
+}
+
+// UpdateIncludingTrashedWithTx 支持事务的版本：按ID更新待办，包括回收站中的待办
+func (r *TodoRepository) UpdateIncludingTrashedWithTx(tx *gorm.DB, id uint, updates map[string]any) error {
+	return tx.Unscoped().Model(&models.Todo{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// FindEditableChildrenWithTx 支持事务的版本：查找抽象待办下尚未完成的实例，from非零时只返回从该时间开始的实例
+func (r *TodoRepository) FindEditableChildrenWithTx(tx *gorm.DB, parentID uint, from time.Time) ([]models.Todo, error) {
+	var children []models.Todo
+	query := tx.
+		Where("parent_id = ?", parentID).
+		Where("status IN ?", []string{"pending", "in_progress"})
+	if !from.IsZero() {
+		query = query.Where("start_time >= ?", from)
+	}
+	err := query.Order("start_time ASC").Find(&children).Error
+	return children, err
+}
+
+// FindChildrenFromWithTx 查询系列中开始时间不早于from的全部实例（含已完成和已取消），用于拆分系列
+func (r *TodoRepository) FindChildrenFromWithTx(tx *gorm.DB, parentID uint, from time.Time) ([]models.Todo, error) {
+	var children []models.Todo
+	err := tx.Where("parent_id = ? AND start_time >= ?", parentID, from).
+		Order("start_time ASC").Find(&children).Error
+	return children, err
+}
+
+// renewDueCondition 子待办需要续期的条件，推迟或单独改过时间的实例按原定结束时间判断，不会延后续期
+const renewDueCondition = "IF(original_end_time >= '1900-01-02', original_end_time, end_time) < ?"
+
 // FindExpiredRenewableTodos 查找有"生育能力"的过期子待办
 func (r *TodoRepository) FindExpiredRenewableTodos(userID uint) ([]models.Todo, error) {
 	var todos []models.Todo
@@ -265,18 +385,107 @@ func (r *TodoRepository) FindExpiredRenewableTodos(userID uint) ([]models.Todo,
 		Where("parent_id != 0").
 		Where("status != ?", "cancelled").
 		Where("repeat_type != ?", "none").
-		Where("end_time < ?", future).
+		Where(renewDueCondition, future).
+		Find(&todos).Error
+
+	return todos, err
+}
+
+// FindExpiredRenewableTodosForUpdateWithTx 支持事务的版本：查询并锁定有"生育能力"的过期子待办，同一用户的续期串行执行
+// 等待锁期间被其他续期处理过的子待办已无生育能力，不会再次返回
+func (r *TodoRepository) FindExpiredRenewableTodosForUpdateWithTx(tx *gorm.DB, userID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	future := time.Now().Add(7 * 24 * time.Hour)
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("creator_user_id = ?", userID).
+		Where("parent_id != 0").
+		Where("status != ?", "cancelled").
+		Where("repeat_type != ?", "none").
+		Where(renewDueCondition, future).
+		Order("id ASC").
 		Find(&todos).Error
+	return todos, err
+}
+
+// FindUserIDsWithRenewableTodos 查找存在需要续期子待办的用户
+func (r *TodoRepository) FindUserIDsWithRenewableTodos() ([]uint, error) {
+	var userIDs []uint
+	future := time.Now().Add(7 * 24 * time.Hour)
+	err := r.db.Model(&models.Todo{}).
+		Where("parent_id != 0").
+		Where("status != ?", "cancelled").
+		Where("repeat_type != ?", "none").
+		Where(renewDueCondition, future).
+		Distinct().
+		Pluck("creator_user_id", &userIDs).Error
+	return userIDs, err
+}
 
+// FindFlexibleTodosForUpdateWithTx 支持事务的版本：查询并锁定用户待开始的弹性待办，同一用户的排程串行执行
+func (r *TodoRepository) FindFlexibleTodosForUpdateWithTx(tx *gorm.DB, userID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("creator_user_id = ?", userID).
+		Where("flexible = ?", true).
+		Where("status = ?", "pending").
+		Order("id ASC").
+		Find(&todos).Error
 	return todos, err
 }
 
+// HasFlexibleTodos 判断用户是否有待开始的弹性待办
+func (r *TodoRepository) HasFlexibleTodos(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Todo{}).
+		Where("creator_user_id = ?", userID).
+		Where("flexible = ?", true).
+		Where("status = ?", "pending").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindUserIDsWithFlexibleTodos 查找存在待开始弹性待办的用户
+func (r *TodoRepository) FindUserIDsWithFlexibleTodos() ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.Todo{}).
+		Where("flexible = ?", true).
+		Where("status = ?", "pending").
+		Distinct().
+		Pluck("creator_user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// MarkOverdueTodos 将已过截止时间且未完成的待办标记为逾期
+func (r *TodoRepository) MarkOverdueTodos(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Todo{}).
+		Where("status IN ?", []string{"pending", "in_progress"}).
+		Where("has_children = false").
+		Where("is_overdue = false").
+		Where("end_time < ?", now).
+		Update("is_overdue", true)
+	return result.RowsAffected, result.Error
+}
+
+// ClearOverdueTodos 清除截止时间已被推迟或已完成待办的逾期标记
+func (r *TodoRepository) ClearOverdueTodos(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Todo{}).
+		Where("is_overdue = true").
+		Where("end_time >= ? OR status IN ?", now, []string{"completed", "cancelled"}).
+		Update("is_overdue", false)
+	return result.RowsAffected, result.Error
+}
+
 // BatchUpdateRepeatType 批量更新重复类型
 func (r *TodoRepository) BatchUpdateRepeatType(ids []uint, repeatType string) error {
+	return r.BatchUpdateRepeatTypeWithTx(r.db, ids, repeatType)
+}
+
+// BatchUpdateRepeatTypeWithTx 支持事务的版本：批量更新重复类型
+func (r *TodoRepository) BatchUpdateRepeatTypeWithTx(tx *gorm.DB, ids []uint, repeatType string) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	return r.db.Model(&models.Todo{}).
+	return tx.Model(&models.Todo{}).
 		Where("id IN (?)", ids).
 		Update("repeat_type", repeatType).Error
 }
@@ -312,10 +521,31 @@ func (r *TodoRepository) FindByStatus(userID uint, status string) ([]models.Todo
 
 // 批量操作
 func (r *TodoRepository) BatchCreate(todos []models.Todo) error {
+	return r.BatchCreateWithTx(r.db, todos)
+}
+
+// BatchCreateWithTx 支持事务的版本：批量创建待办，创建后回填ID
+func (r *TodoRepository) BatchCreateWithTx(tx *gorm.DB, todos []models.Todo) error {
 	if len(todos) == 0 {
 		return nil
 	}
-	return r.db.CreateInBatches(todos, 100).Error
+	return tx.CreateInBatches(todos, 100).Error
+}
+
+// FindOrganizationNames 查询组织名称，按组织ID索引
+func (r *TodoRepository) FindOrganizationNames(organizationIDs []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(organizationIDs))
+	if len(organizationIDs) == 0 {
+		return names, nil
+	}
+	var orgs []models.Organization
+	if err := r.db.Select("id", "name").Where("id IN ?", organizationIDs).Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+	return names, nil
 }
 
 // 事务支持
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"time"
+
+	"team_task_hub/backend/internal/models"
+)
+
+// todoStatsColumns 统计只需要的列，避免读取描述等大字段
+const todoStatsColumns = "id, status, category, start_time, end_time, completed_at"
+
+// FindDueForStats 查询截止时间在[from, to)内、未取消的待办实例，用于完成率统计
+func (r *TodoRepository) FindDueForStats(userID uint, from, to time.Time) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Select(todoStatsColumns).
+		Where("creator_user_id = ? AND has_children = ?", userID, false).
+		Where("status <> ?", "cancelled").
+		Where("end_time >= ? AND end_time < ?", from, to).
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindCompletionTimes 查询用户已完成待办实例的完成时间，按时间升序，since为零值时不限
+func (r *TodoRepository) FindCompletionTimes(userID uint, since time.Time) ([]time.Time, error) {
+	query := r.db.Model(&models.Todo{}).
+		Where("creator_user_id = ? AND has_children = ? AND status = ?", userID, false, "completed")
+	if !since.IsZero() {
+		query = query.Where("completed_at >= ?", since)
+	}
+
+	var times []time.Time
+	err := query.Order("completed_at ASC").Pluck("completed_at", &times).Error
+	return times, err
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TodoTemplateRepository 待办模板数据访问层
+type TodoTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewTodoTemplateRepository 构造函数：创建TodoTemplateRepository实例
+func NewTodoTemplateRepository(db *gorm.DB) *TodoTemplateRepository {
+	return &TodoTemplateRepository{db: db}
+}
+
+// preloadTemplateItems 按顺序预加载模板中的待办
+func preloadTemplateItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// visibleTemplateCondition 用户可见的模板：自己创建的，或共享到用户所在组织的
+const visibleTemplateCondition = "user_id = ? OR organization_id IN (?)"
+
+// activeOrgIDs 用户以活跃成员身份所在组织的子查询
+func (r *TodoTemplateRepository) activeOrgIDs(userID uint) *gorm.DB {
+	return r.db.Model(&models.OrganizationMember{}).
+		Select("organization_id").
+		Where("user_id = ? AND status = ?", userID, "active")
+}
+
+// FindVisible 查询用户可见的全部模板（含待办），自己创建的在前
+func (r *TodoTemplateRepository) FindVisible(userID uint) ([]models.TodoTemplate, error) {
+	var templates []models.TodoTemplate
+	err := r.db.
+		Where(visibleTemplateCondition, userID, r.activeOrgIDs(userID)).
+		Preload("Items", preloadTemplateItems).
+		Order(gorm.Expr("user_id = ? DESC", userID)).
+		Order("updated_at DESC, id DESC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// FindVisibleByID 根据ID查询用户可见的模板（含待办）
+func (r *TodoTemplateRepository) FindVisibleByID(userID, id uint) (*models.TodoTemplate, error) {
+	var template models.TodoTemplate
+	err := r.db.
+		Where(visibleTemplateCondition, userID, r.activeOrgIDs(userID)).
+		Preload("Items", preloadTemplateItems).
+		First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// CountByUser 统计用户创建的模板数量
+func (r *TodoTemplateRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TodoTemplate{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Create 创建模板及其待办
+func (r *TodoTemplateRepository) Create(template *models.TodoTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// UpdateWithItems 更新模板字段，items非nil时整体替换模板中的待办
+func (r *TodoTemplateRepository) UpdateWithItems(template *models.TodoTemplate, updates map[string]any, items []models.TodoTemplateItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(template).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if items == nil {
+			return nil
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&models.TodoTemplateItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].TemplateID = template.ID
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// Delete 删除模板及其待办
+func (r *TodoTemplateRepository) Delete(template *models.TodoTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", template.ID).Delete(&models.TodoTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(template).Error
+	})
+}
@@ -171,6 +171,20 @@ func (r *UserRepository) UpdateAvatar(userID uint, avatarURL string) error {
 	return nil
 }
 
+// UpdateTimezone 更新用户时区
+// userID: 用户ID
+// timezone: IANA时区名称，空字符串表示使用服务器时区
+func (r *UserRepository) UpdateTimezone(userID uint, timezone string) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("timezone", timezone)
+
+	if result.Error != nil {
+		return fmt.Errorf("更新时区失败: %v", result.Error)
+	}
+	return nil
+}
+
 // 更新用户邮箱
 func (r *UserRepository) UpdateEmail(userID uint, newEmail string) error {
 	result := r.db.Model(&models.User{}).
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupBoardRoutes 设置看板路由
+func SetupBoardRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	boardService := services.NewBoardService(
+		repositories.NewBoardRepository(db),
+		repositories.NewTodoRepository(db),
+		repositories.NewTagRepository(db),
+		NewTodoService(db),
+	)
+	boardHandler := handlers.NewBoardHandler(boardService)
+
+	boardGroup := router.Group("/api/boards")
+	boardGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		boardGroup.GET("", boardHandler.ListBoardsHandler)
+		boardGroup.POST("", boardHandler.CreateBoardHandler)
+		boardGroup.GET("/:id", boardHandler.GetBoardHandler)
+		boardGroup.PUT("/:id", boardHandler.UpdateBoardHandler)
+		boardGroup.DELETE("/:id", boardHandler.DeleteBoardHandler)
+		boardGroup.POST("/:id/move", boardHandler.MoveBoardCardHandler)
+	}
+}
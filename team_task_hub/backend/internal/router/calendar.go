@@ -0,0 +1,33 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupCalendarRoutes 设置日历导出与订阅路由
+func SetupCalendarRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	calendarService := services.NewCalendarService(
+		repositories.NewCalendarFeedRepository(db),
+		repositories.NewTodoRepository(db),
+		repositories.NewActivityRepository(db),
+	)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+
+	//订阅地址，通过token识别用户，不需要登录
+	router.GET("/api/calendar/feeds/:token", calendarHandler.CalendarFeedHandler)
+
+	calendarGroup := router.Group("/api/calendar")
+	calendarGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		calendarGroup.GET("/feed", calendarHandler.GetFeedHandler)
+		calendarGroup.POST("/feed/reset", calendarHandler.ResetFeedHandler)
+		calendarGroup.DELETE("/feed", calendarHandler.RevokeFeedHandler)
+		calendarGroup.GET("/export.ics", calendarHandler.ExportCalendarHandler)
+	}
+}
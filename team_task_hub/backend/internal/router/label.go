@@ -0,0 +1,38 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupLabelRoutes 设置分类与标签路由
+func SetupLabelRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	categoryService := services.NewCategoryService(repositories.NewCategoryRepository(db))
+	tagService := services.NewTagService(repositories.NewTagRepository(db))
+	labelHandler := handlers.NewLabelHandler(categoryService, tagService)
+
+	//分类
+	categoryGroup := router.Group("/api/categories")
+	categoryGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		categoryGroup.GET("", labelHandler.ListCategoriesHandler)
+		categoryGroup.POST("", labelHandler.CreateCategoryHandler)
+		categoryGroup.PUT("/:id", labelHandler.UpdateCategoryHandler)
+		categoryGroup.DELETE("/:id", labelHandler.DeleteCategoryHandler)
+	}
+
+	//标签
+	tagGroup := router.Group("/api/tags")
+	tagGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		tagGroup.GET("", labelHandler.ListTagsHandler)
+		tagGroup.POST("", labelHandler.CreateTagHandler)
+		tagGroup.PUT("/:id", labelHandler.UpdateTagHandler)
+		tagGroup.DELETE("/:id", labelHandler.DeleteTagHandler)
+	}
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupNotificationRoutes 设置站内通知路由
+func SetupNotificationRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	notificationGroup := router.Group("/api/notifications")
+	notificationGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		notificationGroup.GET("", notificationHandler.ListNotificationsHandler)
+		notificationGroup.POST("/read", notificationHandler.MarkNotificationsReadHandler)
+	}
+}
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupOrgTodoRoutes 设置组织待办路由
+func SetupOrgTodoRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	orgMemberRepo := repositories.NewOrganizationMemberRepository(db)
+	orgService := services.NewOrganizationService(
+		repositories.NewOrganizationRepository(db),
+		orgMemberRepo,
+		repositories.NewOrganizationApplicationRepository(db),
+		repositories.NewVerificationCodeRepository(db),
+		repositories.NewPermissionRepository(db),
+	)
+	orgTodoService := services.NewOrgTodoService(
+		repositories.NewTodoRepository(db),
+		repositories.NewTodoAssignmentRepository(db),
+		orgMemberRepo,
+		repositories.NewCategoryRepository(db),
+		repositories.NewUserRepository(db),
+		services.NewNotificationService(repositories.NewNotificationRepository(db)),
+	)
+	orgTodoHandler := handlers.NewOrgTodoHandler(orgTodoService)
+
+	orgTodoGroup := router.Group("/api/organization/:orgID/todos")
+	{
+		// 需要publish_todo权限的路由
+		publishRoutes := orgTodoGroup.Group("")
+		publishRoutes.Use(middleware.CreatePermissionChain(authService, orgService, models.PermissionPublishTodo)...)
+		{
+			publishRoutes.POST("", orgTodoHandler.PublishOrgTodoHandler)
+			publishRoutes.POST("/:todoID/assign", orgTodoHandler.AssignOrgTodoHandler)
+		}
+
+		// 需要delete_todo权限的路由
+		deleteRoutes := orgTodoGroup.Group("")
+		deleteRoutes.Use(middleware.CreatePermissionChain(authService, orgService, models.PermissionDeleteTodo)...)
+		{
+			deleteRoutes.DELETE("/:todoID", orgTodoHandler.DeleteOrgTodoHandler)
+		}
+
+		// 需要组织成员权限的路由
+		memberRoutes := orgTodoGroup.Group("")
+		memberRoutes.Use(middleware.CreateAuthChain(authService, orgService, "member")...)
+		{
+			memberRoutes.GET("", orgTodoHandler.ListOrgTodosHandler)
+			memberRoutes.GET("/:todoID/assignments", orgTodoHandler.ListOrgTodoAssignmentsHandler)
+		}
+	}
+
+	// 成员处理分配给自己的组织待办
+	assignmentGroup := router.Group("/api/todos/assignments")
+	assignmentGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		assignmentGroup.GET("", orgTodoHandler.ListMyAssignmentsHandler)
+		assignmentGroup.POST("/:id/accept", orgTodoHandler.AcceptAssignmentHandler)
+		assignmentGroup.POST("/:id/reject", orgTodoHandler.RejectAssignmentHandler)
+	}
+}
@@ -20,9 +20,13 @@ func SetupOrganizationRoutes(router *gin.Engine, db *gorm.DB, authService *servi
 	codeRepo := repositories.NewVerificationCodeRepository(db)
 	activityRepo := repositories.NewActivityRepository(db)
 	participationRepo := repositories.NewActivityParticipationRepository(db)
+	todoRepo := repositories.NewTodoRepository(db)
+	permRepo := repositories.NewPermissionRepository(db)
+	reminderRepo := repositories.NewReminderRepository(db)
 
-	orgService := services.NewOrganizationService(orgRepo, orgMemberRepo, orgAppRepo, codeRepo)
-	activityService := services.NewActivityService(activityRepo, participationRepo)
+	orgService := services.NewOrganizationService(orgRepo, orgMemberRepo, orgAppRepo, codeRepo, permRepo)
+	conflictService := services.NewConflictService(todoRepo, activityRepo)
+	activityService := services.NewActivityService(activityRepo, participationRepo, reminderRepo, conflictService)
 	orgHandler := handlers.NewOrganizationHandler(orgService, activityService)
 
 	//需要超级管理员权限的路由
@@ -74,6 +78,11 @@ func SetupOrganizationRoutes(router *gin.Engine, db *gorm.DB, authService *servi
 			adminRoutes.PATCH("/:orgID/activities/:activityID/complete-user", orgHandler.CompleteActivitiesForUsersHandler)
 			adminRoutes.PATCH("/:orgID/activities/:activityID/complete-activity", orgHandler.CompleteActivityHandler)
 
+			//成员权限
+			adminRoutes.GET("/:orgID/permissions", orgHandler.GetOrgPermissionsHandler)
+			adminRoutes.POST("/:orgID/permissions", orgHandler.GrantPermissionHandler)
+			adminRoutes.DELETE("/:orgID/permissions/:userID/:permissionType", orgHandler.RevokePermissionHandler)
+
 		}
 
 		// 需要组织成员权限的路由
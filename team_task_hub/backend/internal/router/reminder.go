@@ -0,0 +1,31 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupReminderRoutes 设置提醒路由
+func SetupReminderRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	reminderService := services.NewReminderService(
+		repositories.NewReminderRepository(db),
+		repositories.NewTodoRepository(db),
+		repositories.NewActivityRepository(db),
+		repositories.NewActivityParticipationRepository(db),
+		repositories.NewUserRepository(db),
+	)
+	reminderHandler := handlers.NewReminderHandler(reminderService)
+
+	reminderGroup := router.Group("/api/reminders")
+	reminderGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		reminderGroup.GET("", reminderHandler.ListRemindersHandler)
+		reminderGroup.POST("", reminderHandler.CreateReminderHandler)
+		reminderGroup.DELETE("/:id", reminderHandler.CancelReminderHandler)
+	}
+}
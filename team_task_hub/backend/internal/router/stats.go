@@ -0,0 +1,28 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupStatsRoutes 设置统计路由
+func SetupStatsRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	statsService := services.NewStatsService(
+		repositories.NewTodoRepository(db),
+		repositories.NewUserRepository(db),
+	)
+	statsHandler := handlers.NewStatsHandler(statsService)
+
+	statsGroup := router.Group("/api/stats")
+	statsGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		statsGroup.GET("/overview", statsHandler.GetStatsOverviewHandler)
+		statsGroup.GET("/trends", statsHandler.GetStatsTrendsHandler)
+		statsGroup.GET("/streaks", statsHandler.GetStreaksHandler)
+	}
+}
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupTemplateRoutes 设置待办模板路由
+func SetupTemplateRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	templateService := services.NewTemplateService(
+		repositories.NewTodoTemplateRepository(db),
+		repositories.NewOrganizationMemberRepository(db),
+		NewTodoService(db),
+	)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+
+	templateGroup := router.Group("/api/todo-templates")
+	templateGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		templateGroup.GET("", templateHandler.ListTemplatesHandler)
+		templateGroup.POST("", templateHandler.CreateTemplateHandler)
+		templateGroup.GET("/:id", templateHandler.GetTemplateHandler)
+		templateGroup.PUT("/:id", templateHandler.UpdateTemplateHandler)
+		templateGroup.DELETE("/:id", templateHandler.DeleteTemplateHandler)
+		templateGroup.POST("/:id/instantiate", templateHandler.InstantiateTemplateHandler)
+	}
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"team_task_hub/backend/internal/handlers"
+	"team_task_hub/backend/internal/middleware"
+	"team_task_hub/backend/internal/repositories"
+	"team_task_hub/backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupTimeTrackingRoutes 设置用时记录路由
+func SetupTimeTrackingRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
+	timeTrackingService := services.NewTimeTrackingService(
+		repositories.NewTimeEntryRepository(db),
+		repositories.NewTodoRepository(db),
+		repositories.NewUserRepository(db),
+	)
+	timeTrackingHandler := handlers.NewTimeTrackingHandler(timeTrackingService)
+
+	timeGroup := router.Group("/api/time")
+	timeGroup.Use(middleware.AuthMiddleware(authService))
+	{
+		//计时器
+		timeGroup.GET("/timer", timeTrackingHandler.GetRunningTimerHandler)
+		timeGroup.POST("/timer", timeTrackingHandler.StartTimerHandler)
+		timeGroup.POST("/timer/stop", timeTrackingHandler.StopTimerHandler)
+
+		//用时记录
+		timeGroup.GET("/entries", timeTrackingHandler.ListTimeEntriesHandler)
+		timeGroup.POST("/entries", timeTrackingHandler.AddTimeEntryHandler)
+		timeGroup.DELETE("/entries/:id", timeTrackingHandler.DeleteTimeEntryHandler)
+
+		//统计
+		timeGroup.GET("/summary", timeTrackingHandler.GetTimeSummaryHandler)
+		timeGroup.GET("/report", timeTrackingHandler.GetTimeReportHandler)
+	}
+}
@@ -10,19 +10,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// NewTodoService 根据数据库连接组装待办服务，待办、看板、模板路由与后台定时任务共用
+func NewTodoService(db *gorm.DB) *services.TodoService {
+	todoRepo := repositories.NewTodoRepository(db)
+	activityRepo := repositories.NewActivityRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	checklistRepo := repositories.NewChecklistRepository(db)
+	dependencyRepo := repositories.NewDependencyRepository(db)
+	historyRepo := repositories.NewTodoHistoryRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	boardRepo := repositories.NewBoardRepository(db)
+	preferenceRepo := repositories.NewSchedulePreferenceRepository(db)
+	reminderRepo := repositories.NewReminderRepository(db)
+	timeEntryRepo := repositories.NewTimeEntryRepository(db)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	conflictService := services.NewConflictService(todoRepo, activityRepo)
+	return services.NewTodoService(todoRepo, activityRepo, categoryRepo, tagRepo, checklistRepo, dependencyRepo, historyRepo, userRepo, boardRepo, preferenceRepo, reminderRepo, timeEntryRepo, notificationService, conflictService)
+}
+
 // SetupTodoRoutes 设置待办路由
 func SetupTodoRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService) {
 	// 待办事项路由组
-	todoRepo := repositories.NewTodoRepository(db)
-	activityRepo := repositories.NewActivityRepository(db)
-	todoService := services.NewTodoService(todoRepo, activityRepo)
-	todoHandler := handlers.NewTodoHandler(todoService)
+	todoHandler := handlers.NewTodoHandler(NewTodoService(db))
 
 	todoGroup := router.Group("/api/todos")
 	todoGroup.Use(middleware.AuthMiddleware(authService))
 	{
 		//个人待办
+		todoGroup.GET("", todoHandler.ListTodosHandler)
+		todoGroup.GET("/agenda", todoHandler.GetAgendaHandler)
 		todoGroup.POST("/createTodo", todoHandler.AddTodoHandler)
+		todoGroup.POST("/import", todoHandler.ImportTodosHandler)
+		todoGroup.POST("/quick-add", todoHandler.QuickAddTodoHandler)
+		todoGroup.GET("/conflicts", todoHandler.CheckConflictsHandler)
 		todoGroup.POST("/updateTodos", todoHandler.UpdateTodos)
 		todoGroup.POST("/cancel", todoHandler.CancelTodoByDetails)
 		todoGroup.POST("/cancel-with-children", todoHandler.CancelTodoAndChildren)
@@ -35,6 +56,42 @@ func SetupTodoRoutes(router *gin.Engine, db *gorm.DB, authService *services.Auth
 		todoGroup.GET("/Get-OneDayTodos", todoHandler.GetOneDayTodos)
 		todoGroup.GET("/get-OneDayExpiredTodos", todoHandler.GetOneDayExpiredTodos)
 
+		//弹性待办自动排程
+		todoGroup.POST("/flexible", todoHandler.CreateFlexibleTodoHandler)
+		todoGroup.POST("/flexible/replan", todoHandler.ReplanFlexibleTodosHandler)
+		todoGroup.GET("/schedule-preferences", todoHandler.GetSchedulePreferenceHandler)
+		todoGroup.PUT("/schedule-preferences", todoHandler.UpdateSchedulePreferenceHandler)
+
+		//回收站
+		todoGroup.GET("/trash", todoHandler.ListTrashHandler)
+		todoGroup.DELETE("/trash/:id", todoHandler.PurgeTodoHandler)
+		todoGroup.POST("/:id/restore", todoHandler.RestoreTodoHandler)
+
+		//基于ID的待办操作
+		todoGroup.GET("/:id", todoHandler.GetTodoHandler)
+		todoGroup.PUT("/:id", todoHandler.ReplaceTodoHandler)
+		todoGroup.PATCH("/:id", todoHandler.PatchTodoHandler)
+		todoGroup.DELETE("/:id", todoHandler.DeleteTodoHandler)
+		todoGroup.PATCH("/:id/edit", todoHandler.EditTodoHandler)
+		todoGroup.POST("/:id/start", todoHandler.StartTodoHandler)
+		todoGroup.POST("/:id/snooze", todoHandler.SnoozeTodoHandler)
+
+		//变更历史
+		todoGroup.GET("/:id/history", todoHandler.GetTodoHistoryHandler)
+		todoGroup.POST("/:id/undo", todoHandler.UndoTodoHandler)
+
+		//待办清单
+		todoGroup.GET("/:id/checklist", todoHandler.GetChecklistHandler)
+		todoGroup.POST("/:id/checklist", todoHandler.AddChecklistItemHandler)
+		todoGroup.PUT("/:id/checklist/order", todoHandler.ReorderChecklistHandler)
+		todoGroup.PATCH("/:id/checklist/:itemID", todoHandler.UpdateChecklistItemHandler)
+		todoGroup.DELETE("/:id/checklist/:itemID", todoHandler.DeleteChecklistItemHandler)
+
+		//待办依赖
+		todoGroup.GET("/:id/dependencies", todoHandler.GetDependenciesHandler)
+		todoGroup.POST("/:id/dependencies", todoHandler.AddDependencyHandler)
+		todoGroup.DELETE("/:id/dependencies/:blockerID", todoHandler.RemoveDependencyHandler)
+
 		//组织待办
 		todoGroup.GET("/organizations/today", todoHandler.GetTodayOrganizationTodos)
 		todoGroup.GET("/activities/today", todoHandler.GetTodayOrgTodos)
@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"team_task_hub/backend/internal/services"
+)
+
+// RegisterReminderJobs 注册提醒投递定时任务
+func RegisterReminderJobs(s *Scheduler, reminderService *services.ReminderService, interval time.Duration) {
+	s.Register(Job{
+		Name:     "reminder-dispatch",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			sent, err := reminderService.DispatchDueReminders()
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				log.Printf("提醒投递完成: 发送 %d 条", sent)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"team_task_hub/backend/internal/cache"
+)
+
+// Job 定时任务
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler 进程内定时任务调度器
+// 多副本部署时通过Redis锁保证同一任务同一时刻只有一个实例在执行；
+// 单实例部署时Redis不可用不影响任务执行，不加锁直接运行
+type Scheduler struct {
+	jobs           []Job
+	token          string
+	singleInstance bool
+	unlockedOnce   sync.Once
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+// New 创建调度器，singleInstance表示只部署了一个实例，Redis不可用时可以不加锁执行任务
+func New(singleInstance bool) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		token:          fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		singleInstance: singleInstance,
+	}
+}
+
+// Register 注册定时任务，需在Start之前调用
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start 启动所有任务，每个任务在独立的goroutine中运行，启动后立即执行一次
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			log.Printf("定时任务 %s 间隔无效(%v)，未启动", job.Name, job.Interval)
+			continue
+		}
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+		log.Printf("定时任务已启动: %s, 间隔 %v", job.Name, job.Interval)
+	}
+}
+
+// Stop 停止调度器并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	log.Println("定时任务已全部停止")
+}
+
+// loop 按间隔循环执行任务
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.runOnce(ctx, job)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+// lockTTL 任务锁的有效期，比执行间隔略短，
+// 保证本实例下一次触发时锁已过期，不会因为锁未释放而每两个间隔才执行一次
+func lockTTL(interval time.Duration) time.Duration {
+	return interval - interval/10
+}
+
+// runOnce 获取锁后执行一次任务，锁被其他实例持有时跳过本轮
+// 获取锁出错时，单实例部署不加锁执行，多副本部署跳过本轮
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("定时任务 %s 发生panic: %v", job.Name, r)
+		}
+	}()
+
+	lockName := "scheduler:" + job.Name
+	locked := true
+	acquired, err := cache.AcquireLock(lockName, s.token, lockTTL(job.Interval))
+	switch {
+	case err != nil && s.singleInstance:
+		s.unlockedOnce.Do(func() {
+			log.Printf("定时任务获取锁失败，单实例部署下不加锁执行: %v", err)
+		})
+		locked = false
+	case err != nil:
+		log.Printf("定时任务 %s 获取锁失败，跳过本轮: %v", job.Name, err)
+		return
+	case !acquired:
+		return
+	}
+
+	// 锁保留到过期，保证多副本在同一间隔内只执行一次；执行失败时释放以便其他实例重试
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("定时任务 %s 执行失败: %v", job.Name, err)
+		if !locked {
+			return
+		}
+		if err := cache.ReleaseLock(lockName, s.token); err != nil && !errors.Is(err, cache.ErrCacheUnavailable) {
+			log.Printf("定时任务 %s 释放锁失败: %v", job.Name, err)
+		}
+		return
+	}
+	log.Printf("定时任务 %s 执行完成，耗时 %v", job.Name, time.Since(start))
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"team_task_hub/backend/internal/services"
+)
+
+// RegisterTodoJobs 注册待办相关的定时任务
+func RegisterTodoJobs(s *Scheduler, todoService *services.TodoService, renewInterval, overdueInterval time.Duration) {
+	s.Register(Job{
+		Name:     "todo-renew",
+		Interval: renewInterval,
+		Run: func(ctx context.Context) error {
+			renewed, err := todoService.RenewAllSeries()
+			if err != nil {
+				return err
+			}
+			if renewed > 0 {
+				log.Printf("后台续期完成: 生成 %d 个新实例", renewed)
+			}
+			return nil
+		},
+	})
+
+	s.Register(Job{
+		Name:     "todo-overdue",
+		Interval: overdueInterval,
+		Run: func(ctx context.Context) error {
+			marked, cleared, err := todoService.RefreshOverdueFlags()
+			if err != nil {
+				return err
+			}
+			if marked > 0 || cleared > 0 {
+				log.Printf("逾期标记刷新完成: 新增 %d, 清除 %d", marked, cleared)
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterTrashJobs 注册回收站清理任务，彻底删除超过保留期的待办
+func RegisterTrashJobs(s *Scheduler, todoService *services.TodoService, interval, retention time.Duration) {
+	s.Register(Job{
+		Name:     "todo-purge",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purged, err := todoService.PurgeExpiredTrash(retention)
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				log.Printf("回收站清理完成: 彻底删除 %d 个待办", purged)
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterAutoScheduleJobs 注册弹性待办自动排程任务，重新安排因日程变化或时间流逝而失效的弹性待办
+func RegisterAutoScheduleJobs(s *Scheduler, todoService *services.TodoService, interval time.Duration) {
+	s.Register(Job{
+		Name:     "todo-auto-schedule",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			moved, err := todoService.ReplanAllFlexibleTodos()
+			if err != nil {
+				return err
+			}
+			if moved > 0 {
+				log.Printf("自动排程完成: 重新安排 %d 个弹性待办", moved)
+			}
+			return nil
+		},
+	})
+}
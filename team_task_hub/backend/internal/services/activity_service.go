@@ -11,14 +11,19 @@ import (
 type ActivityService struct {
 	activityRepo      *repositories.ActivityRepository
 	participationRepo *repositories.ActivityParticipationRepository
+	reminderRepo      *repositories.ReminderRepository
+	conflictService   *ConflictService
 }
 
 // NewActivityService 创建活动服务实例
 func NewActivityService(activityRepo *repositories.ActivityRepository,
-	participationRepo *repositories.ActivityParticipationRepository) *ActivityService {
+	participationRepo *repositories.ActivityParticipationRepository, reminderRepo *repositories.ReminderRepository,
+	conflictService *ConflictService) *ActivityService {
 	return &ActivityService{
 		activityRepo:      activityRepo,
 		participationRepo: participationRepo,
+		reminderRepo:      reminderRepo,
+		conflictService:   conflictService,
 	}
 }
 
@@ -134,6 +139,13 @@ func (s *ActivityService) UpdateActivity(activityID uint, req *UpdateActivityReq
 		return fmt.Errorf("更新活动失败: %v", err)
 	}
 
+	// 活动时间变化后，参与者的相对提醒跟随新时间
+	if !req.StartTime.IsZero() || !req.EndTime.IsZero() {
+		if err := s.reminderRepo.SyncRelativeRemindAt(models.ReminderTargetActivity, activityID); err != nil {
+			return fmt.Errorf("同步提醒时间失败: %v", err)
+		}
+	}
+
 	//将所有参与者都改成未读
 	err = s.participationRepo.MarkActivityAsUnRead(activityID)
 	if err != nil {
@@ -172,13 +184,27 @@ func (s *ActivityService) GetActivityUsers(activityID uint) ([]models.UserInfo,
 	return userInfos, nil
 }
 
-// ParticipateActivity 参加/领取活动
-func (s *ActivityService) ParticipateActivity(activityID, userID uint) error {
+// ParticipateActivity 参加/领取活动，返回与活动时间重叠的待办和活动
+// policy为block且存在冲突时不参加，返回ErrScheduleConflict
+func (s *ActivityService) ParticipateActivity(activityID, userID uint, policy string) ([]ScheduleConflict, error) {
 	//检查是否参加过该活动
 	if exist, err := s.participationRepo.Exists(activityID, userID); err != nil {
-		return fmt.Errorf("查询是否存在重复记录失败，原因：%v", err)
+		return nil, fmt.Errorf("查询是否存在重复记录失败，原因：%v", err)
 	} else if exist {
-		return fmt.Errorf("已经参加过本次活动，不能重复参加")
+		return nil, fmt.Errorf("已经参加过本次活动，不能重复参加")
+	}
+
+	//检查时间冲突
+	activity, err := s.activityRepo.GetByID(activityID)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.conflictService.FindConflicts(userID, activity.StartTime, activity.EndTime, activityID)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && blocksOnConflict(policy) {
+		return conflicts, ErrScheduleConflict
 	}
 
 	//在活动参与表中插入新活动参与记录
@@ -187,23 +213,40 @@ func (s *ActivityService) ParticipateActivity(activityID, userID uint) error {
 		UserID:     userID,
 	}
 
-	err := s.participationRepo.Create(activityParticipation)
+	err = s.participationRepo.Create(activityParticipation)
 	if err != nil {
-		return fmt.Errorf("参加活动失败,插入活动记录表失败，原因：%v", err)
+		return nil, fmt.Errorf("参加活动失败,插入活动记录表失败，原因：%v", err)
 	}
 
-	return nil
+	return conflicts, nil
 }
 
-// ForceAssignActivityToUsers 强制分配活动给一批用户
-func (s *ActivityService) ForceAssignActivityToUsers(activityID uint, userIDs []uint) error {
+// ForceAssignActivityToUsers 强制分配活动给一批用户，返回存在时间冲突的用户及冲突项
+// policy为block且任一用户存在冲突时不分配给任何人，返回ErrScheduleConflict
+func (s *ActivityService) ForceAssignActivityToUsers(activityID uint, userIDs []uint, policy string) ([]UserScheduleConflicts, error) {
 	// 基础验证
 	if len(userIDs) == 0 {
-		return fmt.Errorf("用户ID列表不能为空")
+		return nil, fmt.Errorf("用户ID列表不能为空")
+	}
+
+	// 检查每个用户的时间冲突
+	activity, err := s.activityRepo.GetByID(activityID)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.conflictService.FindUsersConflicts(userIDs, activity.StartTime, activity.EndTime, activityID)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && blocksOnConflict(policy) {
+		return conflicts, ErrScheduleConflict
 	}
 
 	// 调用数据访问层执行批量分配
-	return s.participationRepo.BatchCreateForcedAssignments(activityID, userIDs)
+	if err := s.participationRepo.BatchCreateForcedAssignments(activityID, userIDs); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
 }
 
 // GetUnreadActivitiesByUserID 获取用户所有未读活动
@@ -383,6 +383,19 @@ func (s *AuthService) UpdateAvatar(userID uint, newAvatar string) error {
 	return nil
 }
 
+// 更新时区，空字符串表示恢复使用服务器时区
+func (s *AuthService) UpdateTimezone(userID uint, timezone string) error {
+	timezone = strings.TrimSpace(timezone)
+	if timezone != "" && !models.ValidTimezone(timezone) {
+		return fmt.Errorf("%w %s", ErrInvalidTimezone, timezone)
+	}
+	if err := s.userRepo.UpdateTimezone(userID, timezone); err != nil {
+		return errors.New("更新时区失败")
+	}
+	cache.DeleteUser(userID)
+	return nil
+}
+
 // 更新用户邮箱
 func (s *AuthService) UpdateEmail(userID uint, oldEmail, newEmail, oldEmailCode, newEmailCode string) (string, error) {
 	// 验证旧邮箱
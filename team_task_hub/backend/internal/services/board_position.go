@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// positionDigits 看板排序键使用的62进制数字，按ASCII升序排列，排序键按字节序比较
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// maxPositionLength 排序键的长度上限，超过后整列重新分配排序键
+const maxPositionLength = 64
+
+// positionBetween 生成严格介于a和b之间的排序键，a为空表示列首，b为空表示列尾
+//
+// 排序键是62进制小数的小数部分（不以0结尾），任意两个键之间总能插入新的键，
+// 因此移动卡片只需改写被移动的那一张
+func positionBetween(a, b string) (string, error) {
+	if !validPosition(a) || !validPosition(b) {
+		return "", fmt.Errorf("无效的排序键: %q, %q", a, b)
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("排序键顺序错误: %q >= %q", a, b)
+	}
+	return positionMidpoint(a, b), nil
+}
+
+// positionMidpoint 计算a与b之间的排序键，b为空表示上界为1
+func positionMidpoint(a, b string) string {
+	if b != "" {
+		// 跳过公共前缀，a较短时按末尾补0比较
+		n := 0
+		for n < len(b) && positionDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + positionMidpoint(rest, b[n:])
+		}
+	}
+
+	low := 0
+	if a != "" {
+		low = strings.IndexByte(positionDigits, a[0])
+	}
+	high := len(positionDigits)
+	if b != "" {
+		high = strings.IndexByte(positionDigits, b[0])
+	}
+	if high-low > 1 {
+		return string(positionDigits[(low+high+1)/2])
+	}
+	// 首位相邻：b有更多位时取b的首位即可，否则在a的首位之后继续细分
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(positionDigits[low]) + positionMidpoint(rest, "")
+}
+
+// positionDigitAt 返回排序键第i位，超出长度时视为0
+func positionDigitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return positionDigits[0]
+}
+
+// validPosition 校验排序键只包含62进制数字且不以0结尾
+func validPosition(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(positionDigits, key[i]) < 0 {
+			return false
+		}
+	}
+	return key == "" || key[len(key)-1] != positionDigits[0]
+}
+
+// evenPositions 为n张卡片生成等距的递增排序键，用于整列重新分配
+func evenPositions(n int) []string {
+	base := len(positionDigits)
+	width, capacity := 1, base
+	for capacity <= n {
+		width++
+		capacity *= base
+	}
+
+	keys := make([]string, n)
+	buf := make([]byte, width)
+	for i := range keys {
+		value := (i + 1) * capacity / (n + 1)
+		for j := width - 1; j >= 0; j-- {
+			buf[j] = positionDigits[value%base]
+			value /= base
+		}
+		// 去掉末尾的0不改变相对顺序
+		keys[i] = strings.TrimRight(string(buf), positionDigits[:1])
+	}
+	return keys
+}
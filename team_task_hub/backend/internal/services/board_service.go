@@ -0,0 +1,547 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrBoardNotFound 看板不存在或不属于当前用户
+var ErrBoardNotFound = errors.New("看板不存在")
+
+const (
+	// maxBoardsPerUser 每个用户最多创建的看板数量
+	maxBoardsPerUser = 20
+	// maxBoardColumns 每个看板最多的列数
+	maxBoardColumns = 10
+	// defaultBoardCardLimit 看板每列默认返回的待办数量
+	defaultBoardCardLimit = 100
+	// maxBoardCardLimit 看板每列最多返回的待办数量
+	maxBoardCardLimit = 500
+)
+
+// BoardService 看板服务层，卡片移动等待办变更委托给TodoService
+type BoardService struct {
+	boardRepo   *repositories.BoardRepository
+	todoRepo    *repositories.TodoRepository
+	tagRepo     *repositories.TagRepository
+	todoService *TodoService
+}
+
+// NewBoardService 创建看板服务实例
+func NewBoardService(boardRepo *repositories.BoardRepository, todoRepo *repositories.TodoRepository,
+	tagRepo *repositories.TagRepository, todoService *TodoService) *BoardService {
+	return &BoardService{
+		boardRepo:   boardRepo,
+		todoRepo:    todoRepo,
+		tagRepo:     tagRepo,
+		todoService: todoService,
+	}
+}
+
+// BoardColumnRequest 看板列配置，status看板填写status，tag看板填写tag（不存在时自动创建）
+type BoardColumnRequest struct {
+	Name     string `json:"name" binding:"required,max=50" example:"进行中"`
+	Status   string `json:"status" binding:"omitempty,oneof=pending in_progress completed cancelled" example:"in_progress"`
+	Tag      string `json:"tag" example:"前端"`
+	WIPLimit int    `json:"wip_limit" binding:"min=0" example:"3"`
+}
+
+// CreateBoardRequest 创建看板请求
+type CreateBoardRequest struct {
+	Name    string               `json:"name" binding:"required,max=100" example:"迭代看板"`
+	GroupBy string               `json:"group_by" binding:"omitempty,oneof=status tag" example:"status"` // 默认status
+	Columns []BoardColumnRequest `json:"columns" binding:"required,min=1,dive"`
+}
+
+// UpdateBoardRequest 更新看板请求，columns非空时整体替换看板列，分列方式不可修改
+type UpdateBoardRequest struct {
+	Name    *string              `json:"name,omitempty" binding:"omitempty,max=100" example:"迭代看板"`
+	Columns []BoardColumnRequest `json:"columns,omitempty" binding:"omitempty,dive"`
+}
+
+// MoveBoardCardRequest 移动看板卡片请求，after_id优先于before_id，两者都为空时放到列尾
+type MoveBoardCardRequest struct {
+	TodoID   uint `json:"todo_id" binding:"required" example:"12"`
+	ColumnID uint `json:"column_id" binding:"required" example:"3"`
+	AfterID  uint `json:"after_id" example:"8"`  // 放在该待办之后
+	BeforeID uint `json:"before_id" example:"0"` // 放在该待办之前
+}
+
+// BoardColumnView 看板的一列及其中的待办
+type BoardColumnView struct {
+	models.BoardColumn
+	TagName     string        `json:"tag_name,omitempty"`
+	Total       int           `json:"total"`        // 列中待办总数，可能大于返回的数量
+	WIPExceeded bool          `json:"wip_exceeded"` // 列中待办数量超过在制品上限
+	Todos       []models.Todo `json:"todos"`
+}
+
+// BoardView 看板及各列待办
+type BoardView struct {
+	ID        uint              `json:"id"`
+	Name      string            `json:"name"`
+	GroupBy   string            `json:"group_by"`
+	IsDefault bool              `json:"is_default"`
+	Columns   []BoardColumnView `json:"columns"`
+}
+
+// ListBoards 查询用户的看板，没有任何看板时创建默认的状态看板
+func (s *BoardService) ListBoards(userID uint) ([]models.Board, error) {
+	boards, err := s.boardRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询看板失败: %v", err)
+	}
+	if len(boards) > 0 {
+		return boards, nil
+	}
+
+	board := &models.Board{
+		UserID:    userID,
+		Name:      "我的看板",
+		GroupBy:   models.BoardGroupByStatus,
+		IsDefault: true,
+		Columns: []models.BoardColumn{
+			{Name: "待办", Position: 0, Status: TodoStatusPending},
+			{Name: "进行中", Position: 1, Status: TodoStatusInProgress},
+			{Name: "已完成", Position: 2, Status: TodoStatusCompleted},
+		},
+	}
+	if err := s.boardRepo.Create(board); err != nil {
+		return nil, fmt.Errorf("创建默认看板失败: %v", err)
+	}
+	return []models.Board{*board}, nil
+}
+
+// CreateBoard 创建看板
+func (s *BoardService) CreateBoard(userID uint, req *CreateBoardRequest) (*models.Board, error) {
+	count, err := s.boardRepo.CountByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询看板失败: %v", err)
+	}
+	if count >= maxBoardsPerUser {
+		return nil, fmt.Errorf("%w: 最多创建%d个看板", ErrInvalidTodoParams, maxBoardsPerUser)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 看板名称不能为空", ErrInvalidTodoParams)
+	}
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = models.BoardGroupByStatus
+	}
+	columns, err := s.buildBoardColumns(userID, groupBy, req.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &models.Board{
+		UserID:  userID,
+		Name:    name,
+		GroupBy: groupBy,
+		Columns: columns,
+	}
+	if err := s.boardRepo.Create(board); err != nil {
+		return nil, fmt.Errorf("创建看板失败: %v", err)
+	}
+	return board, nil
+}
+
+// UpdateBoard 修改看板名称或替换看板列，已有的排序键保持不变
+func (s *BoardService) UpdateBoard(userID, boardID uint, req *UpdateBoardRequest) (*models.Board, error) {
+	board, err := s.findBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+	name := board.Name
+	if req.Name != nil {
+		if name = strings.TrimSpace(*req.Name); name == "" {
+			return nil, fmt.Errorf("%w: 看板名称不能为空", ErrInvalidTodoParams)
+		}
+	}
+	var columns []models.BoardColumn
+	if len(req.Columns) > 0 {
+		if columns, err = s.buildBoardColumns(userID, board.GroupBy, req.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.boardRepo.UpdateWithColumns(board, name, columns); err != nil {
+		return nil, fmt.Errorf("更新看板失败: %v", err)
+	}
+	return s.findBoard(userID, boardID)
+}
+
+// DeleteBoard 删除看板，默认看板不能删除
+func (s *BoardService) DeleteBoard(userID, boardID uint) error {
+	board, err := s.findBoard(userID, boardID)
+	if err != nil {
+		return err
+	}
+	if board.IsDefault {
+		return fmt.Errorf("%w: 默认看板不能删除", ErrInvalidTodoParams)
+	}
+	if err := s.boardRepo.Delete(board); err != nil {
+		return fmt.Errorf("删除看板失败: %v", err)
+	}
+	return nil
+}
+
+// GetBoard 查询看板及每列的待办，列内先按排序键、再按开始时间排列，limit为每列返回的数量上限
+func (s *BoardService) GetBoard(userID, boardID uint, limit int) (*BoardView, error) {
+	if limit <= 0 {
+		limit = defaultBoardCardLimit
+	}
+	limit = min(limit, maxBoardCardLimit)
+
+	board, err := s.findBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.boardRepo.FindCardPositions(board.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询看板排序失败: %v", err)
+	}
+	layout, err := s.loadBoardLayout(board, positions)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &BoardView{
+		ID:        board.ID,
+		Name:      board.Name,
+		GroupBy:   board.GroupBy,
+		IsDefault: board.IsDefault,
+		Columns:   make([]BoardColumnView, 0, len(board.Columns)),
+	}
+	var shown []models.Todo
+	for i, column := range board.Columns {
+		todos := layout.columns[i]
+		columnView := BoardColumnView{
+			BoardColumn: column,
+			TagName:     layout.tagNames[column.TagID],
+			Total:       len(todos),
+			WIPExceeded: column.WIPLimit > 0 && len(todos) > column.WIPLimit,
+		}
+		if len(todos) > limit {
+			todos = todos[:limit]
+		}
+		columnView.Todos = todos
+		shown = append(shown, todos...)
+		view.Columns = append(view.Columns, columnView)
+	}
+
+	// 统一填充详情后再按顺序放回各列
+	if err := s.todoService.attachTodoDetails(shown); err != nil {
+		return nil, err
+	}
+	offset := 0
+	for i := range view.Columns {
+		n := len(view.Columns[i].Todos)
+		view.Columns[i].Todos = shown[offset : offset+n]
+		offset += n
+	}
+	return view, nil
+}
+
+// MoveBoardCard 将待办移动到看板的某一列的指定位置，状态或标签与排序键在同一事务中更新
+func (s *BoardService) MoveBoardCard(userID, boardID uint, req *MoveBoardCardRequest) (*models.Todo, error) {
+	board, err := s.findBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+	target := -1
+	for i, column := range board.Columns {
+		if column.ID == req.ColumnID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("%w: 看板中不存在该列", ErrInvalidTodoParams)
+	}
+	todo, err := s.todoService.GetTodoByID(userID, req.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.HasChildren {
+		return nil, fmt.Errorf("%w: 重复待办的抽象待办不能放到看板上，请移动具体实例", ErrInvalidTodoParams)
+	}
+
+	// 目标列由状态或标签决定，移入即修改对应的状态或标签
+	patch := &PatchTodoRequest{}
+	column := board.Columns[target]
+	switch board.GroupBy {
+	case models.BoardGroupByStatus:
+		if todo.Status != column.Status {
+			patch.Status = &column.Status
+		}
+	case models.BoardGroupByTag:
+		tags, err := s.boardTagsAfterMove(todo, board, column)
+		if err != nil {
+			return nil, err
+		}
+		patch.Tags = tags
+	}
+
+	return s.todoService.patchTodo(userID, todo, patch, func(tx *gorm.DB) error {
+		if err := s.boardRepo.LockWithTx(tx, userID, board.ID); err != nil {
+			return fmt.Errorf("锁定看板失败: %v", err)
+		}
+		return s.placeBoardCardWithTx(tx, board, target, todo.ID, req.AfterID, req.BeforeID)
+	})
+}
+
+// boardLayout 看板各列的待办（已排序）及列使用的标签名称
+type boardLayout struct {
+	columns   [][]models.Todo
+	positions map[uint]string
+	tagNames  map[uint]string
+}
+
+// loadBoardLayout 查询看板涉及的待办并按排序键分配到各列，每个待办只出现在第一个匹配的列中
+func (s *BoardService) loadBoardLayout(board *models.Board, positions map[uint]string) (*boardLayout, error) {
+	layout := &boardLayout{
+		columns:   make([][]models.Todo, len(board.Columns)),
+		positions: positions,
+		tagNames:  make(map[uint]string),
+	}
+
+	query := s.todoRepo.Query(board.UserID).InstancesOnly()
+	switch board.GroupBy {
+	case models.BoardGroupByTag:
+		tags, err := s.tagRepo.FindByUser(board.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("查询标签失败: %v", err)
+		}
+		var names []string
+		for _, tag := range tags {
+			layout.tagNames[tag.ID] = tag.Name
+		}
+		for _, column := range board.Columns {
+			if name, ok := layout.tagNames[column.TagID]; ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return layout, nil
+		}
+		query = query.Statuses(TodoStatusPending, TodoStatusInProgress).AnyTags(names...)
+	default:
+		statuses := make([]string, 0, len(board.Columns))
+		for _, column := range board.Columns {
+			statuses = append(statuses, column.Status)
+		}
+		query = query.Statuses(statuses...)
+	}
+
+	todos, err := query.Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询看板待办失败: %v", err)
+	}
+	if board.GroupBy == models.BoardGroupByTag {
+		if err := s.todoService.attachTags(todos); err != nil {
+			return nil, err
+		}
+	}
+	for _, todo := range todos {
+		if i := boardColumnIndex(board, todo); i >= 0 {
+			layout.columns[i] = append(layout.columns[i], todo)
+		}
+	}
+	for _, todos := range layout.columns {
+		sortBoardTodos(todos, positions)
+	}
+	return layout, nil
+}
+
+// placeBoardCardWithTx 计算待办在目标列中的排序键并写入；目标列中有尚未排序的待办或排序键过长时，整列重新分配排序键
+func (s *BoardService) placeBoardCardWithTx(tx *gorm.DB, board *models.Board, target int, todoID, afterID, beforeID uint) error {
+	positions, err := s.boardRepo.FindCardPositionsWithTx(tx, board.ID)
+	if err != nil {
+		return fmt.Errorf("查询看板排序失败: %v", err)
+	}
+	layout, err := s.loadBoardLayout(board, positions)
+	if err != nil {
+		return err
+	}
+
+	// 目标列中除被移动待办以外的顺序
+	ordered := make([]uint, 0, len(layout.columns[target]))
+	for _, todo := range layout.columns[target] {
+		if todo.ID != todoID {
+			ordered = append(ordered, todo.ID)
+		}
+	}
+	index := len(ordered)
+	anchor, offset := afterID, 1
+	if anchor == 0 && beforeID != 0 {
+		anchor, offset = beforeID, 0
+	}
+	if anchor != 0 {
+		found := false
+		for i, id := range ordered {
+			if id == anchor {
+				index, found = i+offset, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: 参照的待办不在目标列中", ErrInvalidTodoParams)
+		}
+	}
+	ordered = append(ordered[:index], append([]uint{todoID}, ordered[index:]...)...)
+
+	// 只改写被移动待办的排序键
+	position, ok := "", true
+	for _, id := range ordered {
+		if id != todoID && layout.positions[id] == "" {
+			ok = false
+		}
+	}
+	if ok {
+		var before, after string
+		if index > 0 {
+			before = layout.positions[ordered[index-1]]
+		}
+		if index+1 < len(ordered) {
+			after = layout.positions[ordered[index+1]]
+		}
+		position, err = positionBetween(before, after)
+		ok = err == nil && len(position) <= maxPositionLength
+	}
+	if ok {
+		return s.boardRepo.SaveCardsWithTx(tx, []models.BoardCard{{BoardID: board.ID, TodoID: todoID, Position: position}})
+	}
+
+	// 整列重新分配
+	keys := evenPositions(len(ordered))
+	cards := make([]models.BoardCard, len(ordered))
+	for i, id := range ordered {
+		cards[i] = models.BoardCard{BoardID: board.ID, TodoID: id, Position: keys[i]}
+	}
+	return s.boardRepo.SaveCardsWithTx(tx, cards)
+}
+
+// boardTagsAfterMove 计算移入标签列后待办的标签：去掉看板上其他列的标签，加上目标列的标签
+func (s *BoardService) boardTagsAfterMove(todo *models.Todo, board *models.Board, target models.BoardColumn) (*[]string, error) {
+	tagsByTodo, err := s.tagRepo.FindTagsByTodoIDs([]uint{todo.ID})
+	if err != nil {
+		return nil, fmt.Errorf("查询待办标签失败: %v", err)
+	}
+	targetTag, err := s.tagRepo.FindByID(todo.CreatorUserID, target.TagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 目标列的标签已被删除", ErrInvalidTodoParams)
+	}
+
+	boardTags := make(map[uint]bool, len(board.Columns))
+	for _, column := range board.Columns {
+		boardTags[column.TagID] = true
+	}
+	names := []string{targetTag.Name}
+	for _, tag := range tagsByTodo[todo.ID] {
+		if !boardTags[tag.ID] {
+			names = append(names, tag.Name)
+		}
+	}
+	return &names, nil
+}
+
+// buildBoardColumns 校验看板列配置：status看板每列对应一个不重复的状态，tag看板每列对应一个不重复的标签
+func (s *BoardService) buildBoardColumns(userID uint, groupBy string, reqs []BoardColumnRequest) ([]models.BoardColumn, error) {
+	if len(reqs) == 0 || len(reqs) > maxBoardColumns {
+		return nil, fmt.Errorf("%w: 看板需要1到%d列", ErrInvalidTodoParams, maxBoardColumns)
+	}
+
+	columns := make([]models.BoardColumn, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		column := models.BoardColumn{
+			Name:     strings.TrimSpace(req.Name),
+			Position: i,
+			WIPLimit: req.WIPLimit,
+		}
+		if column.Name == "" {
+			return nil, fmt.Errorf("%w: 列名称不能为空", ErrInvalidTodoParams)
+		}
+
+		var key string
+		switch groupBy {
+		case models.BoardGroupByTag:
+			tagIDs, err := s.todoService.resolveTagIDs(userID, []string{req.Tag})
+			if err != nil {
+				return nil, err
+			}
+			if len(tagIDs) == 0 {
+				return nil, fmt.Errorf("%w: 标签看板的每一列都需要指定标签", ErrInvalidTodoParams)
+			}
+			column.TagID = tagIDs[0]
+			key = fmt.Sprintf("tag:%d", column.TagID)
+		default:
+			if req.Status == "" {
+				return nil, fmt.Errorf("%w: 状态看板的每一列都需要指定状态", ErrInvalidTodoParams)
+			}
+			column.Status = req.Status
+			key = "status:" + req.Status
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: 看板列的状态或标签不能重复", ErrInvalidTodoParams)
+		}
+		seen[key] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// findBoard 查询用户的看板，不存在时返回ErrBoardNotFound
+func (s *BoardService) findBoard(userID, boardID uint) (*models.Board, error) {
+	board, err := s.boardRepo.FindByID(userID, boardID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBoardNotFound
+		}
+		return nil, fmt.Errorf("查询看板失败: %v", err)
+	}
+	return board, nil
+}
+
+// boardColumnIndex 返回待办所在的列，不属于任何列时返回-1
+func boardColumnIndex(board *models.Board, todo models.Todo) int {
+	for i, column := range board.Columns {
+		switch board.GroupBy {
+		case models.BoardGroupByTag:
+			for _, tag := range todo.Tags {
+				if tag.ID == column.TagID {
+					return i
+				}
+			}
+		default:
+			if todo.Status == column.Status {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sortBoardTodos 有排序键的待办按键排列在前，其余按开始时间排在后面
+func sortBoardTodos(todos []models.Todo, positions map[uint]string) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := positions[todos[i].ID], positions[todos[j].ID]
+		if a != b {
+			if a == "" || b == "" {
+				return b == ""
+			}
+			return a < b
+		}
+		if !todos[i].StartTime.Equal(todos[j].StartTime) {
+			return todos[i].StartTime.Before(todos[j].StartTime)
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrCalendarFeedNotFound 订阅链接不存在或已失效
+var ErrCalendarFeedNotFound = errors.New("日历订阅不存在或已失效")
+
+// 日历导出参数
+const (
+	calendarFeedTokenBytes   = 32
+	calendarActivityLookback = 30 * 24 * time.Hour // 导出最近30天内结束的活动
+	calendarUIDDomain        = "team-task-hub"
+)
+
+// 待办在日历中的呈现方式
+const (
+	CalendarTodoAsEvent = "vevent" // 作为日程显示，兼容Google日历等不支持VTODO的客户端
+	CalendarTodoAsTodo  = "vtodo"  // 作为任务显示
+)
+
+type CalendarService struct {
+	feedRepo     *repositories.CalendarFeedRepository
+	todoRepo     *repositories.TodoRepository
+	activityRepo *repositories.ActivityRepository
+}
+
+func NewCalendarService(
+	feedRepo *repositories.CalendarFeedRepository,
+	todoRepo *repositories.TodoRepository,
+	activityRepo *repositories.ActivityRepository,
+) *CalendarService {
+	return &CalendarService{
+		feedRepo:     feedRepo,
+		todoRepo:     todoRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// GetFeed 获取用户的订阅，不存在时创建
+func (s *CalendarService) GetFeed(userID uint) (*models.CalendarFeed, error) {
+	feed, err := s.feedRepo.FindByUser(userID)
+	if err == nil {
+		return feed, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询日历订阅失败: %v", err)
+	}
+
+	token, err := randomHexToken(calendarFeedTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("生成订阅token失败: %v", err)
+	}
+	feed = &models.CalendarFeed{UserID: userID, Token: token}
+	if err := s.feedRepo.Create(feed); err != nil {
+		return nil, fmt.Errorf("创建日历订阅失败: %v", err)
+	}
+	return feed, nil
+}
+
+// ResetFeed 重新生成订阅token，旧的订阅链接立即失效
+func (s *CalendarService) ResetFeed(userID uint) (*models.CalendarFeed, error) {
+	feed, err := s.GetFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomHexToken(calendarFeedTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("生成订阅token失败: %v", err)
+	}
+	if err := s.feedRepo.UpdateToken(feed.ID, token); err != nil {
+		return nil, fmt.Errorf("重置日历订阅失败: %v", err)
+	}
+	feed.Token = token
+	return feed, nil
+}
+
+// RevokeFeed 关闭日历订阅
+func (s *CalendarService) RevokeFeed(userID uint) error {
+	deleted, err := s.feedRepo.DeleteByUser(userID)
+	if err != nil {
+		return fmt.Errorf("关闭日历订阅失败: %v", err)
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// RenderFeed 根据订阅token生成日历内容，无需登录
+func (s *CalendarService) RenderFeed(token, todoFormat string) (string, error) {
+	feed, err := s.feedRepo.FindByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrCalendarFeedNotFound
+		}
+		return "", fmt.Errorf("查询日历订阅失败: %v", err)
+	}
+	if err := s.feedRepo.TouchAccess(feed.ID, time.Now()); err != nil {
+		log.Printf("记录日历订阅 %d 访问时间失败: %v", feed.ID, err)
+	}
+	return s.RenderUserCalendar(feed.UserID, todoFormat)
+}
+
+// RenderUserCalendar 将用户未完成的待办和参与的活动导出为iCalendar
+// 重复待办以抽象待办加RRULE导出，已取消的实例作为EXDATE排除，单独修改过时间的实例以RECURRENCE-ID覆盖
+func (s *CalendarService) RenderUserCalendar(userID uint, todoFormat string) (string, error) {
+	if todoFormat == "" {
+		todoFormat = CalendarTodoAsEvent
+	}
+	if todoFormat != CalendarTodoAsEvent && todoFormat != CalendarTodoAsTodo {
+		return "", fmt.Errorf("%w: format 只能为 vevent 或 vtodo", ErrInvalidTodoParams)
+	}
+
+	todos, err := s.todoRepo.Query(userID).
+		Statuses(TodoStatusPending, TodoStatusInProgress).
+		Parent(0).
+		Find()
+	if err != nil {
+		return "", fmt.Errorf("查询待办失败: %v", err)
+	}
+	cancelledDates, overrides, err := s.seriesExceptions(userID, todos)
+	if err != nil {
+		return "", err
+	}
+	activities, err := s.activityRepo.FindParticipatedActivitiesEndingAfter(userID, time.Now().Add(-calendarActivityLookback))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	w := &icalWriter{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + icalProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.text("X-WR-CALNAME", "Team Task Hub")
+	w.line("X-PUBLISHED-TTL:PT1H")
+	w.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+
+	// 重复待办带TZID输出，为用到的每个时区输出VTIMEZONE
+	zones := make(map[string]*time.Location)
+	for i := range todos {
+		if todos[i].HasChildren {
+			loc := todos[i].Location()
+			zones[icalTZID(loc)] = loc
+		}
+	}
+	delete(zones, "")
+	tzids := make([]string, 0, len(zones))
+	for tzid := range zones {
+		tzids = append(tzids, tzid)
+	}
+	sort.Strings(tzids)
+	for _, tzid := range tzids {
+		w.timezone(zones[tzid], now.Year())
+	}
+
+	for i := range todos {
+		writeTodoComponent(w, &todos[i], todoFormat, cancelledDates[todos[i].ID], overrides[todos[i].ID], now)
+	}
+	for i := range activities {
+		writeActivityComponent(w, &activities[i], now)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.String(), nil
+}
+
+// seriesExceptions 查询重复待办中的例外实例，按抽象待办ID分组：
+// 已取消实例的原定开始时间，以及偏离过原定时间（单独修改或推迟）的未完成实例
+func (s *CalendarService) seriesExceptions(userID uint, todos []models.Todo) (map[uint][]time.Time, map[uint][]models.Todo, error) {
+	var rootIDs []uint
+	for _, todo := range todos {
+		if todo.HasChildren {
+			rootIDs = append(rootIDs, todo.ID)
+		}
+	}
+	dates := make(map[uint][]time.Time)
+	overrides := make(map[uint][]models.Todo)
+	if len(rootIDs) == 0 {
+		return dates, overrides, nil
+	}
+
+	children, err := s.todoRepo.Query(userID).
+		Statuses(TodoStatusCancelled, TodoStatusPending, TodoStatusInProgress).
+		ParentIn(rootIDs).
+		Find()
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询重复待办的实例失败: %v", err)
+	}
+	for _, child := range children {
+		scheduled, _ := child.ScheduledTimes()
+		switch {
+		case child.Status == TodoStatusCancelled:
+			dates[child.ParentID] = append(dates[child.ParentID], scheduled)
+		case child.Rescheduled():
+			overrides[child.ParentID] = append(overrides[child.ParentID], child)
+		}
+	}
+	return dates, overrides, nil
+}
+
+// writeTodoComponent 输出待办对应的VEVENT或VTODO，重复待办随后输出各偏离原定时间的实例
+func writeTodoComponent(w *icalWriter, todo *models.Todo, todoFormat string, cancelled []time.Time, overrides []models.Todo, now time.Time) {
+	component := "VEVENT"
+	if todoFormat == CalendarTodoAsTodo {
+		component = "VTODO"
+	}
+
+	var rule *models.RecurrenceRule
+	if todo.HasChildren {
+		rule = seriesRule(todo)
+	}
+	// 重复规则按待办所属时区展开，带时区输出才能保证BYDAY等在订阅端展开一致
+	loc := todo.Location()
+	timeProp := w.utcTime
+	if rule != nil {
+		timeProp = w.zonedTime
+	}
+
+	uidLine := fmt.Sprintf("UID:todo-%d@%s", todo.ID, calendarUIDDomain)
+	w.line("BEGIN:" + component)
+	w.line(uidLine)
+	w.utcTime("DTSTAMP", now)
+	writeTodoProperties(w, todo, component, timeProp, loc)
+	if rule != nil {
+		w.line("RRULE:" + rule.String())
+		for _, date := range seriesExDates(todo, cancelled) {
+			timeProp("EXDATE", date)
+		}
+	}
+	w.line("END:" + component)
+
+	if rule == nil {
+		return
+	}
+	// 与系列同UID，以RECURRENCE-ID（原定开始时间）指明覆盖的是哪一次
+	for i := range overrides {
+		scheduled, _ := overrides[i].ScheduledTimes()
+		w.line("BEGIN:" + component)
+		w.line(uidLine)
+		w.utcTime("DTSTAMP", now)
+		timeProp("RECURRENCE-ID", scheduled.In(loc))
+		writeTodoProperties(w, &overrides[i], component, timeProp, loc)
+		w.line("END:" + component)
+	}
+}
+
+// writeTodoProperties 输出待办组件的内容、时间和状态属性
+func writeTodoProperties(w *icalWriter, todo *models.Todo, component string, timeProp func(string, time.Time), loc *time.Location) {
+	w.text("SUMMARY", todo.Title)
+	w.text("DESCRIPTION", todo.Description)
+	timeProp("DTSTART", todo.StartTime.In(loc))
+	if component == "VTODO" {
+		timeProp("DUE", todo.EndTime.In(loc))
+		if todo.Status == TodoStatusInProgress {
+			w.line("STATUS:IN-PROCESS")
+		} else {
+			w.line("STATUS:NEEDS-ACTION")
+		}
+	} else {
+		timeProp("DTEND", todo.EndTime.In(loc))
+		w.line("STATUS:CONFIRMED")
+	}
+	w.line(fmt.Sprintf("PRIORITY:%d", icalPriority(todo.Urgency)))
+	w.text("CATEGORIES", todo.Category)
+}
+
+// writeActivityComponent 输出活动对应的VEVENT
+func writeActivityComponent(w *icalWriter, activity *models.Activity, now time.Time) {
+	description := activity.Description
+	if activity.Organization.Name != "" {
+		if description != "" {
+			description += "\n"
+		}
+		description += "组织: " + activity.Organization.Name
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line(fmt.Sprintf("UID:activity-%d@%s", activity.ID, calendarUIDDomain))
+	w.utcTime("DTSTAMP", now)
+	w.text("SUMMARY", activity.Title)
+	w.text("DESCRIPTION", description)
+	w.utcTime("DTSTART", activity.StartTime)
+	w.utcTime("DTEND", activity.EndTime)
+	w.line("STATUS:CONFIRMED")
+	w.line("END:VEVENT")
+}
+
+// seriesRule 得到抽象待办的重复规则，兼容只设置了RepeatType的旧数据
+func seriesRule(todo *models.Todo) *models.RecurrenceRule {
+	if todo.RRule != "" {
+		if rule, err := models.ParseRRule(todo.RRule, todo.Location()); err == nil {
+			return rule
+		}
+	}
+	var until time.Time
+	if todo.RepeatEndDate.Year() < 2100 {
+		until = todo.RepeatEndDate
+	}
+	rule, err := models.NewSimpleRule(todo.RepeatType, todo.RepeatInterval, until)
+	if err != nil {
+		return nil
+	}
+	return rule
+}
+
+// seriesExDates 合并抽象待办上排除的日期和已取消实例的时间
+func seriesExDates(todo *models.Todo, cancelled []time.Time) []time.Time {
+	start := todo.LocalStartTime()
+	dates := make([]time.Time, 0, len(cancelled))
+	for _, date := range cancelled {
+		dates = append(dates, date.In(start.Location()))
+	}
+	for date := range todo.ExcludedDates() {
+		day, err := time.ParseInLocation("2006-01-02", date, start.Location())
+		if err != nil {
+			continue
+		}
+		dates = append(dates, time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), start.Second(), 0, start.Location()))
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// icalPriority 将紧急程度映射为iCalendar优先级（1最高，9最低）
+func icalPriority(urgency string) int {
+	switch urgency {
+	case "high":
+		return 1
+	case "low":
+		return 9
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// 分类和标签相关的通用错误
+var (
+	ErrCategoryNotFound  = errors.New("分类不存在")
+	ErrTagNotFound       = errors.New("标签不存在")
+	ErrLabelNameConflict = errors.New("名称已存在")
+	ErrDefaultCategory   = errors.New("预置分类不能删除或改名")
+)
+
+type CategoryService struct {
+	categoryRepo *repositories.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo *repositories.CategoryRepository) *CategoryService {
+	return &CategoryService{categoryRepo: categoryRepo}
+}
+
+// CategoryRequest 创建/更新分类请求
+type CategoryRequest struct {
+	Name  string `json:"name" binding:"required,max=50" example:"健身"`
+	Color string `json:"color" binding:"omitempty,max=20" example:"#4caf50"`
+}
+
+// ListCategories 获取用户的全部分类，首次访问时创建预置分类
+func (s *CategoryService) ListCategories(userID uint) ([]models.Category, error) {
+	if err := s.categoryRepo.EnsureDefaults(userID); err != nil {
+		return nil, fmt.Errorf("初始化默认分类失败: %v", err)
+	}
+	categories, err := s.categoryRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+	return categories, nil
+}
+
+// CreateCategory 创建分类
+func (s *CategoryService) CreateCategory(userID uint, req *CategoryRequest) (*models.Category, error) {
+	name, err := normalizeLabelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.categoryRepo.FindByName(userID, name); err == nil {
+		return nil, ErrLabelNameConflict
+	}
+
+	category := &models.Category{
+		UserID: userID,
+		Name:   name,
+		Color:  strings.TrimSpace(req.Color),
+	}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, fmt.Errorf("创建分类失败: %v", err)
+	}
+	return category, nil
+}
+
+// UpdateCategory 修改分类名称和颜色，改名时同步更新待办上的分类名称
+func (s *CategoryService) UpdateCategory(userID, categoryID uint, req *CategoryRequest) (*models.Category, error) {
+	category, err := s.findCategory(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := normalizeLabelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{"color": strings.TrimSpace(req.Color)}
+	if name != category.Name {
+		if category.IsDefault {
+			return nil, ErrDefaultCategory
+		}
+		if _, err := s.categoryRepo.FindByName(userID, name); err == nil {
+			return nil, ErrLabelNameConflict
+		}
+		updates["name"] = name
+	}
+
+	if err := s.categoryRepo.Update(category, updates); err != nil {
+		return nil, fmt.Errorf("更新分类失败: %v", err)
+	}
+	return s.findCategory(userID, categoryID)
+}
+
+// DeleteCategory 删除分类，该分类下的待办转移到默认分类，返回被转移的待办数量
+func (s *CategoryService) DeleteCategory(userID, categoryID uint) (int64, error) {
+	category, err := s.findCategory(userID, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	if category.IsDefault {
+		return 0, ErrDefaultCategory
+	}
+
+	if err := s.categoryRepo.EnsureDefaults(userID); err != nil {
+		return 0, fmt.Errorf("初始化默认分类失败: %v", err)
+	}
+	target, err := s.categoryRepo.FindByName(userID, models.DefaultCategoryName)
+	if err != nil {
+		return 0, fmt.Errorf("查询默认分类失败: %v", err)
+	}
+
+	reassigned, err := s.categoryRepo.DeleteAndReassign(category, target)
+	if err != nil {
+		return 0, fmt.Errorf("删除分类失败: %v", err)
+	}
+	return reassigned, nil
+}
+
+// findCategory 查找属于用户的分类
+func (s *CategoryService) findCategory(userID, categoryID uint) (*models.Category, error) {
+	category, err := s.categoryRepo.FindByID(userID, categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+	return category, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"team_task_hub/backend/internal/repositories"
+)
+
+// ErrScheduleConflict 时间冲突且要求阻止操作
+var ErrScheduleConflict = errors.New("与已有日程时间冲突")
+
+// 时间冲突的处理策略
+const (
+	ConflictPolicyWarn  = "warn"  // 返回冲突但继续操作（默认）
+	ConflictPolicyBlock = "block" // 存在冲突时阻止操作
+)
+
+// 冲突条目类型
+const (
+	ConflictTypeTodo     = "todo"
+	ConflictTypeActivity = "activity"
+)
+
+// ScheduleConflict 与目标时间段重叠的待办或活动
+type ScheduleConflict struct {
+	Type             string    `json:"type" example:"activity"` // todo 或 activity
+	ID               uint      `json:"id"`
+	Title            string    `json:"title"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	OrganizationName string    `json:"organization_name,omitempty"` // 活动所属组织
+}
+
+// UserScheduleConflicts 某个用户的时间冲突，用于批量分配活动
+type UserScheduleConflicts struct {
+	UserID    uint               `json:"user_id"`
+	Conflicts []ScheduleConflict `json:"conflicts"`
+}
+
+// ConflictService 检查用户待办与参与的活动之间的时间冲突
+type ConflictService struct {
+	todoRepo     *repositories.TodoRepository
+	activityRepo *repositories.ActivityRepository
+}
+
+// NewConflictService 创建时间冲突检查服务实例
+func NewConflictService(todoRepo *repositories.TodoRepository, activityRepo *repositories.ActivityRepository) *ConflictService {
+	return &ConflictService{
+		todoRepo:     todoRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// FindConflicts 查找用户在[start, end)内未完成的待办实例和尚未完成的活动，excludeActivityID用于排除活动本身
+func (s *ConflictService) FindConflicts(userID uint, start, end time.Time, excludeActivityID uint) ([]ScheduleConflict, error) {
+	conflicts := []ScheduleConflict{}
+	if !end.After(start) {
+		return conflicts, nil
+	}
+
+	todos, err := s.todoRepo.Query(userID).
+		InstancesOnly().
+		Statuses(TodoStatusPending, TodoStatusInProgress).
+		Overlapping(start, end).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询冲突待办失败: %v", err)
+	}
+	for _, todo := range todos {
+		conflicts = append(conflicts, ScheduleConflict{
+			Type:      ConflictTypeTodo,
+			ID:        todo.ID,
+			Title:     todo.Title,
+			StartTime: todo.StartTime,
+			EndTime:   todo.EndTime,
+		})
+	}
+
+	activities, err := s.activityRepo.FindUserConflictingActivities(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities {
+		if activity.ID == excludeActivityID {
+			continue
+		}
+		conflicts = append(conflicts, ScheduleConflict{
+			Type:             ConflictTypeActivity,
+			ID:               activity.ID,
+			Title:            activity.Title,
+			StartTime:        activity.StartTime,
+			EndTime:          activity.EndTime,
+			OrganizationName: activity.Organization.Name,
+		})
+	}
+
+	return conflicts, nil
+}
+
+// FindUsersConflicts 批量查找多个用户在[start, end)内的冲突，只返回存在冲突的用户
+func (s *ConflictService) FindUsersConflicts(userIDs []uint, start, end time.Time, excludeActivityID uint) ([]UserScheduleConflicts, error) {
+	result := []UserScheduleConflicts{}
+	for _, userID := range userIDs {
+		conflicts, err := s.FindConflicts(userID, start, end, excludeActivityID)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			result = append(result, UserScheduleConflicts{UserID: userID, Conflicts: conflicts})
+		}
+	}
+	return result, nil
+}
+
+// blocksOnConflict 判断冲突策略是否要求阻止操作
+func blocksOnConflict(policy string) bool {
+	return policy == ConflictPolicyBlock
+}
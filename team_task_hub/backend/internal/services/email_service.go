@@ -283,6 +283,29 @@ func (s *EmailService) sendEmail(to, code, businessType string) error {
 	return nil
 }
 
+// SendNotificationEmail 发送通知类邮件（提醒等），body为纯文本内容
+func (s *EmailService) SendNotificationEmail(to, subject, body string) error {
+	if s.config.Host == "" || s.config.User == "" || s.config.Password == "" {
+		return fmt.Errorf("邮箱配置不完整，请检查SMTP配置")
+	}
+
+	m := gomail.NewMessage()
+	if s.config.From != "" {
+		m.SetHeader("From", s.config.From+"<"+s.config.User+">")
+	} else {
+		m.SetHeader("From", s.config.User)
+	}
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/plain", body)
+
+	d := gomail.NewDialer(s.config.Host, s.config.Port, s.config.User, s.config.Password)
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("SMTP发送失败: %v", err)
+	}
+	return nil
+}
+
 // TestConnection 测试SMTP连接（用于诊断配置问题）
 func (s *EmailService) TestConnection() error {
 	if s.config.Host == "" || s.config.User == "" || s.config.Password == "" {
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalendar 格式常量（RFC 5545）
+const (
+	icalProdID       = "-//Team Task Hub//Calendar Export//ZH"
+	icalUTCLayout    = "20060102T150405Z"
+	icalLocalLayout  = "20060102T150405"
+	icalMaxLineBytes = 75
+)
+
+// icalWriter 按RFC 5545输出iCalendar内容：CRLF换行、长行折叠、文本转义
+type icalWriter struct {
+	b strings.Builder
+}
+
+// line 写入一行内容，超过75字节时按规范折叠，不拆分多字节字符
+func (w *icalWriter) line(content string) {
+	limit := icalMaxLineBytes
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.b.WriteString(content[:cut])
+		w.b.WriteString("\r\n ")
+		content = content[cut:]
+		// 续行以空格开头，占用一个字节
+		limit = icalMaxLineBytes - 1
+	}
+	w.b.WriteString(content)
+	w.b.WriteString("\r\n")
+}
+
+// text 写入文本属性，value为未转义的原始值，为空时跳过
+func (w *icalWriter) text(name, value string) {
+	if value == "" {
+		return
+	}
+	w.line(name + ":" + icalEscape(value))
+}
+
+// utcTime 写入UTC时间属性
+func (w *icalWriter) utcTime(name string, t time.Time) {
+	w.line(name + ":" + t.UTC().Format(icalUTCLayout))
+}
+
+// zonedTime 写入带时区的时间属性，时区不是IANA名称时退回UTC
+func (w *icalWriter) zonedTime(name string, t time.Time) {
+	if tzid := icalTZID(t.Location()); tzid != "" {
+		w.line(name + ";TZID=" + tzid + ":" + t.Format(icalLocalLayout))
+		return
+	}
+	w.utcTime(name, t)
+}
+
+// timezone 输出TZID对应的VTIMEZONE组件，带TZID的时间必须有对应的定义（RFC 5545 第3.6.5节）
+// 按year年的夏令时切换规律生成每年重复的STANDARD/DAYLIGHT规则，没有夏令时的时区只输出固定偏移
+func (w *icalWriter) timezone(loc *time.Location, year int) {
+	tzid := icalTZID(loc)
+	if tzid == "" {
+		return
+	}
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + tzid)
+
+	transitions := icalZoneTransitions(loc, year)
+	if len(transitions) != 2 {
+		// 没有夏令时，或当年调整过时区规则无法归纳为每年重复的规律，使用年底的偏移
+		name, offset := time.Date(year, 12, 31, 0, 0, 0, 0, loc).Zone()
+		w.line("BEGIN:STANDARD")
+		w.line("DTSTART:19700101T000000")
+		w.line("TZOFFSETFROM:" + icalOffset(offset))
+		w.line("TZOFFSETTO:" + icalOffset(offset))
+		w.text("TZNAME", name)
+		w.line("END:STANDARD")
+		transitions = nil
+	}
+	for _, transition := range transitions {
+		_, from := transition.Add(-time.Second).Zone()
+		name, to := transition.Zone()
+		kind := "STANDARD"
+		if transition.IsDST() {
+			kind = "DAYLIGHT"
+		}
+
+		// 切换时刻以切换前的本地时间表示，规则为“每年某月第N个（或最后一个）星期几”
+		onset := transition.Add(time.Duration(from) * time.Second).UTC()
+		daysInMonth := time.Date(onset.Year(), onset.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		n := (onset.Day()-1)/7 + 1
+		if onset.Day()+7 > daysInMonth {
+			n = -1
+		}
+		first := icalNthWeekday(1970, onset.Month(), n, onset.Weekday())
+		first = first.Add(time.Duration(onset.Hour())*time.Hour + time.Duration(onset.Minute())*time.Minute)
+
+		w.line("BEGIN:" + kind)
+		w.line("DTSTART:" + first.Format(icalLocalLayout))
+		w.line(fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(onset.Month()), n, strings.ToUpper(onset.Weekday().String()[:2])))
+		w.line("TZOFFSETFROM:" + icalOffset(from))
+		w.line("TZOFFSETTO:" + icalOffset(to))
+		w.text("TZNAME", name)
+		w.line("END:" + kind)
+	}
+	w.line("END:VTIMEZONE")
+}
+
+// String 返回生成的内容
+func (w *icalWriter) String() string {
+	return w.b.String()
+}
+
+// icalEscape 转义TEXT类型的值
+func icalEscape(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	)
+	return replacer.Replace(value)
+}
+
+// icalTZID 返回可用于TZID参数的时区名称，UTC和无法识别的本地时区返回空
+func icalTZID(loc *time.Location) string {
+	name := loc.String()
+	if name == "" || name == "UTC" || name == "Local" {
+		return ""
+	}
+	return name
+}
+
+// icalZoneTransitions 返回时区在year年内改变UTC偏移的时刻
+func icalZoneTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, loc)
+	t := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	for {
+		_, zoneEnd := t.ZoneBounds()
+		if zoneEnd.IsZero() || !zoneEnd.Before(end) {
+			return transitions
+		}
+		_, before := zoneEnd.Add(-time.Second).Zone()
+		if _, after := zoneEnd.Zone(); after != before {
+			transitions = append(transitions, zoneEnd)
+		}
+		t = zoneEnd
+	}
+}
+
+// icalNthWeekday 返回某月第n个星期几，n为-1时返回最后一个
+func icalNthWeekday(year int, month time.Month, n int, weekday time.Weekday) time.Time {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// icalOffset 格式化UTC偏移，如 +0800、-0430
+func icalOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// icalProperty 解析后的属性，Value为未转义前的原始值
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icalComponent 解析后的VEVENT/VTODO组件
+type icalComponent struct {
+	Name  string
+	Props []icalProperty
+}
+
+// get 返回第一个同名属性
+func (c *icalComponent) get(name string) *icalProperty {
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+// getAll 返回全部同名属性
+func (c *icalComponent) getAll(name string) []icalProperty {
+	var props []icalProperty
+	for _, prop := range c.Props {
+		if prop.Name == name {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// parseICalComponents 解析iCalendar内容中的VEVENT和VTODO，忽略其中嵌套的VALARM等子组件
+func parseICalComponents(data string) ([]icalComponent, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+	data = strings.TrimPrefix(data, "\ufeff")
+
+	var components []icalComponent
+	var current *icalComponent
+	depth := 0
+	sawCalendar := false
+
+	for _, raw := range strings.Split(data, "\n") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		prop, err := parseICalLine(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			value := strings.ToUpper(prop.Value)
+			if value == "VCALENDAR" {
+				sawCalendar = true
+			}
+			if current != nil {
+				depth++
+			} else if value == "VEVENT" || value == "VTODO" {
+				current = &icalComponent{Name: value}
+			}
+		case "END":
+			if current == nil {
+				continue
+			}
+			if depth > 0 {
+				depth--
+			} else if strings.ToUpper(prop.Value) == current.Name {
+				components = append(components, *current)
+				current = nil
+			}
+		default:
+			if current != nil && depth == 0 {
+				current.Props = append(current.Props, prop)
+			}
+		}
+	}
+
+	if !sawCalendar {
+		return nil, fmt.Errorf("不是有效的iCalendar文件")
+	}
+	return components, nil
+}
+
+// parseICalLine 解析一行属性：NAME;PARAM=VALUE:内容
+func parseICalLine(line string) (icalProperty, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, fmt.Errorf("无效的iCalendar行: %s", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{
+		Name:   strings.ToUpper(strings.TrimSpace(parts[0])),
+		Params: make(map[string]string),
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// icalUnescape 还原TEXT类型的转义
+func icalUnescape(value string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(value)
+}
+
+// icalSplitList 按未转义的逗号拆分多值属性并还原转义
+func icalSplitList(value string) []string {
+	var items []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			items = append(items, icalUnescape(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	items = append(items, icalUnescape(b.String()))
+	return items
+}
+
+// parseICalTime 解析DATE或DATE-TIME值，dateOnly表示全天
+// 日期和浮动时间按loc时区解析，TZID无法识别（如Windows时区名）时同样使用loc
+func parseICalTime(value string, params map[string]string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(icalUTCLayout, value)
+		return t.In(loc), false, err
+	}
+
+	if tzid := params["TZID"]; tzid != "" {
+		if l, loadErr := time.LoadLocation(tzid); loadErr == nil {
+			loc = l
+		}
+	}
+	t, err = time.ParseInLocation(icalLocalLayout, value, loc)
+	return t, false, err
+}
+
+// icalDurationPattern RFC 5545 DURATION，如 PT1H30M、P1D、P2W
+var icalDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICalDuration 解析DURATION值
+func parseICalDuration(value string) (time.Duration, error) {
+	match := icalDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("无效的DURATION '%s'", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(match[i+2])
+		d += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+)
+
+// 通知列表默认和最大返回条数
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo *repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// MarkNotificationsReadRequest 标记已读请求，ids为空时标记全部
+type MarkNotificationsReadRequest struct {
+	IDs []uint `json:"ids" example:"1,2"`
+}
+
+// Notify 发送站内通知
+func (s *NotificationService) Notify(notifications ...models.Notification) error {
+	if err := s.notificationRepo.BatchCreate(notifications); err != nil {
+		return fmt.Errorf("创建通知失败: %v", err)
+	}
+	return nil
+}
+
+// ListNotifications 获取用户的通知列表以及未读数量
+func (s *NotificationService) ListNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, int64, error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+
+	notifications, err := s.notificationRepo.FindByUser(userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询通知失败: %v", err)
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("统计未读通知失败: %v", err)
+	}
+	return notifications, unread, nil
+}
+
+// MarkRead 将通知标记为已读，返回实际标记的数量
+func (s *NotificationService) MarkRead(userID uint, ids []uint) (int64, error) {
+	count, err := s.notificationRepo.MarkRead(userID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("标记通知已读失败: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,468 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// 组织待办相关的错误，处理器据此映射HTTP状态码
+var (
+	ErrOrgTodoNotFound      = errors.New("组织待办不存在")
+	ErrAssignmentNotFound   = errors.New("待办分配记录不存在")
+	ErrAssignmentHandled    = errors.New("该待办分配已处理")
+	ErrAssigneeNotOrgMember = errors.New("被分配的用户不是组织成员")
+)
+
+// orgTodoCategory 成员接受组织待办后，个人待办归入的分类
+const orgTodoCategory = "work"
+
+// OrgTodoService 组织发布待办、分配给成员以及成员接受或拒绝
+//
+// 组织待办由组织创建且不属于任何个人（CreatorUserID为0），成员接受分配后在其名下
+// 创建一份个人待办（CreatorOrganID指向来源组织），之后按普通待办流转
+type OrgTodoService struct {
+	todoRepo       *repositories.TodoRepository
+	assignmentRepo *repositories.TodoAssignmentRepository
+	orgMemberRepo  *repositories.OrganizationMemberRepository
+	categoryRepo   *repositories.CategoryRepository
+	userRepo       *repositories.UserRepository
+
+	notificationService *NotificationService
+}
+
+// NewOrgTodoService 创建组织待办服务实例
+func NewOrgTodoService(todoRepo *repositories.TodoRepository, assignmentRepo *repositories.TodoAssignmentRepository, orgMemberRepo *repositories.OrganizationMemberRepository, categoryRepo *repositories.CategoryRepository, userRepo *repositories.UserRepository, notificationService *NotificationService) *OrgTodoService {
+	return &OrgTodoService{
+		todoRepo:            todoRepo,
+		assignmentRepo:      assignmentRepo,
+		orgMemberRepo:       orgMemberRepo,
+		categoryRepo:        categoryRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// PublishOrgTodoRequest 发布组织待办请求参数
+type PublishOrgTodoRequest struct {
+	Title       string    `json:"title" binding:"required,max=200" example:"提交季度总结"`
+	Description string    `json:"description" example:"按模板填写并上传到共享目录"`
+	StartTime   time.Time `json:"start_time" binding:"required" example:"2025-01-06T09:00:00+08:00"`
+	EndTime     time.Time `json:"end_time" binding:"required" example:"2025-01-10T18:00:00+08:00"`
+	Urgency     string    `json:"urgency" binding:"omitempty,oneof=low medium high" example:"medium"`
+	AssigneeIDs []uint    `json:"assignee_ids"` // 发布时同时分配的成员，可为空
+}
+
+// AssignOrgTodoRequest 分配组织待办请求参数
+type AssignOrgTodoRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1"`
+}
+
+// RejectAssignmentRequest 拒绝组织待办请求参数
+type RejectAssignmentRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"本周出差，无法完成"`
+}
+
+// OrgTodoSummary 组织待办及其分配情况
+type OrgTodoSummary struct {
+	models.Todo
+	AssignedCount  int64 `json:"assigned_count"`
+	PendingCount   int64 `json:"pending_count"`   // 尚未回应
+	AcceptedCount  int64 `json:"accepted_count"`  // 已接受
+	RejectedCount  int64 `json:"rejected_count"`  // 已拒绝
+	CompletedCount int64 `json:"completed_count"` // 已接受且个人待办已完成
+}
+
+// PublishOrgTodo 以组织名义发布待办，可同时分配给成员
+func (s *OrgTodoService) PublishOrgTodo(organizationID, publisherID uint, req *PublishOrgTodoRequest) (*models.Todo, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return nil, fmt.Errorf("%w: 待办标题不能为空", ErrInvalidTodoParams)
+	}
+	if !req.EndTime.After(req.StartTime) {
+		return nil, fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidTodoParams)
+	}
+	if req.Urgency == "" {
+		req.Urgency = "medium"
+	}
+	assigneeIDs, err := s.validateAssignees(organizationID, req.AssigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	todo := &models.Todo{
+		Title:          req.Title,
+		Description:    req.Description,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Urgency:        req.Urgency,
+		Category:       orgTodoCategory,
+		Status:         TodoStatusPending,
+		RepeatType:     "none",
+		Timezone:       userTimezone(s.userRepo, publisherID),
+		CreatorOrganID: organizationID,
+	}
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.todoRepo.CreateWithTx(tx, todo); err != nil {
+			return fmt.Errorf("创建组织待办失败: %v", err)
+		}
+		assignments := newAssignments(todo.ID, publisherID, assigneeIDs, time.Now())
+		if err := s.assignmentRepo.BatchCreateWithTx(tx, assignments); err != nil {
+			return fmt.Errorf("分配组织待办失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyAssigned(todo, assigneeIDs)
+	return todo, nil
+}
+
+// AssignOrgTodo 将组织待办分配给更多成员，已分配过的成员跳过，返回本次新分配的成员
+func (s *OrgTodoService) AssignOrgTodo(organizationID, todoID, assignerID uint, userIDs []uint) ([]uint, error) {
+	todo, err := s.findOrgTodo(organizationID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Status == TodoStatusCompleted || todo.Status == TodoStatusCancelled {
+		return nil, fmt.Errorf("%w: 组织待办已结束，不能再分配", ErrIllegalTransition)
+	}
+	userIDs, err = s.validateAssignees(organizationID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.assignmentRepo.FindAssignedUserIDs(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询已分配成员失败: %v", err)
+	}
+	existing := make(map[uint]bool, len(assigned))
+	for _, id := range assigned {
+		existing[id] = true
+	}
+	newIDs := []uint{}
+	for _, id := range userIDs {
+		if !existing[id] {
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		return newIDs, nil
+	}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		return s.assignmentRepo.BatchCreateWithTx(tx, newAssignments(todoID, assignerID, newIDs, time.Now()))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("分配组织待办失败: %v", err)
+	}
+
+	s.notifyAssigned(todo, newIDs)
+	return newIDs, nil
+}
+
+// ListOrgTodos 查询组织发布的待办及各自的分配情况
+func (s *OrgTodoService) ListOrgTodos(organizationID uint) ([]OrgTodoSummary, error) {
+	todos, err := s.assignmentRepo.FindOrgTodos(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("查询组织待办失败: %v", err)
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	rows, err := s.assignmentRepo.SummarizeByTodos(ids)
+	if err != nil {
+		return nil, fmt.Errorf("统计组织待办分配情况失败: %v", err)
+	}
+
+	summaries := make([]OrgTodoSummary, len(todos))
+	index := make(map[uint]int, len(todos))
+	for i, todo := range todos {
+		todo.Localize()
+		summaries[i] = OrgTodoSummary{Todo: todo}
+		index[todo.ID] = i
+	}
+	for _, row := range rows {
+		summary := &summaries[index[row.TodoID]]
+		summary.AssignedCount += row.Count
+		switch row.Status {
+		case models.AssignmentPending:
+			summary.PendingCount += row.Count
+		case models.AssignmentAccepted:
+			summary.AcceptedCount += row.Count
+			if row.TodoStatus == TodoStatusCompleted {
+				summary.CompletedCount += row.Count
+			}
+		case models.AssignmentRejected:
+			summary.RejectedCount += row.Count
+		}
+	}
+	return summaries, nil
+}
+
+// ListAssignments 查询组织待办的分配记录
+func (s *OrgTodoService) ListAssignments(organizationID, todoID uint) ([]models.TodoAssignment, error) {
+	if _, err := s.findOrgTodo(organizationID, todoID); err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignmentRepo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分配记录失败: %v", err)
+	}
+	return assignments, nil
+}
+
+// DeleteOrgTodo 删除组织待办：撤回尚未回应的分配，取消成员已接受但未完成的个人待办，已完成的保留
+func (s *OrgTodoService) DeleteOrgTodo(organizationID, todoID uint) error {
+	acceptedUserIDs, err := s.assignmentRepo.FindAcceptedUserIDs(todoID)
+	if err != nil {
+		return fmt.Errorf("查询已接受成员失败: %v", err)
+	}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.assignmentRepo.FindOrgTodoForUpdateWithTx(tx, organizationID, todoID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrgTodoNotFound
+			}
+			return fmt.Errorf("查询组织待办失败: %v", err)
+		}
+		if _, err := s.assignmentRepo.DeletePendingByTodoWithTx(tx, todoID); err != nil {
+			return fmt.Errorf("撤回待处理的分配失败: %v", err)
+		}
+		if _, err := s.assignmentRepo.CancelPersonalTodosWithTx(tx, todoID); err != nil {
+			return fmt.Errorf("取消成员的个人待办失败: %v", err)
+		}
+		if err := s.todoRepo.DeleteWithTx(tx, todoID); err != nil {
+			return fmt.Errorf("删除组织待办失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range acceptedUserIDs {
+		invalidateTodoStats(userID)
+	}
+	return nil
+}
+
+// ListMyAssignments 查询分配给用户的组织待办，status为空时返回全部
+func (s *OrgTodoService) ListMyAssignments(userID uint, status string) ([]models.TodoAssignment, error) {
+	switch status {
+	case "", models.AssignmentPending, models.AssignmentAccepted, models.AssignmentRejected:
+	default:
+		return nil, fmt.Errorf("%w: 无效的分配状态 %s", ErrInvalidTodoParams, status)
+	}
+	assignments, err := s.assignmentRepo.FindByUser(userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("查询分配给我的待办失败: %v", err)
+	}
+	for i := range assignments {
+		if assignments[i].Todo != nil {
+			assignments[i].Todo.Localize()
+			assignments[i].Todo.OrganizationName = assignments[i].OrganizationName
+		}
+	}
+	return assignments, nil
+}
+
+// AcceptAssignment 接受组织待办，在用户名下创建对应的个人待办
+func (s *OrgTodoService) AcceptAssignment(userID, assignmentID uint) (*models.Todo, error) {
+	if err := s.categoryRepo.EnsureDefaults(userID); err != nil {
+		return nil, fmt.Errorf("初始化默认分类失败: %v", err)
+	}
+	category, err := s.categoryRepo.FindByName(userID, orgTodoCategory)
+	if err != nil {
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+
+	var assignment *models.TodoAssignment
+	var personal *models.Todo
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		var err error
+		assignment, err = s.lockPendingAssignment(tx, userID, assignmentID)
+		if err != nil {
+			return err
+		}
+		orgTodo, err := s.assignmentRepo.FindOrgTodoByIDForUpdateWithTx(tx, assignment.TodoID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrgTodoNotFound
+			}
+			return fmt.Errorf("查询组织待办失败: %v", err)
+		}
+
+		personal = &models.Todo{
+			Title:          orgTodo.Title,
+			Description:    orgTodo.Description,
+			StartTime:      orgTodo.StartTime,
+			EndTime:        orgTodo.EndTime,
+			Urgency:        orgTodo.Urgency,
+			Category:       category.Name,
+			CategoryID:     category.ID,
+			Status:         TodoStatusPending,
+			RepeatType:     "none",
+			Timezone:       orgTodo.Timezone,
+			CreatorUserID:  userID,
+			CreatorOrganID: orgTodo.CreatorOrganID,
+		}
+		if err := s.todoRepo.CreateWithTx(tx, personal); err != nil {
+			return fmt.Errorf("创建个人待办失败: %v", err)
+		}
+		return s.assignmentRepo.UpdateWithTx(tx, assignment.ID, map[string]any{
+			"status":           models.AssignmentAccepted,
+			"accepted_at":      time.Now(),
+			"personal_todo_id": personal.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateTodoStats(userID)
+	s.notifyResponded(assignment, personal.Title, userID, "接受")
+	personal.Localize()
+	return personal, nil
+}
+
+// RejectAssignment 拒绝组织待办
+func (s *OrgTodoService) RejectAssignment(userID, assignmentID uint, reason string) error {
+	var assignment *models.TodoAssignment
+	err := s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		var err error
+		assignment, err = s.lockPendingAssignment(tx, userID, assignmentID)
+		if err != nil {
+			return err
+		}
+		return s.assignmentRepo.UpdateWithTx(tx, assignment.ID, map[string]any{
+			"status":        models.AssignmentRejected,
+			"accepted_at":   time.Now(),
+			"reject_reason": strings.TrimSpace(reason),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	title := ""
+	if todo, err := s.todoRepo.FindByID(assignment.TodoID); err == nil {
+		title = todo.Title
+	}
+	s.notifyResponded(assignment, title, userID, "拒绝")
+	return nil
+}
+
+// findOrgTodo 查询组织发布的待办，不存在时返回ErrOrgTodoNotFound
+func (s *OrgTodoService) findOrgTodo(organizationID, todoID uint) (*models.Todo, error) {
+	todo, err := s.assignmentRepo.FindOrgTodo(organizationID, todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrgTodoNotFound
+		}
+		return nil, fmt.Errorf("查询组织待办失败: %v", err)
+	}
+	return todo, nil
+}
+
+// lockPendingAssignment 锁定用户尚未回应的分配记录
+func (s *OrgTodoService) lockPendingAssignment(tx *gorm.DB, userID, assignmentID uint) (*models.TodoAssignment, error) {
+	assignment, err := s.assignmentRepo.FindByIDForUpdateWithTx(tx, userID, assignmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentNotFound
+		}
+		return nil, fmt.Errorf("查询分配记录失败: %v", err)
+	}
+	if assignment.Status != models.AssignmentPending {
+		return nil, ErrAssignmentHandled
+	}
+	return assignment, nil
+}
+
+// validateAssignees 去重并校验被分配的用户都是组织成员
+func (s *OrgTodoService) validateAssignees(organizationID uint, userIDs []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(userIDs))
+	result := make([]uint, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == 0 || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		isMember, err := s.orgMemberRepo.Exists(organizationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, fmt.Errorf("%w: 用户ID %d", ErrAssigneeNotOrgMember, userID)
+		}
+		result = append(result, userID)
+	}
+	return result, nil
+}
+
+// notifyAssigned 通知成员收到新的组织待办，通知失败不影响分配
+func (s *OrgTodoService) notifyAssigned(todo *models.Todo, userIDs []uint) {
+	if s.notificationService == nil || len(userIDs) == 0 {
+		return
+	}
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, models.Notification{
+			UserID:  userID,
+			Type:    models.NotificationTodoAssigned,
+			Title:   "收到新的组织待办",
+			Content: fmt.Sprintf("组织为你分配了待办「%s」，请接受或拒绝", todo.Title),
+			TodoID:  todo.ID,
+		})
+	}
+	if err := s.notificationService.Notify(notifications...); err != nil {
+		log.Printf("发送组织待办分配通知失败: %v", err)
+	}
+}
+
+// notifyResponded 通知分配人成员已接受或拒绝组织待办
+func (s *OrgTodoService) notifyResponded(assignment *models.TodoAssignment, title string, userID uint, action string) {
+	if s.notificationService == nil {
+		return
+	}
+	username := fmt.Sprintf("用户%d", userID)
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		username = user.Username
+	}
+	err := s.notificationService.Notify(models.Notification{
+		UserID:  assignment.AssignedBy,
+		Type:    models.NotificationTodoResponded,
+		Title:   fmt.Sprintf("组织待办已被%s", action),
+		Content: fmt.Sprintf("%s%s了组织待办「%s」", username, action, title),
+		TodoID:  assignment.TodoID,
+	})
+	if err != nil {
+		log.Printf("发送组织待办回应通知失败: %v", err)
+	}
+}
+
+// newAssignments 为每个成员构造待回应的分配记录
+func newAssignments(todoID, assignedBy uint, userIDs []uint, at time.Time) []models.TodoAssignment {
+	assignments := make([]models.TodoAssignment, 0, len(userIDs))
+	for _, userID := range userIDs {
+		assignments = append(assignments, models.TodoAssignment{
+			TodoID:     todoID,
+			AssignedTo: userID,
+			AssignedBy: assignedBy,
+			AssignedAt: at,
+			Status:     models.AssignmentPending,
+		})
+	}
+	return assignments
+}
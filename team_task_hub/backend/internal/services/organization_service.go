@@ -21,6 +21,7 @@ type OrganizationService struct {
 	orgMemberRepo *repositories.OrganizationMemberRepository
 	orgAppRepo    *repositories.OrganizationApplicationRepository
 	codeRepo      *repositories.VerificationCodeRepository
+	permRepo      *repositories.PermissionRepository
 }
 
 func NewOrganizationService(
@@ -28,12 +29,14 @@ func NewOrganizationService(
 	orgMemberRepo *repositories.OrganizationMemberRepository,
 	orgAppRepo *repositories.OrganizationApplicationRepository,
 	codeRepo *repositories.VerificationCodeRepository,
+	permRepo *repositories.PermissionRepository,
 ) *OrganizationService {
 	return &OrganizationService{
 		orgRepo:       orgRepo,
 		orgMemberRepo: orgMemberRepo,
 		orgAppRepo:    orgAppRepo,
 		codeRepo:      codeRepo,
+		permRepo:      permRepo,
 	}
 }
 
@@ -355,6 +358,9 @@ func (s *OrganizationService) RemoveOrganizationMember(orgID, userID uint, remov
 	if err := s.orgMemberRepo.RemoveMember(orgID, userID); err != nil {
 		return fmt.Errorf("删除成员失败，原因：%v", err)
 	}
+	if err := s.permRepo.RevokeAll(orgID, userID); err != nil {
+		return err
+	}
 
 	s.invalidateUserOrganizationCache(userID)
 
@@ -509,6 +515,59 @@ func (s *OrganizationService) GetUserOrgRole(orgID, userID uint) (string, error)
 	return "None", nil //如果都不是，返回None
 }
 
+// HasPermission 验证用户在组织中是否拥有某项权限，创建者和管理员拥有全部权限，其他成员需要被单独授予
+func (s *OrganizationService) HasPermission(orgID, userID uint, permissionType string) (bool, error) {
+	isAdmin, err := s.IsOrganizationAdmin(orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	if isAdmin {
+		return true, nil
+	}
+
+	isMember, err := s.IsOrganizationMember(orgID, userID)
+	if err != nil || !isMember {
+		return false, err
+	}
+	return s.permRepo.Has(orgID, userID, permissionType)
+}
+
+// GrantPermission 为组织成员授予权限
+func (s *OrganizationService) GrantPermission(orgID, userID, grantedBy uint, permissionType string) error {
+	isMember, err := s.IsOrganizationMember(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("该用户不是组织成员")
+	}
+
+	return s.permRepo.Grant(&models.Permission{
+		OrganizationID: orgID,
+		UserID:         userID,
+		PermissionType: permissionType,
+		GrantedBy:      grantedBy,
+		GrantedAt:      time.Now(),
+	})
+}
+
+// RevokePermission 撤销组织成员的权限
+func (s *OrganizationService) RevokePermission(orgID, userID uint, permissionType string) error {
+	revoked, err := s.permRepo.Revoke(orgID, userID, permissionType)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("该成员没有此权限")
+	}
+	return nil
+}
+
+// GetOrgPermissions 查询组织内单独授予的权限
+func (s *OrganizationService) GetOrgPermissions(orgID uint) ([]models.Permission, error) {
+	return s.permRepo.FindByOrganization(orgID)
+}
+
 // FindOrgInfoByName 根据关键词模糊化查询组织
 func (s *OrganizationService) FindOrgInfoByName(name string) ([]models.OrgInfo, error) {
 	cleanName := strings.TrimSpace(name)
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+
+	"team_task_hub/backend/internal/models"
+)
+
+// ReminderMessage 提醒投递内容
+type ReminderMessage struct {
+	Reminder *models.Reminder
+	Title    string
+	Content  string
+}
+
+// ReminderChannel 提醒投递渠道，新增渠道只需实现该接口并注册到ReminderService
+type ReminderChannel interface {
+	// Name 渠道名称，对应 Reminder.Channel
+	Name() string
+	// Send 向用户投递一条提醒
+	Send(user *models.User, message *ReminderMessage) error
+}
+
+// EmailReminderChannel 通过SMTP邮件投递提醒
+type EmailReminderChannel struct {
+	emailService *EmailService
+}
+
+func NewEmailReminderChannel(emailService *EmailService) *EmailReminderChannel {
+	return &EmailReminderChannel{emailService: emailService}
+}
+
+func (c *EmailReminderChannel) Name() string {
+	return models.ReminderChannelEmail
+}
+
+// Send 发送提醒邮件
+func (c *EmailReminderChannel) Send(user *models.User, message *ReminderMessage) error {
+	if user.Email == "" {
+		return fmt.Errorf("用户未设置邮箱")
+	}
+	return c.emailService.SendNotificationEmail(user.Email, message.Title+" - Team Task Hub", message.Content)
+}
+
+// InAppReminderChannel 通过站内通知投递提醒
+type InAppReminderChannel struct {
+	notificationService *NotificationService
+}
+
+func NewInAppReminderChannel(notificationService *NotificationService) *InAppReminderChannel {
+	return &InAppReminderChannel{notificationService: notificationService}
+}
+
+func (c *InAppReminderChannel) Name() string {
+	return models.ReminderChannelInApp
+}
+
+// Send 创建一条提醒类型的站内通知
+func (c *InAppReminderChannel) Send(user *models.User, message *ReminderMessage) error {
+	notification := models.Notification{
+		UserID:  user.ID,
+		Type:    models.NotificationReminder,
+		Title:   message.Title,
+		Content: message.Content,
+	}
+	if message.Reminder.TargetType == models.ReminderTargetTodo {
+		notification.TodoID = message.Reminder.TargetID
+	}
+	return c.notificationService.Notify(notification)
+}
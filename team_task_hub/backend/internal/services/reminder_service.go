@@ -0,0 +1,315 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// 提醒投递相关参数
+const (
+	reminderBatchSize   = 100              // 每轮最多认领的提醒数
+	maxReminderAttempts = 3                // 投递失败的最大尝试次数
+	reminderClaimTTL    = 10 * time.Minute // 认领后超过该时长仍未完成视为中断
+	reminderStaleAfter  = 24 * time.Hour   // 错过触发时间太久的提醒不再投递
+	maxReminderOffset   = 30 * 24 * 60     // 相对提醒最多提前30天（分钟）
+)
+
+// 提醒相关的通用错误
+var (
+	ErrReminderNotFound = errors.New("提醒不存在或已投递")
+	ErrInvalidReminder  = errors.New("提醒参数错误")
+	ErrReminderTarget   = errors.New("无权为该目标设置提醒")
+)
+
+type ReminderService struct {
+	reminderRepo      *repositories.ReminderRepository
+	todoRepo          *repositories.TodoRepository
+	activityRepo      *repositories.ActivityRepository
+	participationRepo *repositories.ActivityParticipationRepository
+	userRepo          *repositories.UserRepository
+	channels          map[string]ReminderChannel
+}
+
+func NewReminderService(
+	reminderRepo *repositories.ReminderRepository,
+	todoRepo *repositories.TodoRepository,
+	activityRepo *repositories.ActivityRepository,
+	participationRepo *repositories.ActivityParticipationRepository,
+	userRepo *repositories.UserRepository,
+) *ReminderService {
+	return &ReminderService{
+		reminderRepo:      reminderRepo,
+		todoRepo:          todoRepo,
+		activityRepo:      activityRepo,
+		participationRepo: participationRepo,
+		userRepo:          userRepo,
+		channels:          make(map[string]ReminderChannel),
+	}
+}
+
+// RegisterChannel 注册提醒投递渠道
+func (s *ReminderService) RegisterChannel(channel ReminderChannel) {
+	s.channels[channel.Name()] = channel
+}
+
+// CreateReminderRequest 创建提醒请求
+// anchor为start/end时按offset_minutes提前提醒，为absolute时使用remind_at
+type CreateReminderRequest struct {
+	TargetType    string    `json:"target_type" binding:"required,oneof=todo activity" example:"todo"`
+	TargetID      uint      `json:"target_id" binding:"required" example:"1"`
+	Anchor        string    `json:"anchor" binding:"omitempty,oneof=start end absolute" example:"start"`
+	OffsetMinutes int       `json:"offset_minutes" binding:"min=0" example:"15"`
+	RemindAt      time.Time `json:"remind_at" example:"2025-01-01T09:00:00+08:00"`
+	Channel       string    `json:"channel" binding:"omitempty,oneof=email in_app" example:"email"`
+}
+
+// reminderTarget 提醒目标的统一视图
+type reminderTarget struct {
+	Title     string
+	Kind      string
+	StartTime time.Time
+	EndTime   time.Time
+	Resolved  bool // 目标已完成或已取消，无需再提醒
+}
+
+// CreateReminder 为待办或活动创建提醒
+func (s *ReminderService) CreateReminder(userID uint, req *CreateReminderRequest) (*models.Reminder, error) {
+	if req.Anchor == "" {
+		req.Anchor = models.ReminderAnchorStart
+	}
+	if req.Channel == "" {
+		req.Channel = models.ReminderChannelEmail
+	}
+	if req.OffsetMinutes > maxReminderOffset {
+		return nil, fmt.Errorf("%w: 最多提前30天提醒", ErrInvalidReminder)
+	}
+
+	target, err := s.loadOwnedTarget(userID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Resolved {
+		return nil, fmt.Errorf("%w: %s已结束，无需提醒", ErrInvalidReminder, target.Kind)
+	}
+
+	reminder := &models.Reminder{
+		UserID:        userID,
+		TargetType:    req.TargetType,
+		TargetID:      req.TargetID,
+		Anchor:        req.Anchor,
+		OffsetMinutes: req.OffsetMinutes,
+		RemindAt:      req.RemindAt,
+		Channel:       req.Channel,
+		Status:        models.ReminderStatusPending,
+	}
+	if reminder.Anchor == models.ReminderAnchorAbsolute {
+		if req.RemindAt.IsZero() {
+			return nil, fmt.Errorf("%w: 绝对时间提醒必须指定remind_at", ErrInvalidReminder)
+		}
+		reminder.OffsetMinutes = 0
+	}
+	reminder.RemindAt = reminder.ComputeRemindAt(target.StartTime, target.EndTime)
+	if reminder.RemindAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: 提醒时间已过", ErrInvalidReminder)
+	}
+
+	if err := s.reminderRepo.Create(reminder); err != nil {
+		return nil, fmt.Errorf("创建提醒失败: %v", err)
+	}
+	return reminder, nil
+}
+
+// ListReminders 获取用户的提醒，targetType为空时返回全部
+func (s *ReminderService) ListReminders(userID uint, targetType string, targetID uint) ([]models.Reminder, error) {
+	if targetType != "" {
+		if _, err := s.loadOwnedTarget(userID, targetType, targetID); err != nil {
+			return nil, err
+		}
+	}
+	reminders, err := s.reminderRepo.FindByUser(userID, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("查询提醒失败: %v", err)
+	}
+	return reminders, nil
+}
+
+// CancelReminder 取消尚未投递的提醒
+func (s *ReminderService) CancelReminder(userID, reminderID uint) error {
+	cancelled, err := s.reminderRepo.Cancel(userID, reminderID)
+	if err != nil {
+		return fmt.Errorf("取消提醒失败: %v", err)
+	}
+	if !cancelled {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+// DispatchDueReminders 投递所有到期提醒，返回成功投递的数量
+// 提醒先以唯一token认领再投递，投递结果落库，服务重启或多实例运行时不会重复发送
+func (s *ReminderService) DispatchDueReminders() (int, error) {
+	now := time.Now()
+
+	if _, err := s.reminderRepo.FailStaleClaims(now.Add(-reminderClaimTTL)); err != nil {
+		return 0, fmt.Errorf("清理中断的提醒失败: %v", err)
+	}
+
+	token, err := randomHexToken(16)
+	if err != nil {
+		return 0, fmt.Errorf("生成认领标识失败: %v", err)
+	}
+	reminders, err := s.reminderRepo.ClaimDue(now, token, reminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("认领到期提醒失败: %v", err)
+	}
+
+	sent := 0
+	for i := range reminders {
+		if s.deliver(&reminders[i], now) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver 投递单条已认领的提醒并记录结果，返回是否投递成功
+func (s *ReminderService) deliver(reminder *models.Reminder, now time.Time) bool {
+	if now.Sub(reminder.RemindAt) > reminderStaleAfter {
+		s.markCancelled(reminder, "错过提醒时间过久")
+		return false
+	}
+
+	target, err := s.loadOwnedTarget(reminder.UserID, reminder.TargetType, reminder.TargetID)
+	if err != nil {
+		if errors.Is(err, ErrReminderTarget) || errors.Is(err, ErrTodoNotFound) {
+			s.markCancelled(reminder, err.Error())
+			return false
+		}
+		s.markFailed(reminder, err)
+		return false
+	}
+	if target.Resolved {
+		s.markCancelled(reminder, target.Kind+"已结束")
+		return false
+	}
+
+	channel, ok := s.channels[reminder.Channel]
+	if !ok {
+		s.markCancelled(reminder, "不支持的提醒渠道: "+reminder.Channel)
+		return false
+	}
+	user, err := s.userRepo.FindByID(reminder.UserID)
+	if err != nil {
+		s.markFailed(reminder, err)
+		return false
+	}
+
+	if err := channel.Send(user, buildReminderMessage(reminder, target)); err != nil {
+		s.markFailed(reminder, err)
+		return false
+	}
+	if err := s.reminderRepo.MarkSent(reminder.ID, time.Now()); err != nil {
+		log.Printf("记录提醒 %d 投递状态失败: %v", reminder.ID, err)
+	}
+	return true
+}
+
+// markFailed 记录投递失败，未超过最大尝试次数时等待下一轮重试
+func (s *ReminderService) markFailed(reminder *models.Reminder, cause error) {
+	retry := reminder.Attempts+1 < maxReminderAttempts
+	if err := s.reminderRepo.MarkFailed(reminder.ID, cause.Error(), retry); err != nil {
+		log.Printf("记录提醒 %d 失败状态失败: %v", reminder.ID, err)
+	}
+}
+
+// markCancelled 目标失效时取消提醒
+func (s *ReminderService) markCancelled(reminder *models.Reminder, reason string) {
+	if err := s.reminderRepo.MarkCancelled(reminder.ID, reason); err != nil {
+		log.Printf("取消提醒 %d 失败: %v", reminder.ID, err)
+	}
+}
+
+// loadOwnedTarget 加载提醒目标并校验用户权限：待办必须是自己创建的，活动必须已参与
+func (s *ReminderService) loadOwnedTarget(userID uint, targetType string, targetID uint) (*reminderTarget, error) {
+	switch targetType {
+	case models.ReminderTargetTodo:
+		todo, err := s.todoRepo.FindByID(targetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTodoNotFound
+			}
+			return nil, fmt.Errorf("查询待办失败: %v", err)
+		}
+		if todo.CreatorUserID != userID {
+			return nil, ErrReminderTarget
+		}
+		if todo.HasChildren {
+			return nil, fmt.Errorf("%w: 重复待办请为具体实例设置提醒", ErrInvalidReminder)
+		}
+		return &reminderTarget{
+			Title:     todo.Title,
+			Kind:      "待办",
+			StartTime: todo.StartTime,
+			EndTime:   todo.EndTime,
+			Resolved:  isResolvedStatus(todo.Status),
+		}, nil
+
+	case models.ReminderTargetActivity:
+		joined, err := s.participationRepo.Exists(targetID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !joined {
+			return nil, ErrReminderTarget
+		}
+		activity, err := s.activityRepo.GetByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+		return &reminderTarget{
+			Title:     activity.Title,
+			Kind:      "活动",
+			StartTime: activity.StartTime,
+			EndTime:   activity.EndTime,
+			Resolved:  activity.Status != "active",
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: 不支持的提醒目标类型", ErrInvalidReminder)
+	}
+}
+
+// buildReminderMessage 生成提醒标题和正文
+func buildReminderMessage(reminder *models.Reminder, target *reminderTarget) *ReminderMessage {
+	const layout = "2006-01-02 15:04"
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "%s「%s」\n", target.Kind, target.Title)
+	fmt.Fprintf(&content, "开始时间: %s\n", target.StartTime.Format(layout))
+	fmt.Fprintf(&content, "结束时间: %s\n", target.EndTime.Format(layout))
+	switch {
+	case reminder.Anchor == models.ReminderAnchorAbsolute:
+	case reminder.OffsetMinutes == 0 && reminder.Anchor == models.ReminderAnchorStart:
+		content.WriteString("现在开始")
+	case reminder.OffsetMinutes == 0:
+		content.WriteString("已到截止时间")
+	case reminder.Anchor == models.ReminderAnchorStart:
+		fmt.Fprintf(&content, "将在 %d 分钟后开始", reminder.OffsetMinutes)
+	default:
+		fmt.Fprintf(&content, "将在 %d 分钟后截止", reminder.OffsetMinutes)
+	}
+
+	return &ReminderMessage{
+		Reminder: reminder,
+		Title:    fmt.Sprintf("%s提醒：%s", target.Kind, target.Title),
+		Content:  content.String(),
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+)
+
+// scheduleGranularity 自动排程的开始时间对齐到该粒度
+const scheduleGranularity = 5 * time.Minute
+
+// timeSlot 半开区间[start, end)表示的时间段
+type timeSlot struct {
+	start time.Time
+	end   time.Time
+}
+
+// workingSlots 生成[from, to)内每个工作日的工作时段，按用户时区的本地钟点计算
+func workingSlots(pref *models.SchedulePreference, loc *time.Location, from, to time.Time) []timeSlot {
+	var slots []timeSlot
+	local := from.In(loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !pref.IsWorkDay(day.Weekday()) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, pref.WorkStartMinute, 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, pref.WorkEndMinute, 0, 0, loc)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if start.Before(end) {
+			slots = append(slots, timeSlot{start: start, end: end})
+		}
+	}
+	return slots
+}
+
+// subtractSlots 从空闲时段中去掉与忙碌时段重叠的部分，两者都按开始时间升序
+func subtractSlots(free []timeSlot, busy []timeSlot) []timeSlot {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+
+	var result []timeSlot
+	for _, slot := range free {
+		for _, b := range busy {
+			if !b.end.After(slot.start) || !b.start.Before(slot.end) {
+				continue
+			}
+			if b.start.After(slot.start) {
+				result = append(result, timeSlot{start: slot.start, end: b.start})
+			}
+			slot.start = b.end
+			if !slot.start.Before(slot.end) {
+				break
+			}
+		}
+		if slot.start.Before(slot.end) {
+			result = append(result, slot)
+		}
+	}
+	return result
+}
+
+// earliestFit 在空闲时段中找到最早能容纳duration且不晚于deadline结束的时段，开始时间按排程粒度对齐
+func earliestFit(free []timeSlot, duration time.Duration, deadline time.Time) (timeSlot, bool) {
+	for _, slot := range free {
+		start := alignSlotStart(slot.start)
+		end := start.Add(duration)
+		if end.After(deadline) {
+			return timeSlot{}, false
+		}
+		if !end.After(slot.end) {
+			return timeSlot{start: start, end: end}, true
+		}
+	}
+	return timeSlot{}, false
+}
+
+// alignSlotStart 将开始时间向后对齐到排程粒度
+func alignSlotStart(t time.Time) time.Time {
+	aligned := t.Truncate(scheduleGranularity)
+	if aligned.Before(t) {
+		aligned = aligned.Add(scheduleGranularity)
+	}
+	return aligned
+}
+
+// slotWithin 判断时间段是否完整落在某个空闲时段内
+func slotWithin(free []timeSlot, target timeSlot) bool {
+	for _, slot := range free {
+		if !target.start.Before(slot.start) && !target.end.After(slot.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// blocksSchedule 判断固定待办是否占用具体时段
+// 全天待办和跨多天的待办（如组织下发的长期任务）只表示在此期间完成，不占用工作时间
+func blocksSchedule(start, end time.Time, loc *time.Location) bool {
+	if end.Sub(start) >= 24*time.Hour-time.Second {
+		return false
+	}
+	// 夏令时切换当天的全天待办不足24小时，按本地钟点识别
+	local := start.In(loc)
+	if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 &&
+		!end.Before(local.AddDate(0, 0, 1).Add(-time.Second)) {
+		return false
+	}
+	return true
+}
+
+// urgencyRank 紧急程度的排程优先级，数值越小越先安排
+func urgencyRank(urgency string) int {
+	switch urgency {
+	case "high":
+		return 0
+	case "low":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// sortByPriority 按紧急程度从高到低、截止时间从早到晚排列弹性待办
+func sortByPriority(todos []models.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		ri, rj := urgencyRank(todos[i].Urgency), urgencyRank(todos[j].Urgency)
+		if ri != rj {
+			return ri < rj
+		}
+		if !todos[i].Deadline.Equal(todos[j].Deadline) {
+			return todos[i].Deadline.Before(todos[j].Deadline)
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"team_task_hub/backend/internal/cache"
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+)
+
+// statsCacheExpiration 统计结果的缓存时间，待办变化时通过版本号提前失效
+const statsCacheExpiration = 10 * time.Minute
+
+// 趋势统计的周期
+const (
+	StatsPeriodWeek  = "week"
+	StatsPeriodMonth = "month"
+)
+
+// 趋势统计默认和最多返回的周期数
+const (
+	defaultTrendWeeks  = 8
+	defaultTrendMonths = 6
+	maxTrendWeeks      = 52
+	maxTrendMonths     = 24
+)
+
+// ErrInvalidStatsParams 统计参数错误
+var ErrInvalidStatsParams = errors.New("统计参数错误")
+
+type StatsService struct {
+	todoRepo *repositories.TodoRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewStatsService(todoRepo *repositories.TodoRepository, userRepo *repositories.UserRepository) *StatsService {
+	return &StatsService{
+		todoRepo: todoRepo,
+		userRepo: userRepo,
+	}
+}
+
+// CompletionStats 一组待办的完成情况，统计对象为截止时间落在区间内、未取消的待办实例
+type CompletionStats struct {
+	Due            int     `json:"due"`             // 待办总数
+	Completed      int     `json:"completed"`       // 已完成
+	OnTime         int     `json:"on_time"`         // 在截止时间前完成
+	Late           int     `json:"late"`            // 超过截止时间后完成
+	Overdue        int     `json:"overdue"`         // 已过截止时间仍未完成
+	Open           int     `json:"open"`            // 尚未到截止时间且未完成
+	CompletionRate float64 `json:"completion_rate"` // 已完成/待办总数
+	OnTimeRate     float64 `json:"on_time_rate"`    // 按时完成/已完成
+}
+
+// CategoryStats 单个分类的完成情况
+type CategoryStats struct {
+	Category string `json:"category"`
+	CompletionStats
+}
+
+// CompletionStreaks 连续完成天数，日期按用户时区计算
+type CompletionStreaks struct {
+	Current           int    `json:"current"`                       // 截至今天连续有完成记录的天数，今天尚未完成时截至昨天
+	Longest           int    `json:"longest"`                       // 历史最长连续天数
+	LongestStart      string `json:"longest_start,omitempty"`       // 最长连续的起始日期
+	LongestEnd        string `json:"longest_end,omitempty"`         // 最长连续的结束日期
+	ActiveDays        int    `json:"active_days"`                   // 有完成记录的总天数
+	LastCompletedDate string `json:"last_completed_date,omitempty"` // 最近一次完成的日期
+}
+
+// TodoStatsOverview 一段时间内的统计总览
+type TodoStatsOverview struct {
+	From       time.Time         `json:"from"`
+	To         time.Time         `json:"to"`
+	Summary    CompletionStats   `json:"summary"`
+	Categories []CategoryStats   `json:"categories"`
+	Streaks    CompletionStreaks `json:"streaks"`
+}
+
+// StatsTrendBucket 趋势中的一个周期
+type StatsTrendBucket struct {
+	Label string    `json:"label"` // 周期起始日期（YYYY-MM-DD）或月份（YYYY-MM）
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	CompletionStats
+	CompletedInPeriod int `json:"completed_in_period"` // 在该周期内完成的待办数，不论截止时间
+}
+
+// TodoStatsTrends 按周或按月的完成趋势，最后一个周期为当前周期
+type TodoStatsTrends struct {
+	Period  string             `json:"period"`
+	Buckets []StatsTrendBucket `json:"buckets"`
+}
+
+// add 计入一个待办
+func (c *CompletionStats) add(todo *models.Todo, now time.Time) {
+	c.Due++
+	switch {
+	case todo.Status == TodoStatusCompleted:
+		c.Completed++
+		if todo.CompletedAt.After(todo.EndTime) {
+			c.Late++
+		} else {
+			c.OnTime++
+		}
+	case todo.EndTime.Before(now):
+		c.Overdue++
+	default:
+		c.Open++
+	}
+}
+
+// finish 计算比率
+func (c *CompletionStats) finish() {
+	if c.Due > 0 {
+		c.CompletionRate = float64(c.Completed) / float64(c.Due)
+	}
+	if c.Completed > 0 {
+		c.OnTimeRate = float64(c.OnTime) / float64(c.Completed)
+	}
+}
+
+// GetOverview 统计[from, to)内的完成率、按时完成情况和分类明细，并附带连续完成天数
+func (s *StatsService) GetOverview(userID uint, fromStr, toStr string) (*TodoStatsOverview, error) {
+	loc := userLocation(s.userRepo, userID)
+	from, to, err := parseDateRange(fromStr, toStr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatsParams, err)
+	}
+
+	name := fmt.Sprintf("overview:%s:%d:%d", loc, from.Unix(), to.Unix())
+	overview, err := cachedStats(userID, name, func() (*TodoStatsOverview, error) {
+		todos, err := s.todoRepo.FindDueForStats(userID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("查询待办失败: %v", err)
+		}
+
+		now := time.Now()
+		overview := &TodoStatsOverview{From: from, To: to}
+		byCategory := make(map[string]*CategoryStats)
+		for i := range todos {
+			overview.Summary.add(&todos[i], now)
+			category := byCategory[todos[i].Category]
+			if category == nil {
+				category = &CategoryStats{Category: todos[i].Category}
+				byCategory[todos[i].Category] = category
+			}
+			category.add(&todos[i], now)
+		}
+		overview.Summary.finish()
+
+		overview.Categories = make([]CategoryStats, 0, len(byCategory))
+		for _, category := range byCategory {
+			category.finish()
+			overview.Categories = append(overview.Categories, *category)
+		}
+		sort.Slice(overview.Categories, func(i, j int) bool {
+			a, b := overview.Categories[i], overview.Categories[j]
+			if a.Due != b.Due {
+				return a.Due > b.Due
+			}
+			return a.Category < b.Category
+		})
+		return overview, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 连续天数单独缓存，随日期变化
+	streaks, err := s.GetStreaks(userID)
+	if err != nil {
+		return nil, err
+	}
+	overview.Streaks = *streaks
+	return overview, nil
+}
+
+// GetTrends 按周（周一开始）或按月统计最近count个周期的完成情况
+func (s *StatsService) GetTrends(userID uint, period string, count int) (*TodoStatsTrends, error) {
+	switch period {
+	case "", StatsPeriodWeek:
+		period = StatsPeriodWeek
+		if count == 0 {
+			count = defaultTrendWeeks
+		}
+		if count < 0 || count > maxTrendWeeks {
+			return nil, fmt.Errorf("%w: 按周统计最多%d周", ErrInvalidStatsParams, maxTrendWeeks)
+		}
+	case StatsPeriodMonth:
+		if count == 0 {
+			count = defaultTrendMonths
+		}
+		if count < 0 || count > maxTrendMonths {
+			return nil, fmt.Errorf("%w: 按月统计最多%d个月", ErrInvalidStatsParams, maxTrendMonths)
+		}
+	default:
+		return nil, fmt.Errorf("%w: 不支持的统计周期 %s", ErrInvalidStatsParams, period)
+	}
+
+	loc := userLocation(s.userRepo, userID)
+	buckets := trendBuckets(period, count, loc)
+	from, to := buckets[0].From, buckets[len(buckets)-1].To
+
+	name := fmt.Sprintf("trends:%s:%s:%d", loc, period, from.Unix())
+	return cachedStats(userID, name, func() (*TodoStatsTrends, error) {
+		todos, err := s.todoRepo.FindDueForStats(userID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("查询待办失败: %v", err)
+		}
+		completions, err := s.todoRepo.FindCompletionTimes(userID, from)
+		if err != nil {
+			return nil, fmt.Errorf("查询完成记录失败: %v", err)
+		}
+
+		now := time.Now()
+		for i := range todos {
+			if b := findTrendBucket(buckets, todos[i].EndTime); b != nil {
+				b.add(&todos[i], now)
+			}
+		}
+		for _, completedAt := range completions {
+			if b := findTrendBucket(buckets, completedAt); b != nil {
+				b.CompletedInPeriod++
+			}
+		}
+		for i := range buckets {
+			buckets[i].finish()
+		}
+		return &TodoStatsTrends{Period: period, Buckets: buckets}, nil
+	})
+}
+
+// GetStreaks 获取连续完成天数
+func (s *StatsService) GetStreaks(userID uint) (*CompletionStreaks, error) {
+	loc := userLocation(s.userRepo, userID)
+	// 当前连续天数随日期变化，缓存键包含今天的日期
+	name := fmt.Sprintf("streaks:%s:%s", loc, time.Now().In(loc).Format("2006-01-02"))
+	return cachedStats(userID, name, func() (*CompletionStreaks, error) {
+		return s.computeStreaks(userID, loc)
+	})
+}
+
+// computeStreaks 根据全部完成时间计算连续完成天数
+func (s *StatsService) computeStreaks(userID uint, loc *time.Location) (*CompletionStreaks, error) {
+	completions, err := s.todoRepo.FindCompletionTimes(userID, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("查询完成记录失败: %v", err)
+	}
+
+	// 按用户时区去重得到有完成记录的日期，完成时间已升序
+	var days []time.Time
+	for _, completedAt := range completions {
+		day, _ := DayRange(completedAt.In(loc), loc)
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+
+	streaks := &CompletionStreaks{ActiveDays: len(days)}
+	if len(days) == 0 {
+		return streaks, nil
+	}
+
+	runStart := 0
+	for i := range days {
+		if i > 0 && !days[i-1].AddDate(0, 0, 1).Equal(days[i]) {
+			runStart = i
+		}
+		if length := i - runStart + 1; length > streaks.Longest {
+			streaks.Longest = length
+			streaks.LongestStart = days[runStart].Format("2006-01-02")
+			streaks.LongestEnd = days[i].Format("2006-01-02")
+		}
+	}
+
+	last := days[len(days)-1]
+	streaks.LastCompletedDate = last.Format("2006-01-02")
+	today, _ := TodayRange(loc)
+	if last.Equal(today) || last.Equal(today.AddDate(0, 0, -1)) {
+		// 最后一段连续记录的长度
+		streaks.Current = len(days) - runStart
+	}
+	return streaks, nil
+}
+
+// trendBuckets 生成截至当前周期的count个周期
+func trendBuckets(period string, count int, loc *time.Location) []StatsTrendBucket {
+	var start time.Time
+	if period == StatsPeriodMonth {
+		now := time.Now().In(loc)
+		start, _ = MonthRange(now.Year(), now.Month(), loc)
+		start = start.AddDate(0, 1-count, 0)
+	} else {
+		start, _ = ThisWeekRange(loc)
+		start = start.AddDate(0, 0, 7*(1-count))
+	}
+
+	buckets := make([]StatsTrendBucket, count)
+	for i := range buckets {
+		bucket := &buckets[i]
+		bucket.From = start
+		if period == StatsPeriodMonth {
+			bucket.To = start.AddDate(0, 1, 0)
+			bucket.Label = start.Format("2006-01")
+		} else {
+			bucket.To = start.AddDate(0, 0, 7)
+			bucket.Label = start.Format("2006-01-02")
+		}
+		start = bucket.To
+	}
+	return buckets
+}
+
+// findTrendBucket 找到时间所在的周期
+func findTrendBucket(buckets []StatsTrendBucket, t time.Time) *StatsTrendBucket {
+	i := sort.Search(len(buckets), func(i int) bool { return buckets[i].To.After(t) })
+	if i < len(buckets) && !t.Before(buckets[i].From) {
+		return &buckets[i]
+	}
+	return nil
+}
+
+// cachedStats 读取当前版本下缓存的统计结果，未命中时计算并写入缓存；
+// 缓存不可用时直接计算
+func cachedStats[T any](userID uint, name string, compute func() (*T, error)) (*T, error) {
+	version, versionErr := cache.GetUserStatsVersion(userID)
+	if versionErr == nil {
+		var cached T
+		if err := cache.GetUserStats(userID, version, name, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	result, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	if versionErr == nil {
+		go cache.SetUserStats(userID, version, name, result, statsCacheExpiration)
+	}
+	return result, nil
+}
+
+// invalidateTodoStats 待办发生变化后使用户已缓存的统计失效
+func invalidateTodoStats(userID uint) {
+	if err := cache.BumpUserStatsVersion(userID); err != nil {
+		log.Printf("刷新用户 %d 的统计缓存版本失败: %v", userID, err)
+	}
+}
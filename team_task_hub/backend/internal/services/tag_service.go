@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type TagService struct {
+	tagRepo *repositories.TagRepository
+}
+
+func NewTagService(tagRepo *repositories.TagRepository) *TagService {
+	return &TagService{tagRepo: tagRepo}
+}
+
+// TagRequest 创建/更新标签请求
+type TagRequest struct {
+	Name  string `json:"name" binding:"required,max=50" example:"会议"`
+	Color string `json:"color" binding:"omitempty,max=20" example:"#2196f3"`
+}
+
+// ListTags 获取用户的全部标签
+func (s *TagService) ListTags(userID uint) ([]models.Tag, error) {
+	tags, err := s.tagRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	}
+	return tags, nil
+}
+
+// CreateTag 创建标签
+func (s *TagService) CreateTag(userID uint, req *TagRequest) (*models.Tag, error) {
+	name, err := normalizeLabelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if s.nameTaken(userID, name, 0) {
+		return nil, ErrLabelNameConflict
+	}
+
+	tag := &models.Tag{
+		UserID: userID,
+		Name:   name,
+		Color:  strings.TrimSpace(req.Color),
+	}
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, fmt.Errorf("创建标签失败: %v", err)
+	}
+	return tag, nil
+}
+
+// UpdateTag 修改标签名称和颜色
+func (s *TagService) UpdateTag(userID, tagID uint, req *TagRequest) (*models.Tag, error) {
+	tag, err := s.findTag(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := normalizeLabelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if s.nameTaken(userID, name, tag.ID) {
+		return nil, ErrLabelNameConflict
+	}
+
+	updates := map[string]any{
+		"name":  name,
+		"color": strings.TrimSpace(req.Color),
+	}
+	if err := s.tagRepo.Update(tag, updates); err != nil {
+		return nil, fmt.Errorf("更新标签失败: %v", err)
+	}
+	return s.findTag(userID, tagID)
+}
+
+// DeleteTag 删除标签，并移除其与待办的关联
+func (s *TagService) DeleteTag(userID, tagID uint) error {
+	tag, err := s.findTag(userID, tagID)
+	if err != nil {
+		return err
+	}
+	if err := s.tagRepo.Delete(tag); err != nil {
+		return fmt.Errorf("删除标签失败: %v", err)
+	}
+	return nil
+}
+
+// findTag 查找属于用户的标签
+func (s *TagService) findTag(userID, tagID uint) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindByID(userID, tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	}
+	return tag, nil
+}
+
+// nameTaken 判断用户是否已有同名标签（排除指定ID）
+func (s *TagService) nameTaken(userID uint, name string, excludeID uint) bool {
+	tag, err := s.tagRepo.FindByName(userID, name)
+	return err == nil && tag.ID != excludeID
+}
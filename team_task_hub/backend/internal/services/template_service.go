@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// 模板相关的错误，处理器据此映射HTTP状态码
+var (
+	ErrTemplateNotFound  = errors.New("模板不存在")
+	ErrTemplateForbidden = errors.New("无权修改该模板")
+)
+
+const (
+	// maxTemplatesPerUser 每个用户最多创建的模板数量
+	maxTemplatesPerUser = 50
+	// maxTemplateItems 每个模板最多包含的待办数量
+	maxTemplateItems = 20
+	// maxTemplateDayOffset 模板待办相对实例化日期的最大天数
+	maxTemplateDayOffset = 365
+)
+
+// TemplateService 待办模板服务层，实例化时的待办创建委托给TodoService
+type TemplateService struct {
+	templateRepo  *repositories.TodoTemplateRepository
+	orgMemberRepo *repositories.OrganizationMemberRepository
+	todoService   *TodoService
+}
+
+// NewTemplateService 创建待办模板服务实例
+func NewTemplateService(templateRepo *repositories.TodoTemplateRepository,
+	orgMemberRepo *repositories.OrganizationMemberRepository, todoService *TodoService) *TemplateService {
+	return &TemplateService{
+		templateRepo:  templateRepo,
+		orgMemberRepo: orgMemberRepo,
+		todoService:   todoService,
+	}
+}
+
+// TodoTemplateItemRequest 模板中的一个待办
+type TodoTemplateItemRequest struct {
+	Title           string   `json:"title" binding:"required,max=200" example:"迭代计划会"`
+	Description     string   `json:"description" binding:"required" example:"评估上个迭代并排期"`
+	Urgency         string   `json:"urgency" binding:"omitempty,oneof=low medium high" example:"medium"` // 默认medium
+	Category        string   `json:"category" binding:"omitempty,max=50" example:"work"`                 // 默认分类
+	DayOffset       int      `json:"day_offset" binding:"min=0" example:"0"`                             // 相对实例化日期的天数
+	StartMinute     int      `json:"start_minute" binding:"min=0,max=1439" example:"570"`                // 开始时刻，当天0点起的分钟数
+	DurationMinutes int      `json:"duration_minutes" binding:"required,min=1" example:"60"`
+	RepeatType      string   `json:"repeat_type" binding:"omitempty,oneof=none daily weekly monthly yearly" example:"none"`
+	RepeatInterval  int      `json:"repeat_interval" binding:"min=0" example:"1"`
+	RRule           string   `json:"rrule,omitempty" example:"FREQ=WEEKLY;COUNT=4"`
+	Checklist       []string `json:"checklist,omitempty" binding:"omitempty,dive,max=200" example:"回顾燃尽图"`
+	AutoComplete    bool     `json:"auto_complete" example:"false"`
+}
+
+// CreateTodoTemplateRequest 创建模板请求
+type CreateTodoTemplateRequest struct {
+	Name           string                    `json:"name" binding:"required,max=100" example:"每周迭代"`
+	Description    string                    `json:"description" example:"周一计划会、周五周报"`
+	OrganizationID uint                      `json:"organization_id" example:"0"` // 共享到的组织，0表示不共享
+	Items          []TodoTemplateItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdateTodoTemplateRequest 更新模板请求，items非空时整体替换模板中的待办
+type UpdateTodoTemplateRequest struct {
+	Name           *string                   `json:"name,omitempty" binding:"omitempty,max=100" example:"每周迭代"`
+	Description    *string                   `json:"description,omitempty"`
+	OrganizationID *uint                     `json:"organization_id,omitempty" example:"3"` // 传0取消共享
+	Items          []TodoTemplateItemRequest `json:"items,omitempty" binding:"omitempty,dive"`
+}
+
+// InstantiateTemplateRequest 实例化模板请求
+type InstantiateTemplateRequest struct {
+	Date    string `json:"date" binding:"required" example:"2024-01-15"` // 基准日期（YYYY-MM-DD），按用户时区解析
+	ItemIDs []uint `json:"item_ids,omitempty"`                           // 只创建模板中的这些待办，为空时全部创建
+}
+
+// InstantiatedTodo 由模板创建的一个待办
+type InstantiatedTodo struct {
+	ItemID    uint      `json:"item_id"`
+	TodoID    uint      `json:"todo_id"`
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// InstantiateTemplateResult 实例化模板的结果
+type InstantiateTemplateResult struct {
+	TemplateID uint               `json:"template_id"`
+	Date       string             `json:"date"`
+	Todos      []InstantiatedTodo `json:"todos"`
+}
+
+// ListTemplates 查询用户可见的模板：自己创建的，以及共享到所在组织的
+func (s *TemplateService) ListTemplates(userID uint) ([]models.TodoTemplate, error) {
+	templates, err := s.templateRepo.FindVisible(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询模板失败: %v", err)
+	}
+	for i := range templates {
+		loadTemplateChecklists(&templates[i])
+	}
+	return templates, nil
+}
+
+// GetTemplate 查询用户可见的单个模板
+func (s *TemplateService) GetTemplate(userID, templateID uint) (*models.TodoTemplate, error) {
+	return s.findTemplate(userID, templateID)
+}
+
+// CreateTemplate 创建模板，共享到组织时要求用户是该组织的成员
+func (s *TemplateService) CreateTemplate(userID uint, req *CreateTodoTemplateRequest) (*models.TodoTemplate, error) {
+	count, err := s.templateRepo.CountByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询模板失败: %v", err)
+	}
+	if count >= maxTemplatesPerUser {
+		return nil, fmt.Errorf("%w: 最多创建%d个模板", ErrInvalidTodoParams, maxTemplatesPerUser)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 模板名称不能为空", ErrInvalidTodoParams)
+	}
+	if err := s.ensureTemplateOrganization(userID, req.OrganizationID); err != nil {
+		return nil, err
+	}
+	items, err := s.buildTemplateItems(userID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.TodoTemplate{
+		UserID:         userID,
+		OrganizationID: req.OrganizationID,
+		Name:           name,
+		Description:    req.Description,
+		Items:          items,
+	}
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, fmt.Errorf("创建模板失败: %v", err)
+	}
+	return template, nil
+}
+
+// UpdateTemplate 修改模板，只有创建者可以修改
+func (s *TemplateService) UpdateTemplate(userID, templateID uint, req *UpdateTodoTemplateRequest) (*models.TodoTemplate, error) {
+	template, err := s.findOwnTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: 模板名称不能为空", ErrInvalidTodoParams)
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.OrganizationID != nil && *req.OrganizationID != template.OrganizationID {
+		if err := s.ensureTemplateOrganization(userID, *req.OrganizationID); err != nil {
+			return nil, err
+		}
+		updates["organization_id"] = *req.OrganizationID
+	}
+	var items []models.TodoTemplateItem
+	if len(req.Items) > 0 {
+		if items, err = s.buildTemplateItems(userID, req.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.templateRepo.UpdateWithItems(template, updates, items); err != nil {
+		return nil, fmt.Errorf("更新模板失败: %v", err)
+	}
+	return s.findTemplate(userID, templateID)
+}
+
+// DeleteTemplate 删除模板，只有创建者可以删除，已创建的待办不受影响
+func (s *TemplateService) DeleteTemplate(userID, templateID uint) error {
+	template, err := s.findOwnTemplate(userID, templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(template); err != nil {
+		return fmt.Errorf("删除模板失败: %v", err)
+	}
+	return nil
+}
+
+// InstantiateTemplate 以指定日期为基准，按模板在一个事务中创建待办
+// 每个待办按创建待办的规则校验，任一待办无效时全部不创建；
+// 模板中的分类在当前用户下不存在时（如组织成员共享的模板）使用默认分类
+func (s *TemplateService) InstantiateTemplate(userID, templateID uint, req *InstantiateTemplateRequest) (*InstantiateTemplateResult, error) {
+	template, err := s.findTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	loc := s.todoService.userLocation(userID)
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(req.Date), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 日期格式应为YYYY-MM-DD", ErrInvalidTodoParams)
+	}
+	items, err := selectTemplateItems(template.Items, req.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 复用导入的校验和批量创建流程，模板待办等同于一批导入条目
+	categories := make(map[string]*models.Category)
+	results := make([]ImportRowResult, len(items))
+	prepared := make([]preparedImport, len(items))
+	for i, item := range items {
+		if _, ok := categories[item.Category]; !ok {
+			category, err := s.todoService.resolveCategory(userID, item.Category)
+			if errors.Is(err, ErrInvalidTodoParams) {
+				category, err = s.todoService.resolveCategory(userID, "")
+			}
+			if err != nil {
+				return nil, err
+			}
+			categories[item.Category] = category
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day()+item.DayOffset,
+			item.StartMinute/60, item.StartMinute%60, 0, 0, loc)
+		row := importRow{row: i + 1, req: &CreateTodoRequest{
+			Title:          item.Title,
+			Description:    item.Description,
+			StartTime:      start,
+			EndTime:        start.Add(time.Duration(item.DurationMinutes) * time.Minute),
+			Urgency:        item.Urgency,
+			Category:       item.Category,
+			Checklist:      item.ChecklistItems,
+			AutoComplete:   item.AutoComplete,
+			RepeatType:     item.RepeatType,
+			RepeatInterval: item.RepeatInterval,
+			RRule:          item.RRule,
+		}}
+		p, err := s.todoService.prepareImport(userID, row, categories)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 模板待办 %s 无效: %v", ErrInvalidTodoParams, item.Title, err)
+		}
+		p.result = &results[i]
+		prepared[i] = p
+	}
+
+	if err := s.todoService.createImportedTodos(userID, prepared); err != nil {
+		return nil, err
+	}
+	invalidateTodoStats(userID)
+	s.todoService.replanAfterChange(userID)
+
+	result := &InstantiateTemplateResult{
+		TemplateID: template.ID,
+		Date:       day.Format("2006-01-02"),
+		Todos:      make([]InstantiatedTodo, len(items)),
+	}
+	for i, item := range items {
+		result.Todos[i] = InstantiatedTodo{
+			ItemID:    item.ID,
+			TodoID:    results[i].TodoID,
+			Title:     item.Title,
+			StartTime: prepared[i].req.StartTime,
+			EndTime:   prepared[i].req.EndTime,
+		}
+	}
+	return result, nil
+}
+
+// buildTemplateItems 校验模板待办并转换为模型，重复规则和分类按创建待办的规则校验
+func (s *TemplateService) buildTemplateItems(userID uint, reqs []TodoTemplateItemRequest) ([]models.TodoTemplateItem, error) {
+	if len(reqs) > maxTemplateItems {
+		return nil, fmt.Errorf("%w: 每个模板最多包含%d个待办", ErrInvalidTodoParams, maxTemplateItems)
+	}
+
+	items := make([]models.TodoTemplateItem, len(reqs))
+	for i, req := range reqs {
+		title := strings.TrimSpace(req.Title)
+		if title == "" || strings.TrimSpace(req.Description) == "" {
+			return nil, fmt.Errorf("%w: 第%d个待办的标题和内容不能为空", ErrInvalidTodoParams, i+1)
+		}
+		if req.DayOffset > maxTemplateDayOffset {
+			return nil, fmt.Errorf("%w: 第%d个待办的day_offset不能超过%d", ErrInvalidTodoParams, i+1, maxTemplateDayOffset)
+		}
+		if _, err := s.todoService.resolveRecurrenceRule(&CreateTodoRequest{
+			StartTime:      time.Now(),
+			RepeatType:     req.RepeatType,
+			RepeatInterval: req.RepeatInterval,
+			RRule:          req.RRule,
+		}); err != nil {
+			return nil, fmt.Errorf("第%d个待办: %w", i+1, err)
+		}
+		category, err := s.todoService.resolveCategory(userID, req.Category)
+		if err != nil {
+			return nil, fmt.Errorf("第%d个待办: %w", i+1, err)
+		}
+
+		var checklist []string
+		for _, step := range req.Checklist {
+			step = strings.TrimSpace(step)
+			if step == "" {
+				continue
+			}
+			if strings.ContainsAny(step, "\r\n") {
+				return nil, fmt.Errorf("%w: 清单项不能包含换行", ErrInvalidTodoParams)
+			}
+			checklist = append(checklist, step)
+		}
+
+		urgency := req.Urgency
+		if urgency == "" {
+			urgency = "medium"
+		}
+		repeatType := req.RepeatType
+		if repeatType == "" {
+			repeatType = "none"
+		}
+		items[i] = models.TodoTemplateItem{
+			Position:        i,
+			Title:           title,
+			Description:     req.Description,
+			Urgency:         urgency,
+			Category:        category.Name,
+			DayOffset:       req.DayOffset,
+			StartMinute:     req.StartMinute,
+			DurationMinutes: req.DurationMinutes,
+			RepeatType:      repeatType,
+			RepeatInterval:  max(req.RepeatInterval, 1),
+			RRule:           strings.TrimSpace(req.RRule),
+			AutoComplete:    req.AutoComplete,
+		}
+		items[i].SetChecklist(checklist)
+	}
+	return items, nil
+}
+
+// ensureTemplateOrganization 共享到组织时校验用户是该组织的活跃成员
+func (s *TemplateService) ensureTemplateOrganization(userID, organizationID uint) error {
+	if organizationID == 0 {
+		return nil
+	}
+	member, err := s.orgMemberRepo.GetMember(organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.Status != "active" {
+		return fmt.Errorf("%w: 只能共享到自己所在的组织", ErrTemplateForbidden)
+	}
+	return nil
+}
+
+// findTemplate 查询用户可见的模板
+func (s *TemplateService) findTemplate(userID, templateID uint) (*models.TodoTemplate, error) {
+	template, err := s.templateRepo.FindVisibleByID(userID, templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("查询模板失败: %v", err)
+	}
+	loadTemplateChecklists(template)
+	return template, nil
+}
+
+// findOwnTemplate 查询用户自己创建的模板，组织共享的模板只能查看和使用
+func (s *TemplateService) findOwnTemplate(userID, templateID uint) (*models.TodoTemplate, error) {
+	template, err := s.findTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.UserID != userID {
+		return nil, ErrTemplateForbidden
+	}
+	return template, nil
+}
+
+// selectTemplateItems 按ID挑选模板中的待办，ids为空时返回全部
+func selectTemplateItems(items []models.TodoTemplateItem, ids []uint) ([]models.TodoTemplateItem, error) {
+	if len(ids) == 0 {
+		return items, nil
+	}
+	selected := make([]models.TodoTemplateItem, 0, len(ids))
+	for _, item := range items {
+		if slices.Contains(ids, item.ID) {
+			selected = append(selected, item)
+		}
+	}
+	if len(selected) != len(slices.Compact(slices.Sorted(slices.Values(ids)))) {
+		return nil, fmt.Errorf("%w: item_ids中包含不属于该模板的待办", ErrInvalidTodoParams)
+	}
+	return selected, nil
+}
+
+// loadTemplateChecklists 还原模板中各待办的清单项
+func loadTemplateChecklists(template *models.TodoTemplate) {
+	for i := range template.Items {
+		template.Items[i].LoadChecklist()
+	}
+}
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// maxTimeEntryDuration 单条手动记录的最长时长
+const maxTimeEntryDuration = 24 * time.Hour
+
+// 用时汇总的分组方式
+const (
+	TimeGroupByTodo     = "todo"
+	TimeGroupByCategory = "category"
+	TimeGroupByDay      = "day"
+)
+
+// 用时记录相关的通用错误
+var (
+	ErrTimeEntryNotFound = errors.New("用时记录不存在或正在计时")
+	ErrNoRunningTimer    = errors.New("当前没有正在计时的待办")
+	ErrInvalidTimeEntry  = errors.New("用时记录参数错误")
+)
+
+type TimeTrackingService struct {
+	timeEntryRepo *repositories.TimeEntryRepository
+	todoRepo      *repositories.TodoRepository
+	userRepo      *repositories.UserRepository
+}
+
+func NewTimeTrackingService(
+	timeEntryRepo *repositories.TimeEntryRepository,
+	todoRepo *repositories.TodoRepository,
+	userRepo *repositories.UserRepository,
+) *TimeTrackingService {
+	return &TimeTrackingService{
+		timeEntryRepo: timeEntryRepo,
+		todoRepo:      todoRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// StartTimerRequest 开始计时请求
+type StartTimerRequest struct {
+	TodoID uint   `json:"todo_id" binding:"required" example:"1"`
+	Note   string `json:"note" binding:"max=255" example:"写接口文档"`
+}
+
+// StartTimerResult 开始计时结果，Stopped为被自动停止的上一个计时器
+type StartTimerResult struct {
+	Timer   *models.TimeEntry `json:"timer"`
+	Stopped *models.TimeEntry `json:"stopped,omitempty"`
+}
+
+// ManualTimeEntryRequest 手动补录用时请求，ended_at和duration_minutes二选一
+type ManualTimeEntryRequest struct {
+	TodoID          uint      `json:"todo_id" binding:"required" example:"1"`
+	StartedAt       time.Time `json:"started_at" binding:"required" example:"2025-01-01T09:00:00+08:00"`
+	EndedAt         time.Time `json:"ended_at" example:"2025-01-01T10:30:00+08:00"`
+	DurationMinutes int       `json:"duration_minutes" binding:"min=0" example:"90"`
+	Note            string    `json:"note" binding:"max=255" example:"会议讨论"`
+}
+
+// TimeSummaryGroup 用时汇总中的一组
+type TimeSummaryGroup struct {
+	Key     string `json:"key"`   // 待办ID、分类名称或日期（YYYY-MM-DD）
+	Label   string `json:"label"` // 展示名称
+	Seconds int64  `json:"seconds"`
+	Entries int    `json:"entries"` // 参与统计的记录数
+}
+
+// TimeSummary 一段时间内的用时汇总
+type TimeSummary struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	GroupBy      string             `json:"group_by"`
+	TotalSeconds int64              `json:"total_seconds"`
+	Groups       []TimeSummaryGroup `json:"groups"`
+}
+
+// TimeReportItem 单个待办的预计与实际用时对比
+type TimeReportItem struct {
+	TodoID           uint      `json:"todo_id"`
+	Title            string    `json:"title"`
+	Category         string    `json:"category"`
+	Status           string    `json:"status"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	EstimatedSeconds int64     `json:"estimated_seconds"` // 计划时长：结束时间减开始时间
+	ActualSeconds    int64     `json:"actual_seconds"`    // 全部用时记录之和，包括正在计时的部分
+	VarianceSeconds  int64     `json:"variance_seconds"`  // 实际减预计，正数表示超时
+	Ratio            float64   `json:"ratio"`             // 实际/预计，预计为0时为0
+}
+
+// TimeReport 预计与实际用时对比报告
+type TimeReport struct {
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	EstimatedSeconds int64            `json:"estimated_seconds"`
+	ActualSeconds    int64            `json:"actual_seconds"`
+	VarianceSeconds  int64            `json:"variance_seconds"`
+	Items            []TimeReportItem `json:"items"`
+}
+
+// StartTimer 为待办开始计时，已有计时器在运行时先将其停止
+func (s *TimeTrackingService) StartTimer(userID uint, req *StartTimerRequest) (*StartTimerResult, error) {
+	if _, err := s.getTrackableTodo(userID, req.TodoID, true); err != nil {
+		return nil, err
+	}
+
+	result := &StartTimerResult{}
+	err := s.timeEntryRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.timeEntryRepo.LockUserWithTx(tx, userID); err != nil {
+			return fmt.Errorf("锁定用户失败: %v", err)
+		}
+
+		now := time.Now()
+		running, err := s.timeEntryRepo.FindRunningWithTx(tx, userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("查询计时器失败: %v", err)
+		}
+		if running != nil {
+			if err := s.stopWithTx(tx, running, now); err != nil {
+				return err
+			}
+			result.Stopped = running
+		}
+
+		timer := &models.TimeEntry{
+			UserID:        userID,
+			TodoID:        req.TodoID,
+			Source:        models.TimeEntrySourceTimer,
+			StartedAt:     now,
+			EndedAt:       unsetTime,
+			Note:          strings.TrimSpace(req.Note),
+			RunningUserID: &userID,
+		}
+		if err := s.timeEntryRepo.CreateWithTx(tx, timer); err != nil {
+			return fmt.Errorf("开始计时失败: %v", err)
+		}
+		timer.Running = true
+		result.Timer = timer
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StopTimer 停止用户正在运行的计时器
+func (s *TimeTrackingService) StopTimer(userID uint) (*models.TimeEntry, error) {
+	var stopped *models.TimeEntry
+	err := s.timeEntryRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.timeEntryRepo.LockUserWithTx(tx, userID); err != nil {
+			return fmt.Errorf("锁定用户失败: %v", err)
+		}
+		running, err := s.timeEntryRepo.FindRunningWithTx(tx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoRunningTimer
+			}
+			return fmt.Errorf("查询计时器失败: %v", err)
+		}
+		if err := s.stopWithTx(tx, running, time.Now()); err != nil {
+			return err
+		}
+		stopped = running
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stopped, nil
+}
+
+// GetRunningTimer 获取用户正在运行的计时器，没有时返回nil
+func (s *TimeTrackingService) GetRunningTimer(userID uint) (*models.TimeEntry, error) {
+	running, err := s.timeEntryRepo.FindRunning(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询计时器失败: %v", err)
+	}
+	running.Running = true
+	running.DurationSeconds = int64(running.Elapsed(time.Now()) / time.Second)
+	return running, nil
+}
+
+// AddManualEntry 为待办手动补录一段用时
+func (s *TimeTrackingService) AddManualEntry(userID uint, req *ManualTimeEntryRequest) (*models.TimeEntry, error) {
+	endedAt := req.EndedAt
+	if endedAt.IsZero() {
+		if req.DurationMinutes == 0 {
+			return nil, fmt.Errorf("%w: 必须指定ended_at或duration_minutes", ErrInvalidTimeEntry)
+		}
+		endedAt = req.StartedAt.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	}
+	if !endedAt.After(req.StartedAt) {
+		return nil, fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidTimeEntry)
+	}
+	if endedAt.Sub(req.StartedAt) > maxTimeEntryDuration {
+		return nil, fmt.Errorf("%w: 单条记录不能超过24小时", ErrInvalidTimeEntry)
+	}
+	if endedAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: 不能补录未来的用时", ErrInvalidTimeEntry)
+	}
+
+	todo, err := s.getTrackableTodo(userID, req.TodoID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		UserID:          userID,
+		TodoID:          todo.ID,
+		Source:          models.TimeEntrySourceManual,
+		StartedAt:       req.StartedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(endedAt.Sub(req.StartedAt) / time.Second),
+		Note:            strings.TrimSpace(req.Note),
+	}
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		return nil, fmt.Errorf("保存用时记录失败: %v", err)
+	}
+	entry.TodoTitle = todo.Title
+	entry.Category = todo.Category
+	return entry, nil
+}
+
+// ListTodoEntries 获取待办的用时记录，正在计时的记录时长计算到当前
+func (s *TimeTrackingService) ListTodoEntries(userID, todoID uint) ([]models.TimeEntry, error) {
+	if _, err := s.getOwnedTodo(userID, todoID); err != nil {
+		return nil, err
+	}
+	entries, err := s.timeEntryRepo.FindByTodos(userID, []uint{todoID})
+	if err != nil {
+		return nil, fmt.Errorf("查询用时记录失败: %v", err)
+	}
+	now := time.Now()
+	for i := range entries {
+		if entries[i].IsRunning() {
+			entries[i].Running = true
+			entries[i].DurationSeconds = int64(entries[i].Elapsed(now) / time.Second)
+		}
+	}
+	return entries, nil
+}
+
+// DeleteEntry 删除已停止的用时记录，正在计时的记录需先停止
+func (s *TimeTrackingService) DeleteEntry(userID, entryID uint) error {
+	deleted, err := s.timeEntryRepo.Delete(userID, entryID)
+	if err != nil {
+		return fmt.Errorf("删除用时记录失败: %v", err)
+	}
+	if !deleted {
+		return ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+// GetTimeSummary 按待办、分类或日期汇总[from, to)内的用时
+// 跨越统计区间或跨天的记录按区间和用户时区的日期边界拆分
+func (s *TimeTrackingService) GetTimeSummary(userID uint, fromStr, toStr, groupBy string) (*TimeSummary, error) {
+	switch groupBy {
+	case "":
+		groupBy = TimeGroupByTodo
+	case TimeGroupByTodo, TimeGroupByCategory, TimeGroupByDay:
+	default:
+		return nil, fmt.Errorf("%w: 不支持的分组方式 %s", ErrInvalidTimeEntry, groupBy)
+	}
+
+	loc := userLocation(s.userRepo, userID)
+	from, to, err := parseDateRange(fromStr, toStr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTimeEntry, err)
+	}
+	entries, err := s.timeEntryRepo.FindOverlapping(userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询用时记录失败: %v", err)
+	}
+
+	summary := &TimeSummary{From: from, To: to, GroupBy: groupBy}
+	groups := make(map[string]*TimeSummaryGroup)
+	var order []string
+	group := func(key, label string) *TimeSummaryGroup {
+		g, ok := groups[key]
+		if !ok {
+			g = &TimeSummaryGroup{Key: key, Label: label}
+			groups[key] = g
+			order = append(order, key)
+		}
+		return g
+	}
+	if groupBy == TimeGroupByDay {
+		// 按日期分组时列出区间内的每一天，便于绘制图表
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			key := day.Format("2006-01-02")
+			group(key, key)
+		}
+	}
+
+	now := time.Now()
+	for i := range entries {
+		entry := &entries[i]
+		start, end := clipInterval(entry.StartedAt, entry.StartedAt.Add(entry.Elapsed(now)), from, to)
+		if !end.After(start) {
+			continue
+		}
+		seconds := int64(end.Sub(start) / time.Second)
+		summary.TotalSeconds += seconds
+
+		switch groupBy {
+		case TimeGroupByTodo:
+			label := entry.TodoTitle
+			if label == "" {
+				label = "已删除的待办"
+			}
+			g := group(strconv.FormatUint(uint64(entry.TodoID), 10), label)
+			g.Seconds += seconds
+			g.Entries++
+		case TimeGroupByCategory:
+			label := entry.Category
+			if label == "" {
+				label = "未分类"
+			}
+			g := group(entry.Category, label)
+			g.Seconds += seconds
+			g.Entries++
+		case TimeGroupByDay:
+			for cur := start; cur.Before(end); {
+				_, dayEnd := DayRange(cur.In(loc), loc)
+				segmentEnd := end
+				if dayEnd.Before(segmentEnd) {
+					segmentEnd = dayEnd
+				}
+				g := group(cur.In(loc).Format("2006-01-02"), "")
+				g.Seconds += int64(segmentEnd.Sub(cur) / time.Second)
+				g.Entries++
+				cur = segmentEnd
+			}
+		}
+	}
+
+	summary.Groups = make([]TimeSummaryGroup, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		if g.Label == "" {
+			g.Label = g.Key
+		}
+		summary.Groups = append(summary.Groups, *g)
+	}
+	if groupBy != TimeGroupByDay {
+		sort.SliceStable(summary.Groups, func(i, j int) bool {
+			return summary.Groups[i].Seconds > summary.Groups[j].Seconds
+		})
+	}
+	return summary, nil
+}
+
+// GetTimeReport 对比[from, to)内开始的待办的预计与实际用时，已取消的待办不计入
+func (s *TimeTrackingService) GetTimeReport(userID uint, fromStr, toStr string) (*TimeReport, error) {
+	loc := userLocation(s.userRepo, userID)
+	from, to, err := parseDateRange(fromStr, toStr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTimeEntry, err)
+	}
+
+	todos, err := s.todoRepo.Query(userID).
+		InstancesOnly().
+		Statuses(TodoStatusPending, TodoStatusInProgress, TodoStatusCompleted).
+		TimeRange("start_time", from, to).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+	ids := make([]uint, len(todos))
+	for i := range todos {
+		ids[i] = todos[i].ID
+	}
+	entries, err := s.timeEntryRepo.FindByTodos(userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询用时记录失败: %v", err)
+	}
+
+	now := time.Now()
+	actual := make(map[uint]int64, len(todos))
+	for i := range entries {
+		actual[entries[i].TodoID] += int64(entries[i].Elapsed(now) / time.Second)
+	}
+
+	report := &TimeReport{From: from, To: to, Items: make([]TimeReportItem, 0, len(todos))}
+	for i := range todos {
+		todo := &todos[i]
+		todo.Localize()
+		item := TimeReportItem{
+			TodoID:        todo.ID,
+			Title:         todo.Title,
+			Category:      todo.Category,
+			Status:        todo.Status,
+			StartTime:     todo.StartTime,
+			EndTime:       todo.EndTime,
+			ActualSeconds: actual[todo.ID],
+		}
+		if todo.EndTime.After(todo.StartTime) {
+			item.EstimatedSeconds = int64(todo.EndTime.Sub(todo.StartTime) / time.Second)
+		}
+		item.VarianceSeconds = item.ActualSeconds - item.EstimatedSeconds
+		if item.EstimatedSeconds > 0 {
+			item.Ratio = float64(item.ActualSeconds) / float64(item.EstimatedSeconds)
+		}
+		report.EstimatedSeconds += item.EstimatedSeconds
+		report.ActualSeconds += item.ActualSeconds
+		report.Items = append(report.Items, item)
+	}
+	report.VarianceSeconds = report.ActualSeconds - report.EstimatedSeconds
+	return report, nil
+}
+
+// stopWithTx 停止计时器并回填结束时间和时长
+func (s *TimeTrackingService) stopWithTx(tx *gorm.DB, entry *models.TimeEntry, now time.Time) error {
+	duration := int64(entry.Elapsed(now) / time.Second)
+	stopped, err := s.timeEntryRepo.StopWithTx(tx, entry.ID, now, duration)
+	if err != nil {
+		return fmt.Errorf("停止计时失败: %v", err)
+	}
+	if !stopped {
+		return ErrNoRunningTimer
+	}
+	entry.EndedAt = now
+	entry.DurationSeconds = duration
+	entry.RunningUserID = nil
+	entry.Running = false
+	return nil
+}
+
+// getOwnedTodo 获取属于用户的待办
+func (s *TimeTrackingService) getOwnedTodo(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.FindByID(todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+	if todo.CreatorUserID != userID {
+		return nil, ErrTodoForbidden
+	}
+	return todo, nil
+}
+
+// getTrackableTodo 获取可以记录用时的待办：重复待办的抽象待办不能记录，
+// 开始计时还要求待办尚未完成或取消
+func (s *TimeTrackingService) getTrackableTodo(userID, todoID uint, timer bool) (*models.Todo, error) {
+	todo, err := s.getOwnedTodo(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.HasChildren {
+		return nil, fmt.Errorf("%w: 请为重复待办的具体实例记录用时", ErrInvalidTimeEntry)
+	}
+	if timer && (todo.Status == TodoStatusCompleted || todo.Status == TodoStatusCancelled) {
+		return nil, fmt.Errorf("%w: 待办已结束，无法开始计时", ErrInvalidTimeEntry)
+	}
+	return todo, nil
+}
+
+// clipInterval 将[start, end)裁剪到[from, to)内
+func clipInterval(start, end, from, to time.Time) (time.Time, time.Time) {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return start, end
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/cache"
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+)
+
+// ErrInvalidTimezone 时区名称不是可识别的IANA时区
+var ErrInvalidTimezone = errors.New("无法识别的时区")
+
+// userTimezone 获取用户设置的时区名称，优先读取用户缓存，查询失败或未设置时返回空字符串
+func userTimezone(userRepo *repositories.UserRepository, userID uint) string {
+	user, err := cache.GetUser(userID)
+	if err != nil || user == nil {
+		if user, err = userRepo.FindByID(userID); err != nil {
+			return ""
+		}
+	}
+	return user.Timezone
+}
+
+// userLocation 获取用户设置的时区，未设置时使用服务器时区
+func userLocation(userRepo *repositories.UserRepository, userID uint) *time.Location {
+	return models.LoadLocation(userTimezone(userRepo, userID))
+}
+
+// userLocation 获取用户设置的时区
+func (s *TodoService) userLocation(userID uint) *time.Location {
+	return userLocation(s.userRepo, userID)
+}
+
+// resolveTodoTimezone 校验创建请求中的时区，未指定时使用用户设置的时区
+func (s *TodoService) resolveTodoTimezone(userID uint, req *CreateTodoRequest) error {
+	req.Timezone = strings.TrimSpace(req.Timezone)
+	if req.Timezone == "" {
+		req.Timezone = userTimezone(s.userRepo, userID)
+		return nil
+	}
+	if !models.ValidTimezone(req.Timezone) {
+		return fmt.Errorf("%w %s", ErrInvalidTimezone, req.Timezone)
+	}
+	return nil
+}
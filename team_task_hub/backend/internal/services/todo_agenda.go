@@ -0,0 +1,300 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 日程分组方式
+const (
+	AgendaGroupDay   = "day"
+	AgendaGroupWeek  = "week"
+	AgendaGroupMonth = "month"
+	AgendaGroupNone  = "none"
+)
+
+// 日程条目类型
+const (
+	AgendaItemTodo     = "todo"
+	AgendaItemActivity = "activity"
+)
+
+// AgendaRequest 日程查询参数
+type AgendaRequest struct {
+	From             string `form:"from" example:"2025-01-01"`                                            // 用户时区下的起始日期（含），默认本周一
+	To               string `form:"to" example:"2025-01-31"`                                              // 用户时区下的结束日期（含）
+	GroupBy          string `form:"group_by" binding:"omitempty,oneof=day week month none" example:"day"` // 默认day
+	Types            string `form:"types" example:"todo,activity"`                                        // 逗号分隔，默认全部
+	IncludeCompleted bool   `form:"include_completed" example:"false"`                                    // 是否包含已完成的待办和活动
+}
+
+// AgendaItem 日程中的一项，待办和活动使用相同的结构
+type AgendaItem struct {
+	Type        string     `json:"type" example:"todo"` // todo 或 activity
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Status      string     `json:"status" example:"pending"` // 待办状态，活动为用户的参与状态
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	IsOverdue   bool       `json:"is_overdue"`
+
+	// 仅待办
+	Urgency  string   `json:"urgency,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	ParentID uint     `json:"parent_id,omitempty"` // 所属重复待办
+
+	// 活动所属组织，或组织下发待办的来源组织
+	OrganizationID   uint   `json:"organization_id,omitempty"`
+	OrganizationName string `json:"organization_name,omitempty"`
+}
+
+// AgendaGroup 一天、一周或一个月内的日程，跨越多个分组的条目在每个分组中都会出现
+type AgendaGroup struct {
+	Key   string       `json:"key" example:"2025-01-06"` // 日：2025-01-06，周：2025-W02，月：2025-01
+	Start time.Time    `json:"start"`
+	End   time.Time    `json:"end"` // 不含
+	Items []AgendaItem `json:"items"`
+}
+
+// Agenda 日程查询结果，group_by=none时只返回items
+type Agenda struct {
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Timezone string        `json:"timezone"`
+	GroupBy  string        `json:"group_by"`
+	Total    int           `json:"total"`
+	Items    []AgendaItem  `json:"items,omitempty"`
+	Groups   []AgendaGroup `json:"groups,omitempty"`
+}
+
+// GetAgenda 按时间顺序合并用户的个人待办实例和参与的组织活动，并按日、周或月分组
+func (s *TodoService) GetAgenda(userID uint, req *AgendaRequest) (*Agenda, error) {
+	loc := s.userLocation(userID)
+	from, to, err := parseDateRange(req.From, req.To, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = AgendaGroupDay
+	}
+
+	types := map[string]bool{AgendaItemTodo: true, AgendaItemActivity: true}
+	if list := splitCommaList(req.Types); len(list) > 0 {
+		types = make(map[string]bool, len(list))
+		for _, t := range list {
+			if t != AgendaItemTodo && t != AgendaItemActivity {
+				return nil, fmt.Errorf("%w: 不支持的日程类型 %s", ErrInvalidTodoParams, t)
+			}
+			types[t] = true
+		}
+	}
+
+	now := time.Now()
+	items := []AgendaItem{}
+	if types[AgendaItemTodo] {
+		todoItems, err := s.agendaTodos(userID, from, to, req.IncludeCompleted, loc)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, todoItems...)
+	}
+	if types[AgendaItemActivity] {
+		activityItems, err := s.agendaActivities(userID, from, to, req.IncludeCompleted, loc, now)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, activityItems...)
+	}
+	sortAgendaItems(items)
+
+	agenda := &Agenda{
+		From:     from,
+		To:       to,
+		Timezone: loc.String(),
+		GroupBy:  groupBy,
+		Total:    len(items),
+	}
+	if groupBy == AgendaGroupNone {
+		agenda.Items = items
+		return agenda, nil
+	}
+	agenda.Groups = groupAgendaItems(items, from, to, groupBy)
+	return agenda, nil
+}
+
+// agendaTodos 查询与区间有交集的待办实例，不包含抽象的重复待办和已取消的待办
+func (s *TodoService) agendaTodos(userID uint, from, to time.Time, includeCompleted bool, loc *time.Location) ([]AgendaItem, error) {
+	statuses := []string{TodoStatusPending, TodoStatusInProgress}
+	if includeCompleted {
+		statuses = append(statuses, TodoStatusCompleted)
+	}
+	todos, err := s.todoRepo.Query(userID).
+		InstancesOnly().
+		Statuses(statuses...).
+		Overlapping(from, to).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询日程待办失败: %v", err)
+	}
+	if err := s.attachTags(todos); err != nil {
+		return nil, err
+	}
+	if err := s.attachOrganizations(todos); err != nil {
+		return nil, err
+	}
+
+	items := make([]AgendaItem, 0, len(todos))
+	for _, todo := range todos {
+		item := AgendaItem{
+			Type:             AgendaItemTodo,
+			ID:               todo.ID,
+			Title:            todo.Title,
+			Description:      todo.Description,
+			StartTime:        todo.StartTime.In(loc),
+			EndTime:          todo.EndTime.In(loc),
+			Status:           todo.Status,
+			IsOverdue:        todo.IsOverdue,
+			Urgency:          todo.Urgency,
+			Category:         todo.Category,
+			ParentID:         todo.ParentID,
+			OrganizationID:   todo.CreatorOrganID,
+			OrganizationName: todo.OrganizationName,
+		}
+		if todo.Status == TodoStatusCompleted && todo.CompletedAt.Year() > 1900 {
+			completedAt := todo.CompletedAt.In(loc)
+			item.CompletedAt = &completedAt
+		}
+		for _, tag := range todo.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// agendaActivities 查询用户参与且与区间有交集的活动，不包含已取消的活动和参与记录
+func (s *TodoService) agendaActivities(userID uint, from, to time.Time, includeCompleted bool, loc *time.Location, now time.Time) ([]AgendaItem, error) {
+	statuses := []string{"pending"}
+	if includeCompleted {
+		statuses = append(statuses, "completed")
+	}
+	activities, participations, err := s.activityRepo.FindUserParticipationsInRange(userID, from, to, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]AgendaItem, 0, len(activities))
+	for _, activity := range activities {
+		participation := participations[activity.ID]
+		item := AgendaItem{
+			Type:             AgendaItemActivity,
+			ID:               activity.ID,
+			Title:            activity.Title,
+			Description:      activity.Description,
+			StartTime:        activity.StartTime.In(loc),
+			EndTime:          activity.EndTime.In(loc),
+			Status:           participation.Status,
+			IsOverdue:        participation.Status == "pending" && activity.EndTime.Before(now),
+			OrganizationID:   activity.OrganizationID,
+			OrganizationName: activity.Organization.Name,
+		}
+		if participation.Status == "completed" && participation.CompletedAt.Year() > 1900 {
+			completedAt := participation.CompletedAt.In(loc)
+			item.CompletedAt = &completedAt
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// sortAgendaItems 按开始时间、结束时间排序，时间相同时活动在前
+func sortAgendaItems(items []AgendaItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		if !a.EndTime.Equal(b.EndTime) {
+			return a.EndTime.Before(b.EndTime)
+		}
+		if a.Type != b.Type {
+			return a.Type == AgendaItemActivity
+		}
+		return a.ID < b.ID
+	})
+}
+
+// groupAgendaItems 将[from, to)按日、周（周一开始）或月切分，返回包括空分组在内的全部分组，首尾分组截取到区间内
+func groupAgendaItems(items []AgendaItem, from, to time.Time, groupBy string) []AgendaGroup {
+	var groups []AgendaGroup
+	for start := agendaPeriodStart(from, groupBy); start.Before(to); {
+		key := agendaPeriodKey(start, groupBy)
+		end := agendaPeriodEnd(start, groupBy)
+		// 首尾分组截取到查询区间内
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		group := AgendaGroup{
+			Key:   key,
+			Start: start,
+			End:   end,
+			Items: []AgendaItem{},
+		}
+		for _, item := range items {
+			// 零时长的条目按开始时间归入分组
+			if item.StartTime.Before(end) && (item.EndTime.After(start) || !item.StartTime.Before(start) && !item.EndTime.After(item.StartTime)) {
+				group.Items = append(group.Items, item)
+			}
+		}
+		groups = append(groups, group)
+		start = end
+	}
+	return groups
+}
+
+// agendaPeriodStart 返回时间所在分组的起点
+func agendaPeriodStart(t time.Time, groupBy string) time.Time {
+	day, _ := DayRange(t, t.Location())
+	switch groupBy {
+	case AgendaGroupWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为0
+		return day.AddDate(0, 0, -offset)
+	case AgendaGroupMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+// agendaPeriodEnd 返回分组的终点（不含）
+func agendaPeriodEnd(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case AgendaGroupWeek:
+		return start.AddDate(0, 0, 7)
+	case AgendaGroupMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// agendaPeriodKey 返回分组的标识
+func agendaPeriodKey(start time.Time, groupBy string) string {
+	switch groupBy {
+	case AgendaGroupWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case AgendaGroupMonth:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxScheduleHorizon 自动排程最远安排到多久之后
+const maxScheduleHorizon = 60 * 24 * time.Hour
+
+// CreateFlexibleTodoRequest 创建弹性待办请求：只指定时长和截止时间，由自动排程安排具体时间
+type CreateFlexibleTodoRequest struct {
+	Title           string    `json:"title" binding:"required,max=200" example:"写周报"`
+	Description     string    `json:"description" binding:"required" example:"汇总本周进展"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=5" example:"90"`
+	Deadline        time.Time `json:"deadline" binding:"required" example:"2024-01-19T18:00:00+08:00"`
+	Urgency         string    `json:"urgency" binding:"omitempty,oneof=low medium high" example:"medium"` // 默认medium，越紧急越优先安排
+	Category        string    `json:"category" binding:"omitempty,max=50" example:"work"`
+	Tags            []string  `json:"tags,omitempty" example:"周报"`
+	Checklist       []string  `json:"checklist,omitempty" binding:"omitempty,dive,max=200"`
+	AutoComplete    bool      `json:"auto_complete" example:"false"`
+}
+
+// SchedulePreferenceRequest 更新排程偏好请求
+type SchedulePreferenceRequest struct {
+	WorkStartMinute int   `json:"work_start_minute" binding:"min=0,max=1439" example:"540"`        // 每天开始工作的时刻，0点起的分钟数
+	WorkEndMinute   int   `json:"work_end_minute" binding:"min=1,max=1440" example:"1080"`         // 每天结束工作的时刻，0点起的分钟数
+	WorkDays        []int `json:"work_days" binding:"required,min=1,dive,min=0,max=6" example:"1"` // 工作日，0表示周日
+}
+
+// ScheduledTodo 自动排程中一个弹性待办的安排结果
+type ScheduledTodo struct {
+	TodoID    uint      `json:"todo_id"`
+	Title     string    `json:"title"`
+	Urgency   string    `json:"urgency"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// ScheduleResult 一次自动排程的结果
+type ScheduleResult struct {
+	Kept     int             `json:"kept"`     // 原有安排仍然有效、保持不动的数量
+	Placed   []ScheduledTodo `json:"placed"`   // 本次新安排或移动的待办
+	Unplaced []ScheduledTodo `json:"unplaced"` // 截止前没有足够空闲时段的待办
+}
+
+// GetSchedulePreference 查询用户的排程偏好，未设置时返回默认工作时间
+func (s *TodoService) GetSchedulePreference(userID uint) (*models.SchedulePreference, error) {
+	pref, err := s.preferenceRepo.FindByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultSchedulePreference(userID), nil
+		}
+		return nil, fmt.Errorf("查询排程偏好失败: %v", err)
+	}
+	return pref, nil
+}
+
+// UpdateSchedulePreference 更新用户的排程偏好，并按新的工作时间重新安排弹性待办
+func (s *TodoService) UpdateSchedulePreference(userID uint, req *SchedulePreferenceRequest) (*models.SchedulePreference, error) {
+	if req.WorkStartMinute >= req.WorkEndMinute {
+		return nil, fmt.Errorf("%w: 开始工作时间必须早于结束工作时间", ErrInvalidTodoParams)
+	}
+	days := slices.Clone(req.WorkDays)
+	slices.Sort(days)
+	days = slices.Compact(days)
+	parts := make([]string, len(days))
+	for i, day := range days {
+		parts[i] = strconv.Itoa(day)
+	}
+
+	pref := &models.SchedulePreference{
+		UserID:          userID,
+		WorkStartMinute: req.WorkStartMinute,
+		WorkEndMinute:   req.WorkEndMinute,
+		WorkDays:        strings.Join(parts, ","),
+	}
+	if err := s.preferenceRepo.Save(pref); err != nil {
+		return nil, fmt.Errorf("保存排程偏好失败: %v", err)
+	}
+	s.replanAfterChange(userID)
+	return pref, nil
+}
+
+// CreateFlexibleTodo 创建弹性待办并立即排程：在截止时间前最早的空闲工作时段中安排
+// 没有足够的空闲时段时仍然创建，标记为未安排并暂按截止时间倒排
+func (s *TodoService) CreateFlexibleTodo(userID uint, req *CreateFlexibleTodoRequest) (*models.Todo, error) {
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	if !req.Deadline.After(time.Now().Add(duration)) {
+		return nil, fmt.Errorf("%w: 截止时间之前已经来不及完成该待办", ErrInvalidTodoParams)
+	}
+	pref, err := s.GetSchedulePreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.DurationMinutes > pref.WorkMinutesPerDay() {
+		return nil, fmt.Errorf("%w: 时长超过每天的工作时间（%d分钟），请拆分为多个待办", ErrInvalidTodoParams, pref.WorkMinutesPerDay())
+	}
+
+	createReq := &CreateTodoRequest{
+		Title:        req.Title,
+		Description:  req.Description,
+		StartTime:    req.Deadline.Add(-duration),
+		EndTime:      req.Deadline,
+		Urgency:      req.Urgency,
+		Category:     req.Category,
+		Checklist:    req.Checklist,
+		AutoComplete: req.AutoComplete,
+	}
+	if createReq.Urgency == "" {
+		createReq.Urgency = "medium"
+	}
+	if err := s.resolveTodoTimezone(userID, createReq); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+	category, err := s.resolveCategory(userID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs, err := s.resolveTagIDs(userID, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	todo := newSingleTodo(userID, createReq, category)
+	todo.Flexible = true
+	todo.DurationMinutes = req.DurationMinutes
+	todo.Deadline = req.Deadline
+	todo.Unplaced = true
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.todoRepo.CreateWithTx(tx, todo); err != nil {
+			return fmt.Errorf("创建待办失败: %v", err)
+		}
+		if err := s.tagRepo.AddTodoTagsWithTx(tx, []uint{todo.ID}, tagIDs); err != nil {
+			return fmt.Errorf("设置待办标签失败: %v", err)
+		}
+		if err := s.checklistRepo.BatchCreateWithTx(tx, newChecklistItems([]uint{todo.ID}, req.Checklist)); err != nil {
+			return fmt.Errorf("创建待办清单失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateTodoStats(userID)
+
+	if _, err := s.planFlexibleTodos(userID, userID, false); err != nil {
+		return nil, err
+	}
+	created, err := s.todoRepo.FindByID(todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.todoWithDetails(created)
+}
+
+// ReplanFlexibleTodos 重新安排用户的弹性待办，full为true时忽略现有安排从头排程
+func (s *TodoService) ReplanFlexibleTodos(userID uint, full bool) (*ScheduleResult, error) {
+	return s.planFlexibleTodos(userID, userID, full)
+}
+
+// ReplanAllFlexibleTodos 为所有存在弹性待办的用户执行增量排程，返回移动的待办数量
+func (s *TodoService) ReplanAllFlexibleTodos() (int, error) {
+	userIDs, err := s.todoRepo.FindUserIDsWithFlexibleTodos()
+	if err != nil {
+		return 0, fmt.Errorf("查询弹性待办用户失败: %v", err)
+	}
+	moved := 0
+	for _, userID := range userIDs {
+		result, err := s.planFlexibleTodos(userID, 0, false)
+		if err != nil {
+			log.Printf("用户 %d 自动排程失败: %v", userID, err)
+			continue
+		}
+		moved += len(result.Placed)
+	}
+	return moved, nil
+}
+
+// replanAfterChange 日程变化后增量重排用户的弹性待办，失败只记录日志，不影响触发它的操作
+// 没有弹性待办的用户不开启排程事务
+func (s *TodoService) replanAfterChange(userID uint) {
+	has, err := s.todoRepo.HasFlexibleTodos(userID)
+	if err != nil {
+		log.Printf("用户 %d 查询弹性待办失败: %v", userID, err)
+		return
+	}
+	if !has {
+		return
+	}
+	if _, err := s.planFlexibleTodos(userID, userID, false); err != nil {
+		log.Printf("用户 %d 日程变化后自动排程失败: %v", userID, err)
+	}
+}
+
+// affectsSchedule 判断待办的更新是否会改变用户的空闲时段
+func affectsSchedule(updates map[string]any) bool {
+	for _, field := range []string{"start_time", "end_time", "status"} {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// planFlexibleTodos 在一个事务中安排用户待开始的弹性待办
+//
+// 忙碌时间为未完成的固定待办（全天和跨多天的除外）和已参加的活动；弹性待办按紧急程度从高到低、截止时间从早到晚
+// 依次放入截止前最早的空闲工作时段。增量模式下，仍然有效（未过期、在工作时间内、不与忙碌
+// 时间重叠、不晚于截止时间）的安排保持不动，只移动失效的和尚未安排的待办，避免日程频繁变动
+func (s *TodoService) planFlexibleTodos(userID, actorID uint, full bool) (*ScheduleResult, error) {
+	pref, err := s.GetSchedulePreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := s.userLocation(userID)
+	now := time.Now()
+	result := &ScheduleResult{Placed: []ScheduledTodo{}, Unplaced: []ScheduledTodo{}}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		todos, err := s.todoRepo.FindFlexibleTodosForUpdateWithTx(tx, userID)
+		if err != nil {
+			return fmt.Errorf("查询弹性待办失败: %v", err)
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+		sortByPriority(todos)
+
+		horizon := now.Add(24 * time.Hour)
+		for _, todo := range todos {
+			if todo.Deadline.After(horizon) {
+				horizon = todo.Deadline
+			}
+		}
+		if limit := now.Add(maxScheduleHorizon); horizon.After(limit) {
+			horizon = limit
+		}
+
+		busy, err := s.busySlots(userID, todos, now, horizon)
+		if err != nil {
+			return err
+		}
+		free := subtractSlots(workingSlots(pref, loc, now, horizon), busy)
+
+		// 先保留仍然有效的安排，再为其余待办找时段
+		var pending []*models.Todo
+		for i := range todos {
+			todo := &todos[i]
+			current := timeSlot{start: todo.StartTime, end: todo.EndTime}
+			inProgressSlot := todo.StartTime.Before(now) && todo.EndTime.After(now)
+			if !full && !todo.Unplaced && !todo.EndTime.After(todo.Deadline) &&
+				(inProgressSlot || slotWithin(free, current)) {
+				free = subtractSlots(free, []timeSlot{current})
+				result.Kept++
+				continue
+			}
+			pending = append(pending, todo)
+		}
+
+		for _, todo := range pending {
+			slot, ok := earliestFit(free, time.Duration(todo.DurationMinutes)*time.Minute, todo.Deadline)
+			if ok {
+				free = subtractSlots(free, []timeSlot{slot})
+			} else {
+				// 没有空闲时段时按截止时间倒排；已过截止时间的保持原时间，由逾期标记提示
+				slot = timeSlot{start: todo.StartTime, end: todo.EndTime}
+				if todo.Deadline.After(now) {
+					slot = timeSlot{start: todo.Deadline.Add(-time.Duration(todo.DurationMinutes) * time.Minute), end: todo.Deadline}
+				}
+			}
+
+			updates := map[string]any{}
+			if !slot.start.Equal(todo.StartTime) || !slot.end.Equal(todo.EndTime) {
+				updates["start_time"] = slot.start
+				updates["end_time"] = slot.end
+			}
+			if todo.Unplaced == ok {
+				updates["unplaced"] = !ok
+			}
+			if len(updates) == 0 && ok {
+				result.Kept++
+				continue
+			}
+
+			if len(updates) > 0 {
+				if err := s.updateTodoWithTx(tx, todo.ID, updates); err != nil {
+					return fmt.Errorf("更新弹性待办时间失败: %v", err)
+				}
+				recorder := &todoChangeRecorder{}
+				recorder.record(todo, updates)
+				if err := s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+					OwnerID: userID,
+					ActorID: actorID,
+					TodoID:  todo.ID,
+					Action:  models.TodoActionAutoSchedule,
+				}, recorder); err != nil {
+					return err
+				}
+			}
+
+			scheduled := ScheduledTodo{
+				TodoID:    todo.ID,
+				Title:     todo.Title,
+				Urgency:   todo.Urgency,
+				StartTime: slot.start.In(loc),
+				EndTime:   slot.end.In(loc),
+				Deadline:  todo.Deadline.In(loc),
+			}
+			if ok {
+				result.Placed = append(result.Placed, scheduled)
+			} else {
+				result.Unplaced = append(result.Unplaced, scheduled)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// busySlots 查询[from, to)内的忙碌时间：未完成的固定待办实例和已参加的活动，不含待排程的弹性待办
+// 全天和跨多天的固定待办不占用具体时段，不计入忙碌时间
+func (s *TodoService) busySlots(userID uint, flexible []models.Todo, from, to time.Time) ([]timeSlot, error) {
+	flexibleIDs := make(map[uint]bool, len(flexible))
+	for _, todo := range flexible {
+		flexibleIDs[todo.ID] = true
+	}
+
+	todos, err := s.todoRepo.Query(userID).
+		InstancesOnly().
+		Statuses(TodoStatusPending, TodoStatusInProgress).
+		Overlapping(from, to).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询已有待办失败: %v", err)
+	}
+	var busy []timeSlot
+	for _, todo := range todos {
+		if !flexibleIDs[todo.ID] && blocksSchedule(todo.StartTime, todo.EndTime, todo.Location()) {
+			busy = append(busy, timeSlot{start: todo.StartTime, end: todo.EndTime})
+		}
+	}
+
+	activities, err := s.activityRepo.FindUserConflictingActivities(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities {
+		busy = append(busy, timeSlot{start: activity.StartTime, end: activity.EndTime})
+	}
+	return busy, nil
+}
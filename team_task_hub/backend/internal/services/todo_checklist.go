@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrChecklistItemNotFound 清单项不存在
+var ErrChecklistItemNotFound = errors.New("清单项不存在")
+
+// ChecklistItemRequest 新建清单项请求
+type ChecklistItemRequest struct {
+	Title string `json:"title" binding:"required,max=200" example:"准备会议材料"`
+}
+
+// UpdateChecklistItemRequest 更新清单项请求，只更新非空字段
+type UpdateChecklistItemRequest struct {
+	Title *string `json:"title,omitempty" binding:"omitempty,max=200" example:"准备会议材料"`
+	Done  *bool   `json:"done,omitempty" example:"true"`
+}
+
+// ReorderChecklistRequest 清单项排序请求
+type ReorderChecklistRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required,min=1" example:"3,1,2"`
+}
+
+// ChecklistResult 清单操作结果，包含最新的清单、进度以及待办是否被自动完成
+type ChecklistResult struct {
+	Items         []models.ChecklistItem   `json:"items"`
+	Progress      models.ChecklistProgress `json:"progress"`
+	AutoCompleted bool                     `json:"auto_completed"`
+}
+
+// GetChecklist 获取待办的清单
+func (s *TodoService) GetChecklist(userID, todoID uint) (*ChecklistResult, error) {
+	if _, err := s.GetTodoByID(userID, todoID); err != nil {
+		return nil, err
+	}
+	return s.checklistResult(todoID)
+}
+
+// AddChecklistItem 为待办添加清单项
+func (s *TodoService) AddChecklistItem(userID, todoID uint, req *ChecklistItemRequest) (*ChecklistResult, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.HasChildren {
+		return nil, fmt.Errorf("%w: 重复待办的抽象待办不能添加清单，请操作具体实例", ErrInvalidTodoParams)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: 清单项标题不能为空", ErrInvalidTodoParams)
+	}
+
+	item := &models.ChecklistItem{TodoID: todoID, Title: title}
+	if err := s.checklistRepo.Create(item); err != nil {
+		return nil, fmt.Errorf("添加清单项失败: %v", err)
+	}
+	return s.checklistResult(todoID)
+}
+
+// UpdateChecklistItem 修改清单项标题或勾选状态
+// 全部勾选且待办开启了自动完成时，待办会按状态机变为已完成
+func (s *TodoService) UpdateChecklistItem(userID, todoID, itemID uint, req *UpdateChecklistItemRequest) (*ChecklistResult, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.findChecklistItem(todoID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: 清单项标题不能为空", ErrInvalidTodoParams)
+		}
+		updates["title"] = title
+	}
+	if req.Done != nil && *req.Done != item.Done {
+		updates["done"] = *req.Done
+		if *req.Done {
+			updates["done_at"] = time.Now()
+		} else {
+			updates["done_at"] = unsetTime
+		}
+	}
+	if len(updates) > 0 {
+		if err := s.checklistRepo.Update(item, updates); err != nil {
+			return nil, fmt.Errorf("更新清单项失败: %v", err)
+		}
+	}
+
+	result, err := s.checklistResult(todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Done != nil && *req.Done {
+		if err := s.autoCompleteByChecklist(userID, todo, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// DeleteChecklistItem 删除清单项
+// 删除唯一未勾选的清单项后其余全部已勾选时，与勾选一样触发自动完成
+func (s *TodoService) DeleteChecklistItem(userID, todoID, itemID uint) (*ChecklistResult, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.findChecklistItem(todoID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checklistRepo.Delete(item); err != nil {
+		return nil, fmt.Errorf("删除清单项失败: %v", err)
+	}
+
+	result, err := s.checklistResult(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.autoCompleteByChecklist(userID, todo, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReorderChecklist 按给定顺序重排清单项，必须包含待办的全部清单项
+func (s *TodoService) ReorderChecklist(userID, todoID uint, req *ReorderChecklistRequest) (*ChecklistResult, error) {
+	if _, err := s.GetTodoByID(userID, todoID); err != nil {
+		return nil, err
+	}
+	items, err := s.checklistRepo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询清单失败: %v", err)
+	}
+
+	existing := make(map[uint]bool, len(items))
+	for _, item := range items {
+		existing[item.ID] = true
+	}
+	seen := make(map[uint]bool, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		if !existing[id] || seen[id] {
+			return nil, fmt.Errorf("%w: 清单项ID %d 无效或重复", ErrInvalidTodoParams, id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(existing) {
+		return nil, fmt.Errorf("%w: 排序必须包含全部清单项", ErrInvalidTodoParams)
+	}
+
+	if err := s.checklistRepo.Reorder(todoID, req.ItemIDs); err != nil {
+		return nil, fmt.Errorf("清单排序失败: %v", err)
+	}
+	return s.checklistResult(todoID)
+}
+
+// autoCompleteByChecklist 清单全部勾选且待办开启了自动完成时，按状态机将待办变为已完成
+func (s *TodoService) autoCompleteByChecklist(userID uint, todo *models.Todo, result *ChecklistResult) error {
+	if !todo.AutoComplete || result.Progress.Total == 0 || result.Progress.Done != result.Progress.Total ||
+		!CanTransition(todo.Status, TodoStatusCompleted) {
+		return nil
+	}
+	// 仍被前置待办阻塞时不自动完成，清单的修改照常生效
+	_, err := s.TransitionTodo(userID, todo.ID, TodoStatusCompleted)
+	switch {
+	case err == nil:
+		result.AutoCompleted = true
+	case !errors.Is(err, ErrTodoBlocked):
+		return fmt.Errorf("自动完成待办失败: %w", err)
+	}
+	return nil
+}
+
+// findChecklistItem 查找待办下的清单项
+func (s *TodoService) findChecklistItem(todoID, itemID uint) (*models.ChecklistItem, error) {
+	item, err := s.checklistRepo.FindByID(todoID, itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChecklistItemNotFound
+		}
+		return nil, fmt.Errorf("查询清单项失败: %v", err)
+	}
+	return item, nil
+}
+
+// checklistResult 查询待办的最新清单及进度
+func (s *TodoService) checklistResult(todoID uint) (*ChecklistResult, error) {
+	items, err := s.checklistRepo.FindByTodo(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询清单失败: %v", err)
+	}
+
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	return &ChecklistResult{
+		Items:    items,
+		Progress: models.NewChecklistProgress(len(items), done),
+	}, nil
+}
+
+// newChecklistItems 为每个待办按顺序生成相同的清单项，空标题会被忽略
+func newChecklistItems(todoIDs []uint, titles []string) []models.ChecklistItem {
+	var items []models.ChecklistItem
+	for _, todoID := range todoIDs {
+		position := 0
+		for _, title := range titles {
+			title = strings.TrimSpace(title)
+			if title == "" {
+				continue
+			}
+			items = append(items, models.ChecklistItem{TodoID: todoID, Title: title, Position: position})
+			position++
+		}
+	}
+	return items
+}
+
+// attachChecklistProgress 为待办列表填充清单进度，没有清单项的待办不填充
+func (s *TodoService) attachChecklistProgress(todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	progressByTodo, err := s.checklistRepo.CountByTodoIDs(ids)
+	if err != nil {
+		return fmt.Errorf("查询清单进度失败: %v", err)
+	}
+	for i := range todos {
+		if progress, ok := progressByTodo[todos[i].ID]; ok {
+			todos[i].Checklist = &progress
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"team_task_hub/backend/internal/models"
+)
+
+// 依赖相关的错误
+var (
+	ErrTodoBlocked      = errors.New("待办被尚未完成的前置待办阻塞")
+	ErrDependencyCycle  = errors.New("依赖关系会形成循环")
+	ErrDependencyExists = errors.New("依赖关系已存在")
+)
+
+// AddDependencyRequest 添加前置待办请求
+type AddDependencyRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required" example:"12"`
+}
+
+// TodoDependencies 待办的依赖关系
+type TodoDependencies struct {
+	BlockedBy []models.Todo `json:"blocked_by"` // 前置待办
+	Blocks    []models.Todo `json:"blocks"`     // 后续待办
+	Blocked   bool          `json:"blocked"`
+}
+
+// GetDependencies 获取待办的前置待办和后续待办
+func (s *TodoService) GetDependencies(userID, todoID uint) (*TodoDependencies, error) {
+	if _, err := s.GetTodoByID(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	blockedBy, err := s.dependencyRepo.FindBlockers(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询前置待办失败: %v", err)
+	}
+	blocks, err := s.dependencyRepo.FindBlocked(todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询后续待办失败: %v", err)
+	}
+
+	result := &TodoDependencies{BlockedBy: blockedBy, Blocks: blocks}
+	for _, blocker := range blockedBy {
+		if !isResolvedStatus(blocker.Status) {
+			result.Blocked = true
+			break
+		}
+	}
+	return result, nil
+}
+
+// AddDependency 设置blockerID为todoID的前置待办，两者必须属于同一用户且不能形成循环
+func (s *TodoService) AddDependency(userID, todoID, blockerID uint) (*TodoDependencies, error) {
+	if todoID == blockerID {
+		return nil, fmt.Errorf("%w: 待办不能依赖自身", ErrInvalidTodoParams)
+	}
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := s.GetTodoByID(userID, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.HasChildren || blocker.HasChildren {
+		return nil, fmt.Errorf("%w: 重复待办的抽象待办不能设置依赖，请操作具体实例", ErrInvalidTodoParams)
+	}
+
+	exists, err := s.dependencyRepo.Exists(blockerID, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("查询依赖关系失败: %v", err)
+	}
+	if exists {
+		return nil, ErrDependencyExists
+	}
+
+	// 环检测：如果todo已经（直接或间接）阻塞blocker，再让blocker阻塞todo就会形成循环
+	dependencies, err := s.dependencyRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询依赖关系失败: %v", err)
+	}
+	if reachable(dependencies, todoID, blockerID) {
+		return nil, ErrDependencyCycle
+	}
+
+	dependency := &models.TodoDependency{
+		BlockerID:     blockerID,
+		BlockedID:     todoID,
+		CreatorUserID: userID,
+	}
+	if err := s.dependencyRepo.Create(dependency); err != nil {
+		return nil, fmt.Errorf("创建依赖关系失败: %v", err)
+	}
+	return s.GetDependencies(userID, todoID)
+}
+
+// RemoveDependency 移除前置待办
+func (s *TodoService) RemoveDependency(userID, todoID, blockerID uint) (*TodoDependencies, error) {
+	if _, err := s.GetTodoByID(userID, todoID); err != nil {
+		return nil, err
+	}
+	deleted, err := s.dependencyRepo.Delete(blockerID, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("删除依赖关系失败: %v", err)
+	}
+	if !deleted {
+		return nil, fmt.Errorf("%w: 依赖关系不存在", ErrTodoNotFound)
+	}
+	return s.GetDependencies(userID, todoID)
+}
+
+// reachable 沿“阻塞”方向（blocker → blocked）判断from能否到达to
+func reachable(dependencies []models.TodoDependency, from, to uint) bool {
+	edges := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		edges[dependency.BlockerID] = append(edges[dependency.BlockerID], dependency.BlockedID)
+	}
+
+	visited := map[uint]bool{from: true}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// isResolvedStatus 前置待办处于该状态时不再阻塞后续待办
+func isResolvedStatus(status string) bool {
+	return status == TodoStatusCompleted || status == TodoStatusCancelled
+}
+
+// ensureNotBlocked 开始或完成待办前检查前置待办是否都已完成
+func (s *TodoService) ensureNotBlocked(todo *models.Todo, to string) error {
+	if to != TodoStatusInProgress && to != TodoStatusCompleted {
+		return nil
+	}
+	blockedIDs, err := s.dependencyRepo.FindBlockedTodoIDs([]uint{todo.ID})
+	if err != nil {
+		return fmt.Errorf("查询前置待办失败: %v", err)
+	}
+	if len(blockedIDs) > 0 {
+		return ErrTodoBlocked
+	}
+	return nil
+}
+
+// attachBlockedFlags 为待办列表填充阻塞标记
+func (s *TodoService) attachBlockedFlags(todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	blockedIDs, err := s.dependencyRepo.FindBlockedTodoIDs(ids)
+	if err != nil {
+		return fmt.Errorf("查询阻塞状态失败: %v", err)
+	}
+	blocked := make(map[uint]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+	for i := range todos {
+		todos[i].Blocked = blocked[todos[i].ID]
+	}
+	return nil
+}
+
+// notifyUnblocked 前置待办完成或取消后，通知因此解除阻塞的后续待办
+func (s *TodoService) notifyUnblocked(prerequisite *models.Todo) {
+	dependents, err := s.dependencyRepo.FindBlocked(prerequisite.ID)
+	if err != nil {
+		log.Printf("查询后续待办失败: 待办ID=%d, %v", prerequisite.ID, err)
+		return
+	}
+
+	var candidates []uint
+	for _, dependent := range dependents {
+		if !isResolvedStatus(dependent.Status) {
+			candidates = append(candidates, dependent.ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	stillBlocked, err := s.dependencyRepo.FindBlockedTodoIDs(candidates)
+	if err != nil {
+		log.Printf("查询阻塞状态失败: %v", err)
+		return
+	}
+	blocked := make(map[uint]bool, len(stillBlocked))
+	for _, id := range stillBlocked {
+		blocked[id] = true
+	}
+
+	var notifications []models.Notification
+	for _, dependent := range dependents {
+		if isResolvedStatus(dependent.Status) || blocked[dependent.ID] {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:  dependent.CreatorUserID,
+			Type:    models.NotificationTodoUnblocked,
+			Title:   fmt.Sprintf("「%s」可以开始了", dependent.Title),
+			Content: fmt.Sprintf("前置待办「%s」已结束，「%s」不再被阻塞", prerequisite.Title, dependent.Title),
+			TodoID:  dependent.ID,
+		})
+	}
+	if err := s.notificationService.Notify(notifications...); err != nil {
+		log.Printf("发送解除阻塞通知失败: %v", err)
+	}
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNothingToUndo = errors.New("没有可撤销的变更")
+	ErrUndoConflict  = errors.New("无法撤销")
+)
+
+// todoFieldKind 记录历史的字段的值类型
+type todoFieldKind int
+
+const (
+	fieldString todoFieldKind = iota
+	fieldTime
+	fieldNullTime
+	fieldBool
+	fieldInt
+	fieldUint
+)
+
+// todoHistoryFields 记录变更历史的字段，is_overdue等由后台任务维护的派生字段不记录
+var todoHistoryFields = map[string]todoFieldKind{
+	"title":           fieldString,
+	"description":     fieldString,
+	"status":          fieldString,
+	"urgency":         fieldString,
+	"category":        fieldString,
+	"category_id":     fieldUint,
+	"auto_complete":   fieldBool,
+	"start_time":      fieldTime,
+	"end_time":        fieldTime,
+	"started_at":      fieldTime,
+	"completed_at":    fieldTime,
+	"repeat_type":     fieldString,
+	"repeat_interval": fieldInt,
+	"repeat_end_date": fieldTime,
+	"rrule":           fieldString,
+	"exdates":         fieldString,
+	"deleted_at":      fieldNullTime,
+	"parent_id":       fieldUint,
+
+	"snooze_count":        fieldInt,
+	"original_start_time": fieldTime,
+	"original_end_time":   fieldTime,
+
+	"flexible": fieldBool,
+}
+
+// TodoHistoryRequest 变更历史查询参数
+type TodoHistoryRequest struct {
+	Before uint `form:"before" example:"120"` // 上一页最后一个变更集的ID
+	Limit  int  `form:"limit" example:"20"`
+}
+
+// TodoHistoryPage 变更历史分页结果
+type TodoHistoryPage struct {
+	ChangeSets []models.TodoChangeSet `json:"change_sets"`
+	NextBefore uint                   `json:"next_before,omitempty"`
+	HasMore    bool                   `json:"has_more"`
+}
+
+// GetTodoHistory 查询待办的变更历史，包括以它为目标的操作和批量操作中对它的修改，最近的在前
+func (s *TodoService) GetTodoHistory(userID, todoID uint, req *TodoHistoryRequest) (*TodoHistoryPage, error) {
+	if _, err := s.getTodoIncludingTrash(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTodoPageSize
+	}
+	if limit > maxTodoPageSize {
+		limit = maxTodoPageSize
+	}
+
+	sets, err := s.historyRepo.FindByTodo(userID, todoID, req.Before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("查询变更历史失败: %v", err)
+	}
+	page := &TodoHistoryPage{ChangeSets: sets}
+	if len(sets) > limit {
+		page.ChangeSets = sets[:limit]
+		page.HasMore = true
+		page.NextBefore = page.ChangeSets[limit-1].ID
+	}
+	return page, nil
+}
+
+// UndoLastChange 撤销与待办相关的最近一次变更集，包括同一操作中对其他实例的批量修改
+// 撤销本身也会记录为变更集；字段在此之后又被修改过时拒绝撤销
+func (s *TodoService) UndoLastChange(userID, todoID uint) (*models.TodoChangeSet, error) {
+	if _, err := s.getTodoIncludingTrash(userID, todoID); err != nil {
+		return nil, err
+	}
+	set, err := s.historyRepo.FindLatestUndoable(userID, todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNothingToUndo
+		}
+		return nil, fmt.Errorf("查询变更历史失败: %v", err)
+	}
+
+	var undo *models.TodoChangeSet
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		for _, change := range set.Changes {
+			if !slices.Contains(ids, change.TodoID) {
+				ids = append(ids, change.TodoID)
+			}
+		}
+		todos, err := s.todoRepo.FindByIDsIncludingTrashedWithTx(tx, ids)
+		if err != nil {
+			return fmt.Errorf("查询待办失败: %v", err)
+		}
+		byID := make(map[uint]*models.Todo, len(todos))
+		for i := range todos {
+			byID[todos[i].ID] = &todos[i]
+		}
+
+		// 倒序遍历：同一字段被修改多次时，以最后一次的新值做冲突检查，恢复为最早的旧值
+		updates := make(map[uint]map[string]any, len(ids))
+		for i := len(set.Changes) - 1; i >= 0; i-- {
+			change := set.Changes[i]
+			todo, ok := byID[change.TodoID]
+			if !ok {
+				return fmt.Errorf("%w: 待办 %d 已被彻底删除", ErrUndoConflict, change.TodoID)
+			}
+			if updates[todo.ID] == nil {
+				updates[todo.ID] = make(map[string]any)
+			}
+			if _, seen := updates[todo.ID][change.Field]; !seen &&
+				!sameTodoFieldValue(change.Field, todoFieldValue(todo, change.Field), change.NewValue) {
+				return fmt.Errorf("%w: 待办 %d 的 %s 在此之后已被修改", ErrUndoConflict, todo.ID, change.Field)
+			}
+			value, err := parseTodoFieldValue(change.Field, change.OldValue)
+			if err != nil {
+				return err
+			}
+			updates[todo.ID][change.Field] = value
+		}
+
+		recorder := &todoChangeRecorder{}
+		for _, id := range ids {
+			recorder.record(byID[id], updates[id])
+			if err := s.todoRepo.UpdateIncludingTrashedWithTx(tx, id, updates[id]); err != nil {
+				return fmt.Errorf("撤销待办 %d 的变更失败: %v", id, err)
+			}
+			if err := s.syncReminderTimesWithTx(tx, id, updates[id]); err != nil {
+				return fmt.Errorf("同步提醒时间失败: %v", err)
+			}
+		}
+
+		undo = &models.TodoChangeSet{
+			OwnerID:  set.OwnerID,
+			ActorID:  userID,
+			TodoID:   set.TodoID,
+			Action:   models.TodoActionUndo,
+			RevertOf: set.ID,
+			Changes:  recorder.changes,
+		}
+		if err := s.historyRepo.CreateWithTx(tx, undo); err != nil {
+			return fmt.Errorf("记录变更历史失败: %v", err)
+		}
+		marked, err := s.historyRepo.MarkUndoneWithTx(tx, set.ID, undo.ID)
+		if err != nil {
+			return fmt.Errorf("记录变更历史失败: %v", err)
+		}
+		if !marked {
+			return fmt.Errorf("%w: 该变更已被撤销", ErrUndoConflict)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateTodoStats(set.OwnerID)
+	return undo, nil
+}
+
+// getTodoIncludingTrash 获取属于用户的待办，包括回收站中的待办
+func (s *TodoService) getTodoIncludingTrash(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if errors.Is(err, ErrTodoNotFound) {
+		return s.getTrashedTodo(userID, todoID)
+	}
+	return todo, err
+}
+
+// todoChangeRecorder 收集一次操作中各待办的字段变更
+type todoChangeRecorder struct {
+	changes []models.TodoChange
+}
+
+// record 对比待办当前值与即将写入的值，记录发生变化的字段
+func (r *todoChangeRecorder) record(todo *models.Todo, updates map[string]any) {
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		if _, ok := todoHistoryFields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	for _, field := range fields {
+		oldValue := todoFieldValue(todo, field)
+		newValue := formatTodoFieldValue(updates[field])
+		if !sameTodoFieldValue(field, oldValue, newValue) {
+			r.add(todo.ID, field, oldValue, newValue)
+		}
+	}
+}
+
+// add 记录单个字段的变更
+func (r *todoChangeRecorder) add(todoID uint, field, oldValue, newValue string) {
+	r.changes = append(r.changes, models.TodoChange{
+		TodoID:   todoID,
+		Field:    field,
+		OldValue: oldValue,
+		NewValue: newValue,
+	})
+}
+
+// saveChangeSetWithTx 保存一次操作的变更集，没有字段变化时不记录
+func (s *TodoService) saveChangeSetWithTx(tx *gorm.DB, set *models.TodoChangeSet, recorder *todoChangeRecorder) error {
+	if len(recorder.changes) == 0 {
+		return nil
+	}
+	set.Changes = recorder.changes
+	if err := s.historyRepo.CreateWithTx(tx, set); err != nil {
+		return fmt.Errorf("记录变更历史失败: %v", err)
+	}
+	invalidateTodoStats(set.OwnerID)
+	return nil
+}
+
+// cancelChildrenWithTx 取消抽象待办下未完成的实例，并记录每个实例的状态变更
+func (s *TodoService) cancelChildrenWithTx(tx *gorm.DB, parentID uint, recorder *todoChangeRecorder) (int64, error) {
+	var rowsAffected int64
+	for _, status := range []string{TodoStatusPending, TodoStatusInProgress} {
+		children, err := s.todoRepo.FindChildrenByStatusWithTx(tx, parentID, status)
+		if err != nil {
+			return 0, fmt.Errorf("查询子待办失败: %v", err)
+		}
+		if len(children) == 0 {
+			continue
+		}
+		affected, err := s.todoRepo.BatchUpdateChildrenStatusWithTx(tx, parentID, status, TodoStatusCancelled)
+		if err != nil {
+			return 0, fmt.Errorf("批量更新子待办失败: %v", err)
+		}
+		for _, child := range children {
+			recorder.add(child.ID, "status", status, TodoStatusCancelled)
+		}
+		rowsAffected += affected
+	}
+	return rowsAffected, nil
+}
+
+// todoFieldValue 取待办字段的当前值
+func todoFieldValue(todo *models.Todo, field string) string {
+	switch field {
+	case "title":
+		return todo.Title
+	case "description":
+		return todo.Description
+	case "status":
+		return todo.Status
+	case "urgency":
+		return todo.Urgency
+	case "category":
+		return todo.Category
+	case "category_id":
+		return formatTodoFieldValue(todo.CategoryID)
+	case "auto_complete":
+		return formatTodoFieldValue(todo.AutoComplete)
+	case "start_time":
+		return formatTodoFieldValue(todo.StartTime)
+	case "end_time":
+		return formatTodoFieldValue(todo.EndTime)
+	case "started_at":
+		return formatTodoFieldValue(todo.StartedAt)
+	case "completed_at":
+		return formatTodoFieldValue(todo.CompletedAt)
+	case "repeat_type":
+		return todo.RepeatType
+	case "repeat_interval":
+		return formatTodoFieldValue(todo.RepeatInterval)
+	case "repeat_end_date":
+		return formatTodoFieldValue(todo.RepeatEndDate)
+	case "rrule":
+		return todo.RRule
+	case "exdates":
+		return todo.ExDates
+	case "deleted_at":
+		return formatTodoFieldValue(todo.DeletedAt)
+	case "parent_id":
+		return formatTodoFieldValue(todo.ParentID)
+	case "snooze_count":
+		return formatTodoFieldValue(todo.SnoozeCount)
+	case "original_start_time":
+		return formatTodoFieldValue(todo.OriginalStartTime)
+	case "original_end_time":
+		return formatTodoFieldValue(todo.OriginalEndTime)
+	case "flexible":
+		return formatTodoFieldValue(todo.Flexible)
+	default:
+		return ""
+	}
+}
+
+// formatTodoFieldValue 将字段值格式化为历史记录中保存的字符串
+func formatTodoFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case gorm.DeletedAt:
+		if !v.Valid {
+			return ""
+		}
+		return v.Time.Format(time.RFC3339Nano)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseTodoFieldValue 将历史记录中的字符串还原为可写入数据库的字段值
+func parseTodoFieldValue(field, value string) (any, error) {
+	var (
+		parsed any
+		err    error
+	)
+	switch todoHistoryFields[field] {
+	case fieldTime:
+		parsed, err = time.Parse(time.RFC3339Nano, value)
+	case fieldNullTime:
+		if value == "" {
+			return nil, nil
+		}
+		parsed, err = time.Parse(time.RFC3339Nano, value)
+	case fieldBool:
+		parsed, err = strconv.ParseBool(value)
+	case fieldInt:
+		parsed, err = strconv.Atoi(value)
+	case fieldUint:
+		var n uint64
+		n, err = strconv.ParseUint(value, 10, 64)
+		parsed = uint(n)
+	default:
+		parsed = value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("变更历史中 %s 的值 '%s' 无效: %v", field, value, err)
+	}
+	return parsed, nil
+}
+
+// sameTodoFieldValue 比较两个字段值是否相同，时间允许数据库毫秒精度带来的误差
+func sameTodoFieldValue(field, a, b string) bool {
+	if a == b {
+		return true
+	}
+	kind := todoHistoryFields[field]
+	if kind != fieldTime && kind != fieldNullTime {
+		return false
+	}
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	diff := ta.Sub(tb)
+	return diff < time.Millisecond && diff > -time.Millisecond
+}
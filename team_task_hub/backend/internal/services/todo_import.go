@@ -0,0 +1,511 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// 导入文件格式
+const (
+	ImportFormatICS = "ics"
+	ImportFormatCSV = "csv"
+)
+
+// maxImportRows 单次导入的最大条目数
+const maxImportRows = 1000
+
+// 导入结果中每一行的状态
+const (
+	ImportRowValid     = "valid"     // 校验通过（试运行）
+	ImportRowCreated   = "created"   // 已创建
+	ImportRowDuplicate = "duplicate" // 与已有待办或文件中前面的条目重复，跳过
+	ImportRowInvalid   = "invalid"   // 校验失败，跳过
+)
+
+// csvTimeLayouts CSV中支持的时间格式，不带时区的按服务器本地时区解析
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+}
+
+// ImportRowResult 单个导入条目的处理结果
+type ImportRowResult struct {
+	Row       int       `json:"row"` // CSV为文件行号（表头为第1行），ICS为第几个VEVENT/VTODO
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	TodoID    uint      `json:"todo_id,omitempty"`
+}
+
+// ImportReport 导入报告
+type ImportReport struct {
+	Format     string            `json:"format"`
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Valid      int               `json:"valid"`
+	Created    int               `json:"created"`
+	Duplicates int               `json:"duplicates"`
+	Invalid    int               `json:"invalid"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// importRow 解析出的一个待导入条目
+type importRow struct {
+	row int
+	req *CreateTodoRequest
+	err error // 解析阶段的错误
+}
+
+// preparedImport 校验通过的条目
+type preparedImport struct {
+	result   *ImportRowResult
+	req      *CreateTodoRequest
+	rule     *models.RecurrenceRule
+	category *models.Category
+	exDates  string
+	tags     []string
+}
+
+// ImportTodos 从ICS或CSV导入待办
+// 每个条目按创建待办的规则校验，并与已有待办及文件中前面的条目去重；
+// dryRun为true时只返回报告，否则在一个事务中创建全部校验通过的条目
+func (s *TodoService) ImportTodos(userID uint, format string, data []byte, dryRun bool) (*ImportReport, error) {
+	var rows []importRow
+	var err error
+	// 不带时区的时间按用户时区解析
+	loc := s.userLocation(userID)
+	switch format {
+	case ImportFormatICS:
+		rows, err = parseICSImport(data, loc)
+	case ImportFormatCSV:
+		rows, err = parseCSVImport(data, loc)
+	default:
+		return nil, fmt.Errorf("%w: 不支持的导入格式 %s，仅支持 ics 和 csv", ErrInvalidTodoParams, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: 文件中没有可导入的条目", ErrInvalidTodoParams)
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: 单次最多导入%d条", ErrInvalidTodoParams, maxImportRows)
+	}
+
+	report := &ImportReport{
+		Format: format,
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]ImportRowResult, len(rows)),
+	}
+
+	categories := make(map[string]*models.Category)
+	seen := make(map[string]bool)
+	var valid []preparedImport
+
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Row = row.row
+		if row.req != nil {
+			result.Title = row.req.Title
+			result.StartTime = row.req.StartTime
+		}
+
+		prepared, err := s.prepareImport(userID, row, categories)
+		if err != nil {
+			result.Status = ImportRowInvalid
+			result.Error = err.Error()
+			report.Invalid++
+			continue
+		}
+
+		key := importDedupKey(prepared.req)
+		duplicate := seen[key]
+		if !duplicate {
+			existing, err := s.todoRepo.FindTodoByDetails(userID, prepared.req.StartTime, prepared.req.EndTime, prepared.req.Title, prepared.req.Description)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("查询已有待办失败: %v", err)
+			}
+			duplicate = existing != nil
+		}
+		if duplicate {
+			result.Status = ImportRowDuplicate
+			report.Duplicates++
+			continue
+		}
+		seen[key] = true
+
+		result.Status = ImportRowValid
+		report.Valid++
+		prepared.result = result
+		valid = append(valid, prepared)
+	}
+
+	if dryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	if err := s.createImportedTodos(userID, valid); err != nil {
+		return nil, err
+	}
+	invalidateTodoStats(userID)
+	s.replanAfterChange(userID)
+	report.Created = len(valid)
+	return report, nil
+}
+
+// prepareImport 按创建待办的规则校验一个条目
+func (s *TodoService) prepareImport(userID uint, row importRow, categories map[string]*models.Category) (preparedImport, error) {
+	if row.err != nil {
+		return preparedImport{}, row.err
+	}
+	req := row.req
+	if err := s.resolveTodoTimezone(userID, req); err != nil {
+		return preparedImport{}, err
+	}
+	if req.Urgency == "" {
+		req.Urgency = "medium"
+	}
+	if !todoUrgencies[req.Urgency] {
+		return preparedImport{}, fmt.Errorf("无效的紧急程度 %s", req.Urgency)
+	}
+	if err := s.validateCreateRequest(req); err != nil {
+		return preparedImport{}, err
+	}
+
+	rule, err := s.resolveRecurrenceRule(req)
+	if err != nil {
+		return preparedImport{}, err
+	}
+	exDates, err := normalizeExDates(req.ExDates)
+	if err != nil {
+		return preparedImport{}, err
+	}
+	tags, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return preparedImport{}, err
+	}
+
+	category, ok := categories[req.Category]
+	if !ok {
+		category, err = s.resolveCategory(userID, req.Category)
+		if err != nil {
+			return preparedImport{}, err
+		}
+		categories[req.Category] = category
+	}
+
+	return preparedImport{
+		req:      req,
+		rule:     rule,
+		category: category,
+		exDates:  exDates,
+		tags:     tags,
+	}, nil
+}
+
+// createImportedTodos 在一个事务中创建导入的待办、重复待办的实例以及标签关联和清单
+func (s *TodoService) createImportedTodos(userID uint, items []preparedImport) error {
+	// 标签在事务外按名称一次性查找或创建
+	var tagNames []string
+	for _, item := range items {
+		tagNames = append(tagNames, item.tags...)
+	}
+	tagNames, _ = normalizeTagNames(tagNames)
+	tagIDByName := make(map[string]uint)
+	if len(tagNames) > 0 {
+		tags, err := s.tagRepo.FindOrCreateByNames(userID, tagNames)
+		if err != nil {
+			return fmt.Errorf("创建标签失败: %v", err)
+		}
+		for _, tag := range tags {
+			tagIDByName[tag.Name] = tag.ID
+		}
+	}
+
+	todos := make([]models.Todo, len(items))
+	for i, item := range items {
+		if item.rule == nil {
+			todos[i] = *newSingleTodo(userID, item.req, item.category)
+		} else {
+			todos[i] = *newRootTodo(userID, item.req, item.rule, item.category, item.exDates)
+		}
+	}
+
+	return s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.todoRepo.BatchCreateWithTx(tx, todos); err != nil {
+			return fmt.Errorf("创建待办失败: %v", err)
+		}
+
+		for i, item := range items {
+			todoIDs := []uint{todos[i].ID}
+			if todos[i].HasChildren {
+				children, err := s.expandChildTodos(&todos[i])
+				if err != nil {
+					return fmt.Errorf("生成子待办失败: %v", err)
+				}
+				if err := s.todoRepo.BatchCreateWithTx(tx, children); err != nil {
+					return fmt.Errorf("创建子待办失败: %v", err)
+				}
+				for _, child := range children {
+					todoIDs = append(todoIDs, child.ID)
+				}
+			}
+
+			// 清单只创建在实例上，重复待办的抽象待办不带清单
+			instanceIDs := todoIDs
+			if todos[i].HasChildren {
+				instanceIDs = todoIDs[1:]
+			}
+			if err := s.checklistRepo.BatchCreateWithTx(tx, newChecklistItems(instanceIDs, item.req.Checklist)); err != nil {
+				return fmt.Errorf("创建待办清单失败: %v", err)
+			}
+
+			tagIDs := make([]uint, 0, len(item.tags))
+			for _, name := range item.tags {
+				tagIDs = append(tagIDs, tagIDByName[name])
+			}
+			if err := s.tagRepo.AddTodoTagsWithTx(tx, todoIDs, tagIDs); err != nil {
+				return fmt.Errorf("设置待办标签失败: %v", err)
+			}
+
+			item.result.Status = ImportRowCreated
+			item.result.TodoID = todos[i].ID
+		}
+		return nil
+	})
+}
+
+// importDedupKey 文件内去重使用的键，与FindTodoByDetails的匹配条件一致
+func importDedupKey(req *CreateTodoRequest) string {
+	return strings.Join([]string{
+		req.Title,
+		req.Description,
+		req.StartTime.UTC().Format(time.RFC3339),
+		req.EndTime.UTC().Format(time.RFC3339),
+	}, "\x00")
+}
+
+// parseICSImport 将ICS中的VEVENT/VTODO转换为创建请求
+func parseICSImport(data []byte, loc *time.Location) ([]importRow, error) {
+	components, err := parseICalComponents(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0, len(components))
+	for i := range components {
+		req, err := icsComponentToRequest(&components[i], loc)
+		rows = append(rows, importRow{row: i + 1, req: req, err: err})
+	}
+	return rows, nil
+}
+
+// icsComponentToRequest 将一个VEVENT/VTODO转换为创建请求
+// CATEGORIES作为标签导入，分类使用默认分类；DTSTART带有可识别的TZID时作为待办的时区
+func icsComponentToRequest(c *icalComponent, loc *time.Location) (*CreateTodoRequest, error) {
+	req := &CreateTodoRequest{}
+	if p := c.get("SUMMARY"); p != nil {
+		req.Title = strings.TrimSpace(icalUnescape(p.Value))
+	}
+	if p := c.get("DESCRIPTION"); p != nil {
+		req.Description = strings.TrimSpace(icalUnescape(p.Value))
+	}
+
+	if c.get("RECURRENCE-ID") != nil {
+		return req, errors.New("重复日程的单次修改不单独导入")
+	}
+	if p := c.get("STATUS"); p != nil {
+		switch strings.ToUpper(p.Value) {
+		case "COMPLETED", "CANCELLED":
+			return req, errors.New("已完成或已取消的条目不导入")
+		}
+	}
+
+	dtstart := c.get("DTSTART")
+	endProp := c.get("DTEND")
+	if c.Name == "VTODO" {
+		endProp = c.get("DUE")
+	}
+	if dtstart == nil {
+		dtstart = endProp
+	}
+	if dtstart == nil {
+		return req, errors.New("缺少DTSTART")
+	}
+	start, dateOnly, err := parseICalTime(dtstart.Value, dtstart.Params, loc)
+	if err != nil {
+		return req, fmt.Errorf("无效的开始时间: %v", err)
+	}
+	req.StartTime = start
+	if tzid := dtstart.Params["TZID"]; models.ValidTimezone(tzid) {
+		req.Timezone = tzid
+	}
+
+	switch duration := c.get("DURATION"); {
+	case endProp != nil:
+		end, _, err := parseICalTime(endProp.Value, endProp.Params, loc)
+		if err != nil {
+			return req, fmt.Errorf("无效的结束时间: %v", err)
+		}
+		req.EndTime = end
+	case duration != nil:
+		d, err := parseICalDuration(duration.Value)
+		if err != nil {
+			return req, err
+		}
+		req.EndTime = start.Add(d)
+	case dateOnly:
+		req.EndTime = start.Add(24 * time.Hour)
+	default:
+		req.EndTime = start
+	}
+
+	if p := c.get("PRIORITY"); p != nil {
+		req.Urgency = urgencyFromICalPriority(p.Value)
+	}
+	for _, p := range c.getAll("CATEGORIES") {
+		for _, name := range icalSplitList(p.Value) {
+			if name = strings.TrimSpace(name); name != "" {
+				req.Tags = append(req.Tags, name)
+			}
+		}
+	}
+
+	if p := c.get("RRULE"); p != nil {
+		req.RRule = p.Value
+		for _, exdate := range c.getAll("EXDATE") {
+			for _, value := range strings.Split(exdate.Value, ",") {
+				t, _, err := parseICalTime(value, exdate.Params, loc)
+				if err != nil {
+					return req, fmt.Errorf("无效的EXDATE: %v", err)
+				}
+				req.ExDates = append(req.ExDates, t.In(start.Location()).Format("2006-01-02"))
+			}
+		}
+	}
+	return req, nil
+}
+
+// urgencyFromICalPriority 将iCalendar优先级映射为紧急程度
+func urgencyFromICalPriority(value string) string {
+	switch strings.TrimSpace(value) {
+	case "1", "2", "3", "4":
+		return "high"
+	case "6", "7", "8", "9":
+		return "low"
+	default:
+		return "medium"
+	}
+}
+
+// parseCSVImport 解析CSV，首行为表头
+// 支持的列：title, description, start_time, end_time, urgency, category, tags, rrule, exdates
+// tags 和 exdates 中的多个值用分号或竖线分隔
+func parseCSVImport(data []byte, loc *time.Location) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV表头失败: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "start_time", "end_time"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV缺少必需的列 %s", required)
+		}
+	}
+
+	var rows []importRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rows = append(rows, importRow{row: line, err: fmt.Errorf("CSV格式错误: %v", err)})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		req := &CreateTodoRequest{
+			Title:       field("title"),
+			Description: field("description"),
+			Urgency:     strings.ToLower(field("urgency")),
+			Category:    field("category"),
+			Tags:        splitMultiValue(field("tags")),
+			RRule:       field("rrule"),
+			ExDates:     splitMultiValue(field("exdates")),
+		}
+		row := importRow{row: line, req: req}
+		if req.StartTime, err = parseCSVTime(field("start_time"), loc); err != nil {
+			row.err = fmt.Errorf("无效的开始时间: %v", err)
+		} else if req.EndTime, err = parseCSVTime(field("end_time"), loc); err != nil {
+			row.err = fmt.Errorf("无效的结束时间: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseCSVTime 按支持的格式解析CSV中的时间，不带偏移量的时间按loc时区解析
+func parseCSVTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("时间不能为空")
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间 '%s'，支持 RFC3339 或 YYYY-MM-DD HH:MM", value)
+}
+
+// splitMultiValue 拆分用分号或竖线分隔的多值字段
+func splitMultiValue(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '|' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// isBlankRecord 判断CSV记录是否为空行
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
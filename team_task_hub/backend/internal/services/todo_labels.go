@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxLabelNameLength 分类和标签名称的最大长度
+const maxLabelNameLength = 50
+
+// TodoListFilter 待办列表的通用筛选条件
+type TodoListFilter struct {
+	Tags     []string // 标签名称，命中任意一个即可
+	Category string   // 分类名称
+}
+
+// ParseTodoListFilter 从查询参数构造筛选条件，tags为逗号分隔的标签名称
+func ParseTodoListFilter(tags, category string) TodoListFilter {
+	return TodoListFilter{
+		Tags:     splitCommaList(tags),
+		Category: strings.TrimSpace(category),
+	}
+}
+
+// normalizeLabelName 校验并清理分类/标签名称
+func normalizeLabelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: 名称不能为空", ErrInvalidTodoParams)
+	}
+	if utf8.RuneCountInString(name) > maxLabelNameLength {
+		return "", fmt.Errorf("%w: 名称不能超过%d个字符", ErrInvalidTodoParams, maxLabelNameLength)
+	}
+	return name, nil
+}
+
+// normalizeTagNames 校验标签名称并去重，保持原有顺序
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name, err := normalizeLabelName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// resolveCategory 根据分类名称查找用户的分类，名称为空时使用默认分类
+func (s *TodoService) resolveCategory(userID uint, name string) (*models.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = models.DefaultCategoryName
+	}
+
+	if err := s.categoryRepo.EnsureDefaults(userID); err != nil {
+		return nil, fmt.Errorf("初始化默认分类失败: %v", err)
+	}
+	category, err := s.categoryRepo.FindByName(userID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: 分类 %s 不存在", ErrInvalidTodoParams, name)
+		}
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+	return category, nil
+}
+
+// resolveTagIDs 根据标签名称得到标签ID，不存在的标签自动创建
+func (s *TodoService) resolveTagIDs(userID uint, names []string) ([]uint, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags, err := s.tagRepo.FindOrCreateByNames(userID, names)
+	if err != nil {
+		return nil, fmt.Errorf("创建标签失败: %v", err)
+	}
+	tagIDs := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return tagIDs, nil
+}
+
+// attachTags 为待办列表填充标签
+func (s *TodoService) attachTags(todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	tagsByTodo, err := s.tagRepo.FindTagsByTodoIDs(ids)
+	if err != nil {
+		return fmt.Errorf("查询待办标签失败: %v", err)
+	}
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+	}
+	return nil
+}
+
+// attachOrganizations 为组织下发的待办填充来源组织名称
+func (s *TodoService) attachOrganizations(todos []models.Todo) error {
+	seen := make(map[uint]bool)
+	var orgIDs []uint
+	for _, todo := range todos {
+		if todo.CreatorOrganID > 0 && !seen[todo.CreatorOrganID] {
+			seen[todo.CreatorOrganID] = true
+			orgIDs = append(orgIDs, todo.CreatorOrganID)
+		}
+	}
+	if len(orgIDs) == 0 {
+		return nil
+	}
+	names, err := s.todoRepo.FindOrganizationNames(orgIDs)
+	if err != nil {
+		return fmt.Errorf("查询待办来源组织失败: %v", err)
+	}
+	for i := range todos {
+		todos[i].OrganizationName = names[todos[i].CreatorOrganID]
+	}
+	return nil
+}
+
+// attachTodoDetails 将时间转换到待办所属时区，并为待办列表填充标签、来源组织、清单进度和阻塞标记
+func (s *TodoService) attachTodoDetails(todos []models.Todo) error {
+	for i := range todos {
+		todos[i].Localize()
+	}
+	if err := s.attachTags(todos); err != nil {
+		return err
+	}
+	if err := s.attachOrganizations(todos); err != nil {
+		return err
+	}
+	if err := s.attachChecklistProgress(todos); err != nil {
+		return err
+	}
+	return s.attachBlockedFlags(todos)
+}
+
+// applyListFilter 为待办列表填充详情，并按分类和标签筛选
+func (s *TodoService) applyListFilter(todos []models.Todo, filter TodoListFilter) ([]models.Todo, error) {
+	if err := s.attachTodoDetails(todos); err != nil {
+		return nil, err
+	}
+	if filter.Category == "" && len(filter.Tags) == 0 {
+		return todos, nil
+	}
+
+	wanted := make(map[string]bool, len(filter.Tags))
+	for _, name := range filter.Tags {
+		wanted[name] = true
+	}
+
+	filtered := make([]models.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if filter.Category != "" && todo.Category != filter.Category {
+			continue
+		}
+		if len(wanted) > 0 && !hasAnyTag(todo.Tags, wanted) {
+			continue
+		}
+		filtered = append(filtered, todo)
+	}
+	return filtered, nil
+}
+
+// hasAnyTag 判断标签列表中是否包含任意一个目标标签
+func hasAnyTag(tags []models.Tag, wanted map[string]bool) bool {
+	for _, tag := range tags {
+		if wanted[tag.Name] {
+			return true
+		}
+	}
+	return false
+}
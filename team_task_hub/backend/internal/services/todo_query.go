@@ -0,0 +1,236 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+	"team_task_hub/backend/internal/repositories"
+)
+
+// 待办查询分页参数
+const (
+	defaultTodoPageSize = 20
+	maxTodoPageSize     = 100
+)
+
+// todoUrgencies 合法的紧急程度
+var todoUrgencies = map[string]bool{"low": true, "medium": true, "high": true}
+
+// TodoQueryRequest 待办查询参数，多值参数用逗号分隔
+// 时间参数支持 YYYY-MM-DD（按用户时区）或 RFC3339，_from 包含、_to 不包含；日期格式的 _to 包含当天
+type TodoQueryRequest struct {
+	Status        string `form:"status" example:"pending,in_progress"`
+	Urgency       string `form:"urgency" example:"high"`
+	Category      string `form:"category" example:"work"`
+	Tags          string `form:"tags" example:"会议,重要"`
+	ParentID      uint   `form:"parent_id" example:"12"`
+	IncludeSeries bool   `form:"include_series" example:"false"`
+	Overdue       *bool  `form:"overdue" example:"true"`
+	Q             string `form:"q" example:"周报"`
+	StartFrom     string `form:"start_from" example:"2025-01-01"`
+	StartTo       string `form:"start_to" example:"2025-01-31"`
+	EndFrom       string `form:"end_from"`
+	EndTo         string `form:"end_to"`
+	CompletedFrom string `form:"completed_from"`
+	CompletedTo   string `form:"completed_to"`
+	Sort          string `form:"sort" example:"start_time"`
+	Order         string `form:"order" example:"asc"`
+	Cursor        string `form:"cursor"`
+	Limit         int    `form:"limit" example:"20"`
+}
+
+// TodoPage 待办分页结果，next_cursor 为空表示没有更多数据
+type TodoPage struct {
+	Todos      []models.Todo `json:"todos"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// todoCursor 游标内容，记录排序方式和上一页最后一条记录的位置
+type todoCursor struct {
+	Sort  string    `json:"s"`
+	Order string    `json:"o"`
+	Value time.Time `json:"v"`
+	ID    uint      `json:"id"`
+}
+
+// QueryTodos 按条件筛选、排序并分页查询待办
+func (s *TodoService) QueryTodos(userID uint, req *TodoQueryRequest) (*TodoPage, error) {
+	sortField := strings.TrimSpace(req.Sort)
+	if sortField == "" {
+		sortField = "start_time"
+	}
+	if !repositories.IsTodoSortField(sortField) {
+		return nil, fmt.Errorf("%w: 不支持的排序字段 %s", ErrInvalidTodoParams, sortField)
+	}
+	order := strings.ToLower(strings.TrimSpace(req.Order))
+	if order == "" {
+		order = "asc"
+	}
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("%w: order 只能为 asc 或 desc", ErrInvalidTodoParams)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTodoPageSize
+	}
+	if limit > maxTodoPageSize {
+		limit = maxTodoPageSize
+	}
+
+	statuses := splitCommaList(req.Status)
+	for _, status := range statuses {
+		if _, ok := todoTransitions[status]; !ok {
+			return nil, fmt.Errorf("%w: 未知的状态 %s", ErrInvalidTodoParams, status)
+		}
+	}
+	urgencies := splitCommaList(req.Urgency)
+	for _, urgency := range urgencies {
+		if !todoUrgencies[urgency] {
+			return nil, fmt.Errorf("%w: 未知的紧急程度 %s", ErrInvalidTodoParams, urgency)
+		}
+	}
+
+	query := s.todoRepo.Query(userID).
+		Statuses(statuses...).
+		Urgencies(urgencies...).
+		Categories(splitCommaList(req.Category)...).
+		AnyTags(splitCommaList(req.Tags)...).
+		Text(strings.TrimSpace(req.Q)).
+		OrderBy(sortField, order == "desc")
+
+	if req.ParentID != 0 {
+		query.Parent(req.ParentID)
+	}
+	if req.ParentID != 0 || !req.IncludeSeries {
+		query.InstancesOnly()
+	}
+	if req.Overdue != nil {
+		query.Overdue(*req.Overdue)
+	}
+
+	ranges := []struct {
+		field    string
+		from, to string
+	}{
+		{"start_time", req.StartFrom, req.StartTo},
+		{"end_time", req.EndFrom, req.EndTo},
+		{"completed_at", req.CompletedFrom, req.CompletedTo},
+	}
+	loc := s.userLocation(userID)
+	for _, r := range ranges {
+		from, err := parseQueryTime(r.from, false, loc)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseQueryTime(r.to, true, loc)
+		if err != nil {
+			return nil, err
+		}
+		query.TimeRange(r.field, from, to)
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeTodoCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != sortField || cursor.Order != order {
+			return nil, fmt.Errorf("%w: 游标与当前排序方式不一致", ErrInvalidTodoParams)
+		}
+		query.After(cursor.Value, cursor.ID)
+	}
+
+	// 多取一条用于判断是否还有下一页
+	todos, err := query.Limit(limit + 1).Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+
+	page := &TodoPage{Todos: todos}
+	if len(todos) > limit {
+		page.Todos = todos[:limit]
+		page.HasMore = true
+		last := page.Todos[limit-1]
+		page.NextCursor = encodeTodoCursor(todoCursor{
+			Sort:  sortField,
+			Order: order,
+			Value: todoSortValue(&last, sortField),
+			ID:    last.ID,
+		})
+	}
+
+	if err := s.attachTodoDetails(page.Todos); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// splitCommaList 拆分逗号分隔的查询参数，忽略空项
+func splitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseQueryTime 解析查询中的时间参数，日期格式按loc时区取当天开始，upper为true时取当天结束
+func parseQueryTime(value string, upper bool, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := ParseDateString(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+	start, end := DayRange(date, loc)
+	if upper {
+		return end, nil
+	}
+	return start, nil
+}
+
+// todoSortValue 取待办在排序字段上的值
+func todoSortValue(todo *models.Todo, field string) time.Time {
+	switch field {
+	case "end_time":
+		return todo.EndTime
+	case "created_at":
+		return todo.CreatedAt
+	case "completed_at":
+		return todo.CompletedAt
+	default:
+		return todo.StartTime
+	}
+}
+
+// encodeTodoCursor 将游标编码为URL安全的字符串
+func encodeTodoCursor(cursor todoCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTodoCursor 解析游标字符串
+func decodeTodoCursor(value string) (*todoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无效的游标", ErrInvalidTodoParams)
+	}
+	var cursor todoCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, fmt.Errorf("%w: 无效的游标", ErrInvalidTodoParams)
+	}
+	return &cursor, nil
+}
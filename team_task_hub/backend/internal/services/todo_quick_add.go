@@ -0,0 +1,1054 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+)
+
+// 快速添加解析出的片段类型
+const (
+	QuickAddTokenRecurrence = "recurrence"
+	QuickAddTokenDate       = "date"
+	QuickAddTokenTime       = "time"
+	QuickAddTokenDuration   = "duration"
+	QuickAddTokenUrgency    = "urgency"
+	QuickAddTokenTag        = "tag"
+)
+
+// 快速添加的默认值
+const (
+	quickAddDefaultDuration = time.Hour // 只给出开始时间时的默认时长
+	quickAddDefaultHour     = 9         // 只给出日期和时长时的默认开始时间
+)
+
+// QuickAddRequest 快速添加请求
+type QuickAddRequest struct {
+	Text    string `json:"text" binding:"required,max=500" example:"每周三下午3点 小组会议 高优先级"`
+	Preview bool   `json:"preview" example:"true"` // 只返回解析结果，不创建待办
+}
+
+// QuickAddToken 从输入中识别出的片段
+type QuickAddToken struct {
+	Type string `json:"type"` // 见 QuickAddToken* 常量
+	Text string `json:"text"` // 原文
+}
+
+// QuickAddResult 快速添加结果，Request可以修改后提交到创建待办接口
+type QuickAddResult struct {
+	Request   *CreateTodoRequest `json:"request"`
+	Tokens    []QuickAddToken    `json:"tokens"`
+	Warnings  []string           `json:"warnings,omitempty"`
+	Conflicts []ScheduleConflict `json:"conflicts,omitempty"` // 与解析出的时间重叠的待办和活动
+	TodoID    uint               `json:"todo_id,omitempty"`
+}
+
+// QuickAddTodo 解析一句话形式的待办，preview为false时直接创建
+// #标签与已有分类同名时作为分类，其余作为标签
+func (s *TodoService) QuickAddTodo(userID uint, req *QuickAddRequest) (*QuickAddResult, error) {
+	loc := s.userLocation(userID)
+	parsed, err := parseQuickAdd(req.Text, time.Now().In(loc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+
+	if err := s.categoryRepo.EnsureDefaults(userID); err != nil {
+		return nil, fmt.Errorf("初始化默认分类失败: %v", err)
+	}
+	categories, err := s.categoryRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+	for i, tag := range parsed.hashtags {
+		if parsed.request.Category == "" {
+			if category := findCategoryByName(categories, tag); category != nil {
+				parsed.request.Category = category.Name
+				continue
+			}
+		}
+		parsed.request.Tags = append(parsed.request.Tags, parsed.hashtags[i])
+	}
+
+	result := &QuickAddResult{
+		Request:  parsed.request,
+		Tokens:   parsed.tokens,
+		Warnings: parsed.warnings,
+	}
+	if req.Preview {
+		result.Conflicts, err = s.conflictService.FindConflicts(userID, parsed.request.StartTime, parsed.request.EndTime, 0)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	resp, err := s.CreateTodo(userID, parsed.request)
+	if err != nil {
+		return nil, err
+	}
+	result.TodoID = resp.TodoID
+	result.Conflicts = resp.Conflicts
+	return result, nil
+}
+
+// findCategoryByName 不区分大小写地按名称查找分类
+func findCategoryByName(categories []models.Category, name string) *models.Category {
+	for i := range categories {
+		if strings.EqualFold(categories[i].Name, name) {
+			return &categories[i]
+		}
+	}
+	return nil
+}
+
+// 中文数字，用于“三点”“十五号”等
+const cnNum = `[0-9零〇一二两三四五六七八九十]+`
+
+// 英文星期名称
+const enWeekday = `(?:monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tues?|wed|thu(?:rs?)?|fri|sat|sun)`
+
+// 中文时段前缀
+const cnPeriodPrefix = `(?:凌晨|早上|早晨|上午|中午|午后|下午|傍晚|晚上|夜里)\s*`
+
+// 中文时间分钟部分：半/一刻/三刻/几分
+const cnMinute = `\s*(?:半|一刻|三刻|` + cnNum + `\s*分?)?`
+
+// 中文时间：时段 + 几点或几:几，没有时段时必须用“点”，“7:30pm”这类冒号时间交给英文规则识别
+const cnClock = `(?:` + cnPeriodPrefix + cnNum + `\s*(?:点|時|时|:|：)|(?:` + cnPeriodPrefix + `)?` + cnNum + `\s*(?:点|時|时))` + cnMinute
+
+// 时间段的结束时间沿用开始时间的写法，冒号不要求时段，如“下午3:00-5:00”
+const cnClockEnd = `(?:` + cnPeriodPrefix + `)?` + cnNum + `\s*(?:点|時|时|:|：)` + cnMinute
+
+// 英文时间：10am、10:30 pm、14:00、noon
+const enClock = `(?:\d{1,2}(?::\d{2})?\s*(?:am|pm|a\.m\.|p\.m\.)|\d{1,2}:\d{2}|noon|midnight)`
+
+var (
+	// 重复规则
+	reCNWorkday   = regexp.MustCompile(`每个?工作日`)
+	reCNWeekend   = regexp.MustCompile(`每个?周末`)
+	reCNWeekly    = regexp.MustCompile(`每(隔)?(` + cnNum + `)?个?(?:周|星期|礼拜)((?:[、,，和及与]?(?:周|星期|礼拜)?[一二三四五六日天1-7])*)`)
+	reCNMonthly   = regexp.MustCompile(`每(隔)?(` + cnNum + `)?个?月(?:的)?(?:(` + cnNum + `)[号日]|(最后一天))?`)
+	reCNDaily     = regexp.MustCompile(`每(隔)?(` + cnNum + `)?(?:天|日)`)
+	reCNYearly    = regexp.MustCompile(`每(隔)?(` + cnNum + `)?年(?:的)?(?:(` + cnNum + `)月(` + cnNum + `)[日号])?`)
+	reENWorkday   = regexp.MustCompile(`(?i)\b(?:every\s+weekdays?|on\s+weekdays|weekdays)\b`)
+	reENWeekend   = regexp.MustCompile(`(?i)\b(?:every\s+weekend|on\s+weekends|weekends)\b`)
+	reENWeekdays  = regexp.MustCompile(`(?i)\bevery\s+(` + enWeekday + `(?:\s*(?:,|and|&)\s*` + enWeekday + `)*)\b`)
+	reENEvery     = regexp.MustCompile(`(?i)\bevery\s+(?:(other)\s+|(\d+)\s+)?(day|week|month|year)s?\b`)
+	reENFrequency = regexp.MustCompile(`(?i)\b(daily|weekly|monthly|yearly|annually)\b`)
+
+	// 重复规则的截止日期，如“until friday”“到1月20日为止”“直到下周五”
+	reENUntil = regexp.MustCompile(`(?i)\b(?:until|till|through|thru)\s+`)
+	reCNUntil = regexp.MustCompile(`(?:直到|截止到|截至|到)(.+?)(?:为止|结束)|(?:直到|截止到|截至)(\S+)`)
+
+	// 日期
+	reCNRelativeDay = regexp.MustCompile(`(大后天|后天|明天|明日|今天|今日|今晚)`)
+	reCNDaysLater   = regexp.MustCompile(`(` + cnNum + `)天(?:后|以后|之后)`)
+	reCNWeekday     = regexp.MustCompile(`(下下|下|这|本)?个?(?:周|星期|礼拜)([一二三四五六日天])`)
+	reCNMonthDay    = regexp.MustCompile(`(?:(\d{4})年)?(` + cnNum + `)月(` + cnNum + `)[日号]`)
+	reISODate       = regexp.MustCompile(`\b(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+	reENRelativeDay = regexp.MustCompile(`(?i)\b(day\s+after\s+tomorrow|today|tonight|tomorrow|tmrw|tmr)\b`)
+	reENInDays      = regexp.MustCompile(`(?i)\bin\s+(\d+)\s+(days?|weeks?)\b`)
+	reENWeekday     = regexp.MustCompile(`(?i)\b(?:(next|this|on)\s+)?(monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`)
+	reENShortDay    = regexp.MustCompile(`(?i)\b(next|this|on)\s+(mon|tues?|wed|thu(?:rs?)?|fri|sat|sun)\b`)
+	reENMonthDay    = regexp.MustCompile(`(?i)\b(?:on\s+)?(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s*(\d{4}))?\b`)
+	reENDayMonth    = regexp.MustCompile(`(?i)\b(?:on\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\b(?:,?\s*(\d{4}))?`)
+
+	// 时长
+	reCNDuration    = regexp.MustCompile(`(?:持续|用时|时长|共)?\s*(?:(` + cnNum + `(?:\.\d+)?)\s*个?\s*(半)?|(半))\s*个?\s*(小时|钟头|分钟)`)
+	reENHourMinute  = regexp.MustCompile(`(?i)(?:\bfor\s+)?\b(\d+)\s*h\s*(\d+)\s*m(?:in)?s?\b`)
+	reENDuration    = regexp.MustCompile(`(?i)(?:\bfor\s+)?\b(\d+(?:\.\d+)?)\s*(hours?|hrs?|h|minutes?|mins?)\b`)
+	reENForDuration = regexp.MustCompile(`(?i)\bfor\s+(?:(an?|one)\s+hour|(half\s+an\s+hour|(\d+)\s*m))\b`)
+
+	// 相对当前时间的开始时间，如“2小时后”“in 30 minutes”
+	reCNTimeLater = regexp.MustCompile(`(?:(` + cnNum + `)\s*个?\s*(半)?|(半))\s*个?\s*(小时|钟头|分钟)(?:后|以后|之后)`)
+	reENInTime    = regexp.MustCompile(`(?i)\bin\s+(?:(\d+(?:\.\d+)?)\s*(hours?|hrs?|h|minutes?|mins?)|(an?)\s+(hour)|(half\s+an\s+hour))\b`)
+
+	// 时间
+	reCNTimeRange = regexp.MustCompile(`(` + cnClock + `)\s*(?:到|至|-|~|～|—)\s*(` + cnClockEnd + `)`)
+	reCNTime      = regexp.MustCompile(cnClock)
+	reCNClock     = regexp.MustCompile(`^(?:(凌晨|早上|早晨|上午|中午|午后|下午|傍晚|晚上|夜里)\s*)?(` + cnNum + `)\s*(?:点|時|时|:|：)\s*(?:(半)|(一刻)|(三刻)|(` + cnNum + `)\s*分?)?$`)
+	reENTimeRange = regexp.MustCompile(`(?i)(?:\bfrom\s+)?\b(\d{1,2}(?::\d{2})?\s*(?:am|pm|a\.m\.|p\.m\.)?|noon)\s*(?:-|–|to|until|till)\s*(` + enClock + `)`)
+	reENTime      = regexp.MustCompile(`(?i)(?:\bat\s+)?\b(` + enClock + `)`)
+	reENBareAt    = regexp.MustCompile(`(?i)\bat\s+(\d{1,2})\b`)
+	reENClock     = regexp.MustCompile(`(?i)^(\d{1,2})(?::(\d{2}))?\s*(am|pm|a\.m\.|p\.m\.)?$`)
+	reCNPeriod    = regexp.MustCompile(`(凌晨|早上|早晨|上午|中午|午后|下午|傍晚|晚上|夜里)`)
+	reENPeriod    = regexp.MustCompile(`(?i)\b(?:this\s+|in\s+the\s+)?(morning|afternoon|evening)\b`)
+
+	// 紧急程度
+	reCNUrgency   = regexp.MustCompile(`(?:(高|中|低)优先级|优先级\s*[:：]?\s*(高|中|低)|(非常紧急|十分紧急|特别紧急|不紧急|不急|紧急|加急|重要))`)
+	reENUrgency   = regexp.MustCompile(`(?i)(?:\b(high|medium|normal|low)\s+priority\b|\bpriority\s*[:=]?\s*(high|medium|normal|low)\b|\b(urgent|asap)\b|!(high|medium|low)\b|\bp([1-3])\b)`)
+	reBangUrgency = regexp.MustCompile(`(?:^|\s)(!{1,3})(?:\s|$)`)
+
+	// 标签
+	reHashtag = regexp.MustCompile(`[#＃]([\p{L}\p{N}_\-]+)`)
+
+	// 标题两端残留的连接词和标点
+	reTitleEdge = regexp.MustCompile(`(?i)^(?:[\s,，。.;；:：、\-–—]|\b(?:at|on|from|for|by|in)\b)+|(?:[\s,，。.;；:：、\-–—]|\b(?:at|on|from|for|by|in)\b)+$`)
+)
+
+// 时段对应的默认小时
+var cnPeriodHours = map[string]int{
+	"凌晨": 5, "早上": 8, "早晨": 8, "上午": 9, "中午": 12, "午后": 14,
+	"下午": 14, "傍晚": 18, "晚上": 20, "夜里": 22,
+}
+
+var enPeriodHours = map[string]int{"morning": 9, "afternoon": 14, "evening": 19}
+
+var cnWeekdays = map[string]time.Weekday{
+	"一": time.Monday, "二": time.Tuesday, "三": time.Wednesday, "四": time.Thursday,
+	"五": time.Friday, "六": time.Saturday, "日": time.Sunday, "天": time.Sunday,
+	"1": time.Monday, "2": time.Tuesday, "3": time.Wednesday, "4": time.Thursday,
+	"5": time.Friday, "6": time.Saturday, "7": time.Sunday,
+}
+
+// quickAddClock 解析出的钟点，meridiem为am/pm表示明确指定了上午或下午
+type quickAddClock struct {
+	hour     int
+	minute   int
+	meridiem string
+	padded   bool // 两位数小时（如09:00），视为24小时制
+}
+
+// quickAddParser 从一句话中依次识别并移除各类片段，剩下的文本作为标题
+type quickAddParser struct {
+	now   time.Time
+	loc   *time.Location
+	text  string
+	today time.Time
+
+	tokens   []QuickAddToken
+	warnings []string
+	hashtags []string
+
+	rule        *models.RecurrenceRule
+	date        time.Time
+	hasDate     bool
+	start       *quickAddClock
+	end         *quickAddClock
+	periodHour  int // 只给出时段时的默认小时，0表示没有
+	eveningHint bool
+	duration    time.Duration
+	urgency     string
+	inStart     time.Duration // 相对当前时间的开始时间，0表示没有
+}
+
+// parsedQuickAdd 快速添加的解析结果
+type parsedQuickAdd struct {
+	request  *CreateTodoRequest
+	tokens   []QuickAddToken
+	warnings []string
+	hashtags []string
+}
+
+// parseQuickAdd 解析一句话形式的待办，now为用户时区下的当前时间
+func parseQuickAdd(text string, now time.Time) (*parsedQuickAdd, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("内容不能为空")
+	}
+	p := &quickAddParser{now: now, loc: now.Location(), text: text}
+	p.today, _ = DayRange(now, p.loc)
+
+	p.parseTags()
+	p.parseUrgency()
+	p.parseRecurrence()
+	p.parseUntil()
+	p.parseDate()
+	p.parseRelativeTime()
+	p.parseDuration()
+	p.parseTime()
+
+	title := strings.Join(strings.Fields(p.text), " ")
+	for {
+		trimmed := reTitleEdge.ReplaceAllString(title, "")
+		if trimmed == title {
+			break
+		}
+		title = trimmed
+	}
+	if title == "" {
+		return nil, errors.New("未能识别出待办标题")
+	}
+
+	req := &CreateTodoRequest{
+		Title:       title,
+		Description: text,
+		Urgency:     p.urgency,
+	}
+	if req.Urgency == "" {
+		req.Urgency = "medium"
+	}
+	p.resolveTimes(req)
+	if p.rule != nil {
+		req.RRule = p.rule.String()
+		req.RepeatType = p.rule.RepeatType()
+	}
+
+	return &parsedQuickAdd{
+		request:  req,
+		tokens:   p.tokens,
+		warnings: p.warnings,
+		hashtags: p.hashtags,
+	}, nil
+}
+
+// consume 查找re的第一个匹配，fn返回true时记录片段并从文本中移除
+func (p *quickAddParser) consume(re *regexp.Regexp, kind string, fn func(m []string) bool) bool {
+	loc := re.FindStringSubmatchIndex(p.text)
+	if loc == nil {
+		return false
+	}
+	m := make([]string, len(loc)/2)
+	for i := range m {
+		if loc[2*i] >= 0 {
+			m[i] = p.text[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	if !fn(m) {
+		return false
+	}
+	p.tokens = append(p.tokens, QuickAddToken{Type: kind, Text: strings.TrimSpace(m[0])})
+	p.text = p.text[:loc[0]] + " " + p.text[loc[1]:]
+	return true
+}
+
+// parseTags 识别 #标签
+func (p *quickAddParser) parseTags() {
+	for p.consume(reHashtag, QuickAddTokenTag, func(m []string) bool {
+		p.hashtags = append(p.hashtags, m[1])
+		return true
+	}) {
+	}
+}
+
+// parseUrgency 识别紧急程度
+func (p *quickAddParser) parseUrgency() {
+	cn := map[string]string{
+		"高": "high", "中": "medium", "低": "low",
+		"非常紧急": "high", "十分紧急": "high", "特别紧急": "high", "紧急": "high", "加急": "high", "重要": "high",
+		"不紧急": "low", "不急": "low",
+	}
+	if p.consume(reCNUrgency, QuickAddTokenUrgency, func(m []string) bool {
+		p.urgency = cn[firstNonEmpty(m[1], m[2], m[3])]
+		return true
+	}) {
+		return
+	}
+
+	en := map[string]string{
+		"high": "high", "medium": "medium", "normal": "medium", "low": "low",
+		"urgent": "high", "asap": "high", "1": "high", "2": "medium", "3": "low",
+	}
+	if p.consume(reENUrgency, QuickAddTokenUrgency, func(m []string) bool {
+		p.urgency = en[strings.ToLower(firstNonEmpty(m[1], m[2], m[3], m[4], m[5]))]
+		return true
+	}) {
+		return
+	}
+	p.consume(reBangUrgency, QuickAddTokenUrgency, func(m []string) bool {
+		p.urgency = []string{"low", "medium", "high"}[len(m[1])-1]
+		return true
+	})
+}
+
+// parseRecurrence 识别重复规则
+func (p *quickAddParser) parseRecurrence() {
+	weekly := func(days ...time.Weekday) func(m []string) bool {
+		return func(m []string) bool {
+			p.rule = newQuickAddRule("WEEKLY", 1)
+			for _, day := range days {
+				p.rule.ByDay = append(p.rule.ByDay, models.WeekdayNum{Day: day})
+			}
+			return true
+		}
+	}
+	workdays := weekly(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	weekends := weekly(time.Saturday, time.Sunday)
+
+	switch {
+	case p.consume(reCNWorkday, QuickAddTokenRecurrence, workdays),
+		p.consume(reCNWeekend, QuickAddTokenRecurrence, weekends),
+		p.consume(reCNWeekly, QuickAddTokenRecurrence, func(m []string) bool {
+			p.rule = newQuickAddRule("WEEKLY", cnInterval(m[1], m[2]))
+			for _, r := range m[3] {
+				if day, ok := cnWeekdays[string(r)]; ok && !hasWeekday(p.rule.ByDay, day) {
+					p.rule.ByDay = append(p.rule.ByDay, models.WeekdayNum{Day: day})
+				}
+			}
+			return true
+		}),
+		p.consume(reCNMonthly, QuickAddTokenRecurrence, func(m []string) bool {
+			p.rule = newQuickAddRule("MONTHLY", cnInterval(m[1], m[2]))
+			if m[4] != "" {
+				p.rule.ByMonthDay = []int{-1}
+			} else if day := parseCNInt(m[3]); day >= 1 && day <= 31 {
+				p.rule.ByMonthDay = []int{day}
+			}
+			return true
+		}),
+		p.consume(reCNYearly, QuickAddTokenRecurrence, func(m []string) bool {
+			p.rule = newQuickAddRule("YEARLY", cnInterval(m[1], m[2]))
+			if m[3] != "" {
+				p.setMonthDay(p.today.Year(), parseCNInt(m[3]), parseCNInt(m[4]))
+			}
+			return true
+		}),
+		p.consume(reCNDaily, QuickAddTokenRecurrence, func(m []string) bool {
+			p.rule = newQuickAddRule("DAILY", cnInterval(m[1], m[2]))
+			return true
+		}),
+		p.consume(reENWorkday, QuickAddTokenRecurrence, workdays),
+		p.consume(reENWeekend, QuickAddTokenRecurrence, weekends),
+		p.consume(reENWeekdays, QuickAddTokenRecurrence, func(m []string) bool {
+			p.rule = newQuickAddRule("WEEKLY", 1)
+			for _, name := range regexp.MustCompile(`(?i)`+enWeekday).FindAllString(m[1], -1) {
+				if day, ok := parseENWeekday(name); ok && !hasWeekday(p.rule.ByDay, day) {
+					p.rule.ByDay = append(p.rule.ByDay, models.WeekdayNum{Day: day})
+				}
+			}
+			return true
+		}),
+		p.consume(reENEvery, QuickAddTokenRecurrence, func(m []string) bool {
+			interval := 1
+			if m[1] != "" {
+				interval = 2
+			} else if m[2] != "" {
+				interval, _ = strconv.Atoi(m[2])
+			}
+			freq := map[string]string{"day": "DAILY", "week": "WEEKLY", "month": "MONTHLY", "year": "YEARLY"}
+			p.rule = newQuickAddRule(freq[strings.ToLower(m[3])], interval)
+			return true
+		}),
+		p.consume(reENFrequency, QuickAddTokenRecurrence, func(m []string) bool {
+			freq := strings.ToUpper(m[1])
+			if freq == "ANNUALLY" {
+				freq = "YEARLY"
+			}
+			p.rule = newQuickAddRule(freq, 1)
+			return true
+		}):
+	}
+}
+
+// parseUntil 识别重复规则的截止日期，需在日期之前识别，避免“until friday”被当作开始日期
+func (p *quickAddParser) parseUntil() {
+	if p.rule == nil {
+		return
+	}
+	// untilDate 单独解析截止部分的文本，whole为true时要求整段都是日期，否则要求日期紧跟在关键词之后
+	untilDate := func(text string, whole bool) (time.Time, string, bool) {
+		sub := &quickAddParser{now: p.now, loc: p.loc, text: text, today: p.today}
+		sub.parseDate()
+		if !sub.hasDate || len(sub.tokens) == 0 {
+			return time.Time{}, "", false
+		}
+		token := sub.tokens[0].Text
+		if whole && strings.TrimSpace(sub.text) != "" {
+			return time.Time{}, "", false
+		}
+		if !whole && !strings.HasPrefix(strings.ToLower(strings.TrimSpace(text)), strings.ToLower(token)) {
+			return time.Time{}, "", false
+		}
+		return sub.date, token, true
+	}
+	setUntil := func(date time.Time) {
+		p.rule.Until = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	if loc := reENUntil.FindStringIndex(p.text); loc != nil {
+		rest := p.text[loc[1]:]
+		if date, token, ok := untilDate(rest, false); ok {
+			setUntil(date)
+			end := loc[1] + strings.Index(strings.ToLower(rest), strings.ToLower(token)) + len(token)
+			p.tokens = append(p.tokens, QuickAddToken{Type: QuickAddTokenRecurrence, Text: strings.TrimSpace(p.text[loc[0]:end])})
+			p.text = p.text[:loc[0]] + " " + p.text[end:]
+		}
+		return
+	}
+	p.consume(reCNUntil, QuickAddTokenRecurrence, func(m []string) bool {
+		date, _, ok := untilDate(firstNonEmpty(m[1], m[2]), true)
+		if ok {
+			setUntil(date)
+		}
+		return ok
+	})
+}
+
+// parseDate 识别日期
+func (p *quickAddParser) parseDate() {
+	cnOffsets := map[string]int{"今天": 0, "今日": 0, "今晚": 0, "明天": 1, "明日": 1, "后天": 2, "大后天": 3}
+	enOffsets := map[string]int{"today": 0, "tonight": 0, "tomorrow": 1, "tmrw": 1, "tmr": 1}
+
+	switch {
+	case p.consume(reCNRelativeDay, QuickAddTokenDate, func(m []string) bool {
+		p.setDate(p.today.AddDate(0, 0, cnOffsets[m[1]]))
+		p.eveningHint = m[1] == "今晚"
+		return true
+	}):
+	case p.consume(reCNDaysLater, QuickAddTokenDate, func(m []string) bool {
+		p.setDate(p.today.AddDate(0, 0, parseCNInt(m[1])))
+		return true
+	}):
+	case p.consume(reCNWeekday, QuickAddTokenDate, func(m []string) bool {
+		weeks := map[string]int{"下": 1, "下下": 2, "这": 0, "本": 0}
+		p.setWeekday(cnWeekdays[m[2]], m[1] != "", weeks[m[1]])
+		return true
+	}):
+	case p.consume(reCNMonthDay, QuickAddTokenDate, func(m []string) bool {
+		year, _ := strconv.Atoi(m[1])
+		return p.setMonthDay(year, parseCNInt(m[2]), parseCNInt(m[3]))
+	}):
+	case p.consume(reISODate, QuickAddTokenDate, func(m []string) bool {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return p.setMonthDay(year, month, day)
+	}):
+	case p.consume(reENRelativeDay, QuickAddTokenDate, func(m []string) bool {
+		word := strings.ToLower(m[1])
+		if strings.HasPrefix(word, "day") {
+			p.setDate(p.today.AddDate(0, 0, 2))
+			return true
+		}
+		p.setDate(p.today.AddDate(0, 0, enOffsets[word]))
+		p.eveningHint = word == "tonight"
+		return true
+	}):
+	case p.consume(reENInDays, QuickAddTokenDate, func(m []string) bool {
+		n, _ := strconv.Atoi(m[1])
+		if strings.HasPrefix(strings.ToLower(m[2]), "week") {
+			n *= 7
+		}
+		p.setDate(p.today.AddDate(0, 0, n))
+		return true
+	}):
+	case p.consume(reENMonthDay, QuickAddTokenDate, func(m []string) bool {
+		day, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		return p.setMonthDay(year, parseENMonth(m[1]), day)
+	}):
+	case p.consume(reENDayMonth, QuickAddTokenDate, func(m []string) bool {
+		day, _ := strconv.Atoi(m[1])
+		year, _ := strconv.Atoi(m[3])
+		return p.setMonthDay(year, parseENMonth(m[2]), day)
+	}):
+	case p.consume(reENWeekday, QuickAddTokenDate, p.enWeekdayDate),
+		p.consume(reENShortDay, QuickAddTokenDate, p.enWeekdayDate):
+	}
+}
+
+// enWeekdayDate 处理 next/this/on 加星期名称
+func (p *quickAddParser) enWeekdayDate(m []string) bool {
+	day, ok := parseENWeekday(m[2])
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(m[1]) {
+	case "next":
+		p.setWeekday(day, true, 1)
+	case "this":
+		p.setWeekday(day, true, 0)
+	default:
+		p.setWeekday(day, false, 0)
+	}
+	return true
+}
+
+// parseRelativeTime 识别相对当前时间的开始时间，需在时长之前识别，避免“in 2 hours”被当作时长
+func (p *quickAddParser) parseRelativeTime() {
+	switch {
+	case p.consume(reCNTimeLater, QuickAddTokenTime, func(m []string) bool {
+		amount := float64(parseCNInt(m[1]))
+		if m[2] != "" || m[3] != "" {
+			amount += 0.5
+		}
+		unit := time.Hour
+		if m[4] == "分钟" {
+			unit = time.Minute
+		}
+		p.inStart = time.Duration(amount * float64(unit))
+		return p.inStart > 0
+	}):
+	case p.consume(reENInTime, QuickAddTokenTime, func(m []string) bool {
+		switch {
+		case m[3] != "":
+			p.inStart = time.Hour
+		case m[5] != "":
+			p.inStart = 30 * time.Minute
+		default:
+			amount, _ := strconv.ParseFloat(m[1], 64)
+			unit := time.Hour
+			if strings.HasPrefix(strings.ToLower(m[2]), "m") {
+				unit = time.Minute
+			}
+			p.inStart = time.Duration(amount * float64(unit))
+		}
+		return p.inStart > 0
+	}):
+	}
+}
+
+// parseDuration 识别时长
+func (p *quickAddParser) parseDuration() {
+	switch {
+	case p.consume(reCNDuration, QuickAddTokenDuration, func(m []string) bool {
+		var amount float64
+		switch {
+		case m[3] != "":
+			amount = 0.5
+		case strings.Contains(m[1], "."):
+			amount, _ = strconv.ParseFloat(m[1], 64)
+		default:
+			amount = float64(parseCNInt(m[1]))
+		}
+		if m[2] != "" {
+			amount += 0.5
+		}
+		unit := time.Hour
+		if m[4] == "分钟" {
+			unit = time.Minute
+		}
+		p.duration = time.Duration(amount * float64(unit))
+		return p.duration > 0
+	}):
+	case p.consume(reENHourMinute, QuickAddTokenDuration, func(m []string) bool {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		p.duration = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+		return p.duration > 0
+	}):
+	case p.consume(reENForDuration, QuickAddTokenDuration, func(m []string) bool {
+		switch {
+		case m[1] != "":
+			p.duration = time.Hour
+		case m[3] != "":
+			minutes, _ := strconv.Atoi(m[3])
+			p.duration = time.Duration(minutes) * time.Minute
+		default:
+			p.duration = 30 * time.Minute
+		}
+		return p.duration > 0
+	}):
+	case p.consume(reENDuration, QuickAddTokenDuration, func(m []string) bool {
+		amount, _ := strconv.ParseFloat(m[1], 64)
+		unit := time.Hour
+		if strings.HasPrefix(strings.ToLower(m[2]), "m") {
+			unit = time.Minute
+		}
+		p.duration = time.Duration(amount * float64(unit))
+		return p.duration > 0
+	}):
+	}
+}
+
+// parseTime 识别时间或时间段，没有具体钟点时识别“下午”“evening”等时段
+func (p *quickAddParser) parseTime() {
+	switch {
+	case p.consume(reCNTimeRange, QuickAddTokenTime, func(m []string) bool {
+		start, ok1 := parseCNClock(m[1])
+		end, ok2 := parseCNClock(m[2])
+		if !ok1 || !ok2 {
+			return false
+		}
+		// “下午3点到5点”：结束时间沿用开始时间的时段
+		if end.meridiem == "" && start.meridiem == "pm" && end.hour < 12 {
+			end.hour += 12
+			end.meridiem = "pm"
+		}
+		p.start, p.end = start, end
+		return true
+	}):
+	case p.consume(reENTimeRange, QuickAddTokenTime, func(m []string) bool {
+		start, ok1 := parseENClock(m[1])
+		end, ok2 := parseENClock(m[2])
+		if !ok1 || !ok2 {
+			return false
+		}
+		// “3-5pm”：开始时间沿用结束时间的上午/下午
+		if start.meridiem == "" && end.meridiem == "pm" && start.hour+12 <= end.hour {
+			start.hour += 12
+			start.meridiem = "pm"
+		}
+		p.start, p.end = start, end
+		return true
+	}):
+	case p.consume(reCNTime, QuickAddTokenTime, func(m []string) bool {
+		clock, ok := parseCNClock(m[0])
+		return p.acceptStart(m[0], clock, ok)
+	}):
+	case p.consume(reENTime, QuickAddTokenTime, func(m []string) bool {
+		clock, ok := parseENClock(m[1])
+		return p.acceptStart(m[1], clock, ok)
+	}):
+	case p.consume(reENBareAt, QuickAddTokenTime, func(m []string) bool {
+		clock, ok := parseENClock(m[1])
+		return p.acceptStart(m[1], clock, ok)
+	}):
+	case p.consume(reCNPeriod, QuickAddTokenTime, func(m []string) bool {
+		p.periodHour = cnPeriodHours[m[1]]
+		return true
+	}):
+	case p.consume(reENPeriod, QuickAddTokenTime, func(m []string) bool {
+		p.periodHour = enPeriodHours[strings.ToLower(m[1])]
+		return true
+	}):
+	}
+}
+
+// acceptStart 钟点合法时设为开始时间，不合法（如25:00）时保留在标题中并给出提示
+func (p *quickAddParser) acceptStart(text string, clock *quickAddClock, ok bool) bool {
+	if !ok {
+		p.warnings = append(p.warnings, fmt.Sprintf("“%s”不是有效的时间，已忽略", strings.TrimSpace(text)))
+		return false
+	}
+	p.start = clock
+	return true
+}
+
+// resolveTimes 根据识别出的日期、钟点、时长和重复规则计算开始和结束时间
+func (p *quickAddParser) resolveTimes(req *CreateTodoRequest) {
+	if p.start == nil && p.periodHour == 0 && p.eveningHint {
+		p.periodHour = 20
+	}
+	if p.start != nil {
+		p.applyMeridiemDefault(p.start)
+		if p.end != nil {
+			p.applyMeridiemDefault(p.end)
+		}
+	}
+
+	if p.inStart > 0 {
+		start := p.now.Add(p.inStart).Truncate(time.Minute)
+		end := start.Add(quickAddDefaultDuration)
+		if p.duration > 0 {
+			end = start.Add(p.duration)
+		}
+		if p.hasDate || p.start != nil || p.periodHour > 0 {
+			p.warnings = append(p.warnings, "已按相对当前时间的开始时间安排，忽略其他日期和时间")
+		}
+		req.StartTime = start
+		req.EndTime = end
+		return
+	}
+
+	hasClock := p.start != nil || p.periodHour > 0 || p.duration > 0
+	date := p.today
+	if p.hasDate {
+		date = p.date
+	}
+
+	// 没有钟点的当天待办：覆盖全天
+	if !hasClock {
+		if !p.hasDate && p.rule != nil {
+			date = p.firstOccurrence(date, 0, 0)
+		}
+		req.StartTime = date
+		req.EndTime = date.AddDate(0, 0, 1).Add(-time.Second)
+		return
+	}
+
+	hour, minute := quickAddDefaultHour, 0
+	switch {
+	case p.start != nil:
+		hour, minute = p.start.hour, p.start.minute
+	case p.periodHour > 0:
+		hour = p.periodHour
+	}
+
+	if !p.hasDate {
+		if p.rule != nil {
+			date = p.firstOccurrence(date, hour, minute)
+		} else if atClock(date, hour, minute).Before(p.now) {
+			date = date.AddDate(0, 0, 1)
+			p.warnings = append(p.warnings, "今天的这个时间已过，已安排到明天")
+		}
+	}
+
+	start := atClock(date, hour, minute)
+	end := start.Add(quickAddDefaultDuration)
+	switch {
+	case p.end != nil:
+		end = atClock(date, p.end.hour, p.end.minute)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+	case p.duration > 0:
+		end = start.Add(p.duration)
+	}
+	req.StartTime = start
+	req.EndTime = end
+}
+
+// applyMeridiemDefault 没有指明上午下午的1~6点按下午处理，并给出提示
+func (p *quickAddParser) applyMeridiemDefault(c *quickAddClock) {
+	if c.meridiem != "" || c.padded {
+		return
+	}
+	if p.eveningHint && c.hour < 12 {
+		c.hour += 12
+		c.meridiem = "pm"
+		return
+	}
+	if c.hour >= 1 && c.hour <= 6 {
+		c.hour += 12
+		c.meridiem = "pm"
+		p.warnings = append(p.warnings, fmt.Sprintf("未指明上午或下午，%d点按下午%d点处理", c.hour-12, c.hour-12))
+	}
+}
+
+// firstOccurrence 从from起找到符合重复规则的第一天，且该天的开始时间晚于当前时间
+func (p *quickAddParser) firstOccurrence(from time.Time, hour, minute int) time.Time {
+	for i := 0; i < 400; i++ {
+		day := from.AddDate(0, 0, i)
+		if !p.matchesRule(day) {
+			continue
+		}
+		if i == 0 && (hour != 0 || minute != 0) && atClock(day, hour, minute).Before(p.now) {
+			continue
+		}
+		return day
+	}
+	return from
+}
+
+// matchesRule 判断某天是否符合重复规则的BYDAY和BYMONTHDAY限制
+func (p *quickAddParser) matchesRule(day time.Time) bool {
+	if len(p.rule.ByDay) > 0 && !hasWeekday(p.rule.ByDay, day.Weekday()) {
+		return false
+	}
+	for _, monthDay := range p.rule.ByMonthDay {
+		if monthDay == -1 {
+			return day.AddDate(0, 0, 1).Day() == 1
+		}
+		return day.Day() == monthDay
+	}
+	return true
+}
+
+// setDate 设置日期
+func (p *quickAddParser) setDate(date time.Time) {
+	p.date = date
+	p.hasDate = true
+}
+
+// setWeekday 设置星期：explicit为false时取今天起的下一个该星期，否则取今天所在周之后第weeks周的该星期
+func (p *quickAddParser) setWeekday(day time.Weekday, explicit bool, weeks int) {
+	if !explicit {
+		diff := (int(day) - int(p.today.Weekday()) + 7) % 7
+		p.setDate(p.today.AddDate(0, 0, diff))
+		return
+	}
+	// 以解析时的当前日期计算本周周一
+	monday := p.today.AddDate(0, 0, -((int(p.today.Weekday()) + 6) % 7))
+	offset := (int(day) + 6) % 7 // 周一为0
+	p.setDate(monday.AddDate(0, 0, 7*weeks+offset))
+}
+
+// setMonthDay 设置月日，未给出年份且日期已过时取明年
+func (p *quickAddParser) setMonthDay(year, month, day int) bool {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return false
+	}
+	explicitYear := year > 0
+	if !explicitYear {
+		year = p.today.Year()
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.loc)
+	if date.Month() != time.Month(month) {
+		return false
+	}
+	if !explicitYear && date.Before(p.today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	p.setDate(date)
+	return true
+}
+
+// newQuickAddRule 创建重复规则
+func newQuickAddRule(freq string, interval int) *models.RecurrenceRule {
+	if interval < 1 {
+		interval = 1
+	}
+	return &models.RecurrenceRule{Freq: freq, Interval: interval, WeekStart: time.Monday}
+}
+
+// cnInterval 换算中文重复间隔：“每两周”为2，“每隔一周”为2
+func cnInterval(every, number string) int {
+	n := 1
+	if number != "" {
+		n = parseCNInt(number)
+	}
+	if every != "" {
+		n++
+	}
+	return n
+}
+
+// hasWeekday 判断BYDAY中是否包含某个星期
+func hasWeekday(days []models.WeekdayNum, day time.Weekday) bool {
+	for _, wd := range days {
+		if wd.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCNClock 解析中文钟点，如“下午3点半”“15:30”“十点十五分”
+func parseCNClock(value string) (*quickAddClock, bool) {
+	m := reCNClock.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return nil, false
+	}
+	clock := &quickAddClock{hour: parseCNInt(m[2])}
+	clock.padded = len(m[2]) == 2 && m[2][0] == '0'
+	switch {
+	case m[3] != "":
+		clock.minute = 30
+	case m[4] != "":
+		clock.minute = 15
+	case m[5] != "":
+		clock.minute = 45
+	case m[6] != "":
+		clock.minute = parseCNInt(m[6])
+	}
+	switch m[1] {
+	case "":
+	case "凌晨", "早上", "早晨", "上午":
+		clock.meridiem = "am"
+		if clock.hour == 12 {
+			clock.hour = 0
+		}
+	case "中午":
+		clock.meridiem = "pm"
+		if clock.hour < 6 {
+			clock.hour += 12
+		}
+	default:
+		clock.meridiem = "pm"
+		if clock.hour < 12 {
+			clock.hour += 12
+		}
+	}
+	if clock.hour > 12 {
+		clock.meridiem = "pm"
+	}
+	if clock.hour == 0 && clock.meridiem == "" {
+		clock.padded = true
+	}
+	return clock, clock.hour <= 24 && clock.minute < 60
+}
+
+// parseENClock 解析英文钟点，如“10am”“3:30 pm”“14:00”“noon”
+func parseENClock(value string) (*quickAddClock, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "noon":
+		return &quickAddClock{hour: 12, meridiem: "pm"}, true
+	case "midnight":
+		return &quickAddClock{hour: 0, meridiem: "am"}, true
+	}
+	m := reENClock.FindStringSubmatch(value)
+	if m == nil {
+		return nil, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	clock := &quickAddClock{hour: hour, minute: minute, padded: len(m[1]) == 2 && m[1][0] == '0'}
+	switch strings.ReplaceAll(m[3], ".", "") {
+	case "am":
+		clock.meridiem = "am"
+		if hour == 12 {
+			clock.hour = 0
+		}
+	case "pm":
+		clock.meridiem = "pm"
+		if hour < 12 {
+			clock.hour += 12
+		}
+	}
+	if clock.hour > 12 {
+		clock.meridiem = "pm"
+	}
+	if clock.hour == 0 && clock.meridiem == "" {
+		clock.padded = true
+	}
+	return clock, clock.hour < 24 && clock.minute < 60
+}
+
+// parseENWeekday 解析英文星期名称或缩写
+func parseENWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(value)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), value[:3]) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// parseENMonth 解析英文月份名称或缩写
+func parseENMonth(value string) int {
+	value = strings.ToLower(value)
+	for month := time.January; month <= time.December; month++ {
+		if strings.HasPrefix(strings.ToLower(month.String()), value[:3]) {
+			return int(month)
+		}
+	}
+	return 0
+}
+
+// parseCNInt 解析阿拉伯数字或不超过两位的中文数字，如“3”“十五”“二十三”“两”
+func parseCNInt(value string) int {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	digits := map[rune]int{'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+	runes := []rune(value)
+	for i, r := range runes {
+		if r != '十' {
+			continue
+		}
+		tens, ones := 1, 0
+		if i > 0 {
+			tens = digits[runes[i-1]]
+		}
+		if i+1 < len(runes) {
+			ones = digits[runes[i+1]]
+		}
+		return tens*10 + ones
+	}
+	n := 0
+	for _, r := range runes {
+		d, ok := digits[r]
+		if !ok {
+			if r >= '0' && r <= '9' {
+				d = int(r - '0')
+			} else {
+				return 0
+			}
+		}
+		n = n*10 + d
+	}
+	return n
+}
+
+// atClock 返回某天的指定钟点，小时为24时表示次日零点
+func atClock(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
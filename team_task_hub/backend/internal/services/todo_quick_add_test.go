@@ -0,0 +1,184 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-01-17 周三 10:00
+	now := time.Date(2024, 1, 17, 10, 0, 0, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		text     string
+		title    string
+		start    time.Time
+		end      time.Time
+		urgency  string
+		rrule    string
+		hashtags []string
+	}{
+		{
+			text:    "每周三下午3点 小组会议 高优先级",
+			title:   "小组会议",
+			start:   at(17, 15, 0),
+			end:     at(17, 16, 0),
+			urgency: "high",
+			rrule:   "FREQ=WEEKLY;BYDAY=WE",
+		},
+		{
+			text:     "review PR tomorrow 10am for 1h #work",
+			title:    "review PR",
+			start:    at(18, 10, 0),
+			end:      at(18, 11, 0),
+			urgency:  "medium",
+			hashtags: []string{"work"},
+		},
+		{
+			text:    "dinner 7:30pm",
+			title:   "dinner",
+			start:   at(17, 19, 30),
+			end:     at(17, 20, 30),
+			urgency: "medium",
+		},
+		{
+			// 今天的9:30已过，顺延到明天
+			text:    "standup 9:30am",
+			title:   "standup",
+			start:   at(18, 9, 30),
+			end:     at(18, 10, 30),
+			urgency: "medium",
+		},
+		{
+			text:    "standup every weekday 9:30 am",
+			title:   "standup",
+			start:   at(18, 9, 30),
+			end:     at(18, 10, 30),
+			urgency: "medium",
+			rrule:   "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		},
+		{
+			text:    "call mom in 2 hours",
+			title:   "call mom",
+			start:   at(17, 12, 0),
+			end:     at(17, 13, 0),
+			urgency: "medium",
+		},
+		{
+			text:    "write report in 30 minutes for 2h",
+			title:   "write report",
+			start:   at(17, 10, 30),
+			end:     at(17, 12, 30),
+			urgency: "medium",
+		},
+		{
+			text:    "两小时后 给妈妈打电话",
+			title:   "给妈妈打电话",
+			start:   at(17, 12, 0),
+			end:     at(17, 13, 0),
+			urgency: "medium",
+		},
+		{
+			text:    "明天下午3:30到5:00 评审会",
+			title:   "评审会",
+			start:   at(18, 15, 30),
+			end:     at(18, 17, 0),
+			urgency: "medium",
+		},
+		{
+			text:    "会议 15:30",
+			title:   "会议",
+			start:   at(17, 15, 30),
+			end:     at(17, 16, 30),
+			urgency: "medium",
+		},
+		{
+			text:    "sync 3-5pm next monday",
+			title:   "sync",
+			start:   at(22, 15, 0),
+			end:     at(22, 17, 0),
+			urgency: "medium",
+		},
+		{
+			text:    "体检 1月20号 上午8点 持续两个小时",
+			title:   "体检",
+			start:   at(20, 8, 0),
+			end:     at(20, 10, 0),
+			urgency: "medium",
+		},
+		{
+			text:    "standup every day until friday",
+			title:   "standup",
+			start:   at(17, 0, 0),
+			end:     at(18, 0, 0).Add(-time.Second),
+			urgency: "medium",
+			rrule:   "FREQ=DAILY;UNTIL=20240119T155959Z",
+		},
+		{
+			text:    "每天早上9点 写日报 到1月20日为止",
+			title:   "写日报",
+			start:   at(18, 9, 0),
+			end:     at(18, 10, 0),
+			urgency: "medium",
+			rrule:   "FREQ=DAILY;UNTIL=20240120T155959Z",
+		},
+		{
+			// 无效的钟点不设置开始时间，保留在标题中
+			text:    "meeting 25:00",
+			title:   "meeting 25:00",
+			start:   at(17, 0, 0),
+			end:     at(18, 0, 0).Add(-time.Second),
+			urgency: "medium",
+		},
+		{
+			// 没有钟点时覆盖全天
+			text:    "交房租 后天",
+			title:   "交房租",
+			start:   at(19, 0, 0),
+			end:     at(20, 0, 0).Add(-time.Second),
+			urgency: "medium",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			parsed, err := parseQuickAdd(tt.text, now)
+			if err != nil {
+				t.Fatalf("parseQuickAdd(%q) error: %v", tt.text, err)
+			}
+			req := parsed.request
+			if req.Title != tt.title {
+				t.Errorf("title = %q, want %q", req.Title, tt.title)
+			}
+			if !req.StartTime.Equal(tt.start) {
+				t.Errorf("start = %v, want %v", req.StartTime, tt.start)
+			}
+			if !req.EndTime.Equal(tt.end) {
+				t.Errorf("end = %v, want %v", req.EndTime, tt.end)
+			}
+			if req.Urgency != tt.urgency {
+				t.Errorf("urgency = %q, want %q", req.Urgency, tt.urgency)
+			}
+			if req.RRule != tt.rrule {
+				t.Errorf("rrule = %q, want %q", req.RRule, tt.rrule)
+			}
+			if !slices.Equal(parsed.hashtags, tt.hashtags) {
+				t.Errorf("hashtags = %v, want %v", parsed.hashtags, tt.hashtags)
+			}
+		})
+	}
+}
+
+func TestParseQuickAddEmptyTitle(t *testing.T) {
+	now := time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC)
+	for _, text := range []string{"", "   ", "tomorrow 10am"} {
+		if _, err := parseQuickAdd(text, now); err == nil {
+			t.Errorf("parseQuickAdd(%q) expected error", text)
+		}
+	}
+}
@@ -12,15 +12,54 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// recurrenceWindow 重复待办预先生成实例的时间窗口，与续期查询的窗口保持一致
+	recurrenceWindow = 7 * 24 * time.Hour
+	// maxGeneratedChildren 根据重复规则一次最多生成的实例数量
+	maxGeneratedChildren = 100
+)
+
+// 待办相关的通用错误，处理器据此映射HTTP状态码
+var (
+	ErrTodoNotFound      = errors.New("待办不存在")
+	ErrTodoForbidden     = errors.New("无权操作该待办")
+	ErrInvalidTodoParams = errors.New("请求参数错误")
+)
+
 type TodoService struct {
-	todoRepo     *repositories.TodoRepository
-	activityRepo *repositories.ActivityRepository
+	todoRepo       *repositories.TodoRepository
+	activityRepo   *repositories.ActivityRepository
+	categoryRepo   *repositories.CategoryRepository
+	tagRepo        *repositories.TagRepository
+	checklistRepo  *repositories.ChecklistRepository
+	dependencyRepo *repositories.DependencyRepository
+	historyRepo    *repositories.TodoHistoryRepository
+	userRepo       *repositories.UserRepository
+	boardRepo      *repositories.BoardRepository
+	preferenceRepo *repositories.SchedulePreferenceRepository
+	reminderRepo   *repositories.ReminderRepository
+	timeEntryRepo  *repositories.TimeEntryRepository
+
+	notificationService *NotificationService
+	conflictService     *ConflictService
 }
 
-func NewTodoService(todoRepo *repositories.TodoRepository, activityRepo *repositories.ActivityRepository) *TodoService {
+func NewTodoService(todoRepo *repositories.TodoRepository, activityRepo *repositories.ActivityRepository, categoryRepo *repositories.CategoryRepository, tagRepo *repositories.TagRepository, checklistRepo *repositories.ChecklistRepository, dependencyRepo *repositories.DependencyRepository, historyRepo *repositories.TodoHistoryRepository, userRepo *repositories.UserRepository, boardRepo *repositories.BoardRepository, preferenceRepo *repositories.SchedulePreferenceRepository, reminderRepo *repositories.ReminderRepository, timeEntryRepo *repositories.TimeEntryRepository, notificationService *NotificationService, conflictService *ConflictService) *TodoService {
 	return &TodoService{
-		todoRepo:     todoRepo,
-		activityRepo: activityRepo,
+		todoRepo:            todoRepo,
+		activityRepo:        activityRepo,
+		categoryRepo:        categoryRepo,
+		tagRepo:             tagRepo,
+		checklistRepo:       checklistRepo,
+		dependencyRepo:      dependencyRepo,
+		historyRepo:         historyRepo,
+		userRepo:            userRepo,
+		boardRepo:           boardRepo,
+		preferenceRepo:      preferenceRepo,
+		reminderRepo:        reminderRepo,
+		timeEntryRepo:       timeEntryRepo,
+		notificationService: notificationService,
+		conflictService:     conflictService,
 	}
 }
 
@@ -38,28 +77,37 @@ func (s *TodoService) CancelTodoAndChildren(userID uint, title, description stri
 		}
 
 		// 更新根待办状态
-		if err := s.todoRepo.UpdateStatusWithTx(tx, rootTodo.ID, "cancelled"); err != nil {
+		updates, err := todoTransitionUpdates(rootTodo, TodoStatusCancelled, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := s.todoRepo.UpdateWithTx(tx, rootTodo.ID, updates); err != nil {
 			return fmt.Errorf("更新根待办状态失败: %v", err)
 		}
+		recorder := &todoChangeRecorder{}
+		recorder.record(rootTodo, updates)
 
-		// 如果有子节点，批量更新
+		// 如果有子节点，批量取消未完成的实例
 		if rootTodo.HasChildren {
-			rowsAffected, err := s.todoRepo.BatchUpdateChildrenStatusWithTx(tx, rootTodo.ID, "pending", "cancelled")
+			rowsAffected, err := s.cancelChildrenWithTx(tx, rootTodo.ID, recorder)
 			if err != nil {
-				return fmt.Errorf("批量更新子待办失败: %v", err)
+				return err
 			}
 			fmt.Printf("在事务中成功取消 %d 个子待办\n", rowsAffected)
 		}
-		return nil
+
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: rootTodo.CreatorUserID,
+			ActorID: userID,
+			TodoID:  rootTodo.ID,
+			Action:  models.TodoActionCancelSeries,
+		}, recorder)
 	})
 }
 
 // CancelTodoByDetails 根据待办详情取消待办
 func (s *TodoService) CancelTodoByDetails(userID uint, title, description string, startTime, endTime time.Time) (bool, string) {
-	err := s.todoRepo.UpdateTodoStateByDetails(
-		userID, title, description, startTime, endTime, "cancelled",
-	)
-
+	_, err := s.transitionTodoByDetails(userID, title, description, startTime, endTime, TodoStatusCancelled)
 	if err != nil {
 		return false, fmt.Sprintf("取消待办失败: %v", err)
 	}
@@ -69,33 +117,91 @@ func (s *TodoService) CancelTodoByDetails(userID uint, title, description string
 
 // CreateTodoRequest 创建待办请求
 type CreateTodoRequest struct {
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description,omitempty"`
-	StartTime   time.Time `json:"start_time" binding:"required"`
-	EndTime     time.Time `json:"end_time" binding:"required"`
-	Urgency     string    `json:"urgency" binding:"oneof=low medium high" default:"medium"`
-	Category    string    `json:"category" binding:"oneof=work study fun personal" default:"personal"`
+	Title        string    `json:"title" binding:"required"`
+	Description  string    `json:"description,omitempty"`
+	StartTime    time.Time `json:"start_time" binding:"required"`
+	EndTime      time.Time `json:"end_time" binding:"required"`
+	Urgency      string    `json:"urgency" binding:"oneof=low medium high" default:"medium"`
+	Category     string    `json:"category" binding:"omitempty,max=50" default:"personal"`              // 用户分类名称
+	Tags         []string  `json:"tags,omitempty" example:"会议"`                                         // 标签名称，不存在的标签会自动创建
+	Checklist    []string  `json:"checklist,omitempty" binding:"omitempty,dive,max=200" example:"准备材料"` // 清单项标题，按顺序创建
+	AutoComplete bool      `json:"auto_complete" example:"false"`                                       // 清单全部勾选后自动完成
+	Timezone     string    `json:"timezone,omitempty" example:"Asia/Shanghai"`                          // IANA时区，默认使用用户设置的时区
+
+	// 与已有待办或活动时间重叠时的处理：warn 返回冲突并继续创建（默认），block 不创建
+	ConflictPolicy string `json:"conflict_policy,omitempty" binding:"omitempty,oneof=warn block" example:"warn"`
 
 	// 重复规则
-	RepeatType     string    `json:"repeat_type" binding:"oneof=none daily weekly monthly" default:"none"`
+	RepeatType     string    `json:"repeat_type" binding:"omitempty,oneof=none daily weekly monthly yearly" default:"none"`
 	RepeatInterval int       `json:"repeat_interval" default:"1"`
 	RepeatEndDate  time.Time `json:"repeat_end_date"`
+	RRule          string    `json:"rrule,omitempty" example:"FREQ=MONTHLY;BYDAY=2TU"` // RFC 5545 重复规则，优先于 repeat_type
+	ExDates        []string  `json:"exdates,omitempty" example:"2024-02-13"`           // 需要跳过的日期 YYYY-MM-DD
 
-	// 子待办日期数组
+	// 子待办日期数组（可选，未提供时由服务端根据重复规则展开）
 	ChildDates []string `json:"child_dates"`
 }
 
 // CreateTodoResponse 创建待办响应
 type CreateTodoResponse struct {
-	Success   bool      `json:"success"`
-	Message   string    `json:"message"`
-	CreatedAt time.Time `json:"created_at"`
+	Success   bool               `json:"success"`
+	Message   string             `json:"message"`
+	TodoID    uint               `json:"todo_id,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	Conflicts []ScheduleConflict `json:"conflicts,omitempty"` // 与首次出现时间重叠的待办和活动
 }
 
 // CreateTodo 创建待办（支持普通和重复待办）
 func (s *TodoService) CreateTodo(userID uint, req *CreateTodoRequest) (*CreateTodoResponse, error) {
 	// 验证请求参数
+	if err := s.resolveTodoTimezone(userID, req); err != nil {
+		return &CreateTodoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
 	if err := s.validateCreateRequest(req); err != nil {
+		return &CreateTodoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+	}
+
+	// 解析重复规则
+	rule, err := s.resolveRecurrenceRule(req)
+	if err != nil {
+		return &CreateTodoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	// 检查时间冲突，重复待办只检查首次出现
+	conflicts, err := s.conflictService.FindConflicts(userID, req.StartTime, req.EndTime, 0)
+	if err != nil {
+		return &CreateTodoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	if len(conflicts) > 0 && blocksOnConflict(req.ConflictPolicy) {
+		return &CreateTodoResponse{
+			Success:   false,
+			Message:   fmt.Sprintf("与 %d 项已有日程时间冲突，未创建待办", len(conflicts)),
+			Conflicts: conflicts,
+		}, ErrScheduleConflict
+	}
+
+	// 解析分类和标签
+	category, err := s.resolveCategory(userID, req.Category)
+	if err != nil {
+		return &CreateTodoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	tagIDs, err := s.resolveTagIDs(userID, req.Tags)
+	if err != nil {
 		return &CreateTodoResponse{
 			Success: false,
 			Message: err.Error(),
@@ -103,105 +209,168 @@ func (s *TodoService) CreateTodo(userID uint, req *CreateTodoRequest) (*CreateTo
 	}
 
 	// 判断是普通待办还是重复待办
-	if req.RepeatType == "none" || len(req.ChildDates) == 0 {
-		return s.createSingleTodo(userID, req)
+	var resp *CreateTodoResponse
+	if rule == nil {
+		resp, err = s.createSingleTodo(userID, req, category, tagIDs)
 	} else {
-		return s.createRepeatingTodo(userID, req)
+		resp, err = s.createRepeatingTodo(userID, req, rule, category, tagIDs)
+	}
+	if err == nil {
+		invalidateTodoStats(userID)
+		resp.Conflicts = conflicts
+		s.replanAfterChange(userID)
 	}
+	return resp, err
 }
 
-// createSingleTodo 创建普通待办
-func (s *TodoService) createSingleTodo(userID uint, req *CreateTodoRequest) (*CreateTodoResponse, error) {
-	todo := &models.Todo{
-		Title:         req.Title,
-		Description:   req.Description,
-		StartTime:     req.StartTime,
-		EndTime:       req.EndTime,
-		Urgency:       req.Urgency,
-		Category:      req.Category,
-		Status:        "pending",
-		ParentID:      0,
-		HasChildren:   false,
-		RepeatType:    "none",
-		CreatorUserID: userID,
+// CheckConflicts 查找与[start, end)重叠的未完成待办和活动
+func (s *TodoService) CheckConflicts(userID uint, start, end time.Time) ([]ScheduleConflict, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidTodoParams)
+	}
+	return s.conflictService.FindConflicts(userID, start, end, 0)
+}
+
+// resolveRecurrenceRule 根据请求得到重复规则：优先使用RRULE，其次由旧的重复类型换算，非重复待办返回nil
+func (s *TodoService) resolveRecurrenceRule(req *CreateTodoRequest) (*models.RecurrenceRule, error) {
+	var rule *models.RecurrenceRule
+	var err error
+
+	switch {
+	case strings.TrimSpace(req.RRule) != "":
+		rule, err = models.ParseRRule(req.RRule, models.LoadLocation(req.Timezone))
+	case req.RepeatType != "" && req.RepeatType != "none":
+		rule, err = models.NewSimpleRule(req.RepeatType, req.RepeatInterval, time.Time{})
+	default:
+		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: 重复规则无效: %v", ErrInvalidTodoParams, err)
+	}
+
+	// COUNT换算为UNTIL，续期时任何实例都能独立推算后续时间；按待办所属时区展开
+	rule.ResolveCount(req.StartTime.In(models.LoadLocation(req.Timezone)))
+	return rule, nil
+}
+
+// normalizeExDates 校验并拼接排除日期
+func normalizeExDates(dates []string) (string, error) {
+	normalized := make([]string, 0, len(dates))
+	for _, dateStr := range dates {
+		date, err := ParseDateString(dateStr)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidTodoParams, err)
+		}
+		normalized = append(normalized, date.Format("2006-01-02"))
+	}
+	return strings.Join(normalized, ","), nil
+}
 
-	if err := s.todoRepo.Create(todo); err != nil {
+// createSingleTodo 创建普通待办，待办、标签和清单在同一事务中写入
+func (s *TodoService) createSingleTodo(userID uint, req *CreateTodoRequest, category *models.Category, tagIDs []uint) (*CreateTodoResponse, error) {
+	todo := newSingleTodo(userID, req, category)
+
+	err := s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.todoRepo.CreateWithTx(tx, todo); err != nil {
+			return fmt.Errorf("创建待办失败: %v", err)
+		}
+		if err := s.tagRepo.AddTodoTagsWithTx(tx, []uint{todo.ID}, tagIDs); err != nil {
+			return fmt.Errorf("设置待办标签失败: %v", err)
+		}
+		if err := s.checklistRepo.BatchCreateWithTx(tx, newChecklistItems([]uint{todo.ID}, req.Checklist)); err != nil {
+			return fmt.Errorf("创建待办清单失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return &CreateTodoResponse{
 			Success: false,
-			Message: "创建待办失败: " + err.Error(),
+			Message: err.Error(),
 		}, err
 	}
 
 	return &CreateTodoResponse{
 		Success:   true,
 		Message:   "待办创建成功",
+		TodoID:    todo.ID,
 		CreatedAt: time.Now(),
 	}, nil
 }
 
-// createRepeatingTodo 创建重复待办
-func (s *TodoService) createRepeatingTodo(userID uint, req *CreateTodoRequest) (*CreateTodoResponse, error) {
+// createRepeatingTodo 创建重复待办，抽象待办、实例、标签和清单在同一事务中写入
+func (s *TodoService) createRepeatingTodo(userID uint, req *CreateTodoRequest, rule *models.RecurrenceRule, category *models.Category, tagIDs []uint) (*CreateTodoResponse, error) {
 	response := &CreateTodoResponse{
 		Success:   false,
 		CreatedAt: time.Now(),
 	}
 
-	// 创建父待办,作为抽象待办存在，产生实例待办
-	parentTodo := &models.Todo{
-		Title:          req.Title,
-		Description:    req.Description,
-		StartTime:      req.StartTime,
-		EndTime:        req.EndTime,
-		Urgency:        req.Urgency,
-		Category:       req.Category,
-		Status:         "pending",
-		ParentID:       0,
-		HasChildren:    true,
-		RepeatType:     req.RepeatType,
-		RepeatInterval: req.RepeatInterval,
-		RepeatEndDate:  req.RepeatEndDate,
-		CreatorUserID:  userID,
-	}
-
-	if err := s.todoRepo.Create(parentTodo); err != nil {
-		response.Message = "创建父待办失败: " + err.Error()
+	exDates, err := normalizeExDates(req.ExDates)
+	if err != nil {
+		response.Message = err.Error()
 		return response, err
 	}
 
-	// 生成子待办实例
-	childTodos, err := s.generateChildTodos(parentTodo, req)
+	// 创建父待办,作为抽象待办存在，产生实例待办
+	parentTodo := newRootTodo(userID, req, rule, category, exDates)
+
+	var childTodos []models.Todo
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.todoRepo.CreateWithTx(tx, parentTodo); err != nil {
+			return fmt.Errorf("创建父待办失败: %v", err)
+		}
+
+		// 生成并批量创建子待办实例
+		children, err := s.generateChildTodos(parentTodo, req)
+		if err != nil {
+			return fmt.Errorf("生成子待办失败: %v", err)
+		}
+		if len(children) > 0 {
+			if err := s.todoRepo.BatchCreateWithTx(tx, children); err != nil {
+				return fmt.Errorf("创建子待办失败: %v", err)
+			}
+		}
+
+		// 抽象待办及其实例使用相同的标签
+		todoIDs := []uint{parentTodo.ID}
+		for _, child := range children {
+			todoIDs = append(todoIDs, child.ID)
+		}
+		if err := s.tagRepo.AddTodoTagsWithTx(tx, todoIDs, tagIDs); err != nil {
+			return fmt.Errorf("设置待办标签失败: %v", err)
+		}
+
+		// 清单只属于具体实例，抽象待办不持有清单
+		if err := s.checklistRepo.BatchCreateWithTx(tx, newChecklistItems(todoIDs[1:], req.Checklist)); err != nil {
+			return fmt.Errorf("创建待办清单失败: %v", err)
+		}
+		childTodos = children
+		return nil
+	})
 	if err != nil {
-		response.Message = "生成子待办失败: " + err.Error()
+		response.Message = err.Error()
 		return response, err
 	}
 
-	// 批量创建子待办
+	response.TodoID = parentTodo.ID
 	if len(childTodos) > 0 {
-		if err := s.todoRepo.BatchCreate(childTodos); err != nil {
-			response.Message = "创建子待办失败: " + err.Error()
-			return response, err
-		}
-
 		response.Message = fmt.Sprintf("重复待办创建成功，生成 %d 个子实例", len(childTodos))
 	} else {
 		response.Message = "重复待办创建成功，但未生成子实例"
 	}
-
 	response.Success = true
 	return response, nil
 }
 
-// generateChildTodos 根据前端提供的日期数组生成子待办
+// generateChildTodos 生成子待办：优先使用前端提供的日期数组，未提供时根据重复规则展开
 func (s *TodoService) generateChildTodos(parent *models.Todo, req *CreateTodoRequest) ([]models.Todo, error) {
 	var childTodos []models.Todo
 
 	if len(req.ChildDates) == 0 {
-		return childTodos, nil
+		return s.expandChildTodos(parent)
 	}
 
-	// 解析父待办的时间部分
-	parentStartTime := parent.StartTime
+	// 解析父待办在其时区下的时间部分
+	parentStartTime := parent.LocalStartTime()
 	parentEndTime := parent.EndTime
 
 	// 提取父待办的时间部分
@@ -242,29 +411,127 @@ func (s *TodoService) generateChildTodos(parent *models.Todo, req *CreateTodoReq
 		}
 
 		// 创建子待办实例
-		childTodo := models.Todo{
-			Title:          parent.Title,
-			Description:    parent.Description,
-			StartTime:      childStartTime,
-			EndTime:        childEndTime,
-			CreatedAt:      parent.CreatedAt,
-			Urgency:        parent.Urgency,
-			Category:       parent.Category,
-			Status:         "pending",
-			ParentID:       parent.ID,
-			HasChildren:    false,
-			RepeatType:     parent.RepeatType,
-			RepeatInterval: parent.RepeatInterval,
-			RepeatEndDate:  parent.RepeatEndDate,
-			CreatorUserID:  parent.CreatorUserID,
-		}
-		childTodos = append(childTodos, childTodo)
+		childTodos = append(childTodos, newChildTodo(parent, childStartTime, childEndTime))
 	}
 
 	log.Printf("为父待办 %d 生成 %d 个子待办实例", parent.ID, len(childTodos))
 	return childTodos, nil
 }
 
+// expandChildTodos 根据抽象待办的重复规则展开时间窗口内的实例，至少生成第一个实例
+// 只有最后一个实例保留重复类型（有"生育能力"），由续期逻辑继续生成后续实例
+func (s *TodoService) expandChildTodos(parent *models.Todo) ([]models.Todo, error) {
+	rule, err := models.ParseRRule(parent.RRule, parent.Location())
+	if err != nil {
+		return nil, fmt.Errorf("重复规则无效: %v", err)
+	}
+
+	// 在待办所属时区下展开，跨越夏令时切换时保持钟点不变
+	dtstart := parent.LocalStartTime()
+	horizon := time.Now()
+	if dtstart.After(horizon) {
+		horizon = dtstart
+	}
+	horizon = horizon.Add(recurrenceWindow)
+
+	exDates := parent.ExcludedDates()
+	starts := rule.Between(dtstart, dtstart, horizon, exDates, maxGeneratedChildren)
+	if len(starts) == 0 {
+		if first, ok := rule.After(dtstart, dtstart.Add(-time.Nanosecond), exDates); ok {
+			starts = append(starts, first)
+		}
+	}
+
+	taskDuration := parent.EndTime.Sub(parent.StartTime)
+	var childTodos []models.Todo
+	for _, start := range starts {
+		if !parent.RepeatEndDate.IsZero() && start.After(parent.RepeatEndDate) {
+			break
+		}
+		childTodos = append(childTodos, newChildTodo(parent, start, start.Add(taskDuration)))
+	}
+	for i := 0; i < len(childTodos)-1; i++ {
+		childTodos[i].RepeatType = "none"
+	}
+
+	log.Printf("根据重复规则为父待办 %d 生成 %d 个子待办实例", parent.ID, len(childTodos))
+	return childTodos, nil
+}
+
+// newSingleTodo 根据创建请求构造普通待办
+func newSingleTodo(userID uint, req *CreateTodoRequest, category *models.Category) *models.Todo {
+	return &models.Todo{
+		Title:         req.Title,
+		Description:   req.Description,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		Urgency:       req.Urgency,
+		Category:      category.Name,
+		CategoryID:    category.ID,
+		Status:        "pending",
+		ParentID:      0,
+		HasChildren:   false,
+		RepeatType:    "none",
+		AutoComplete:  req.AutoComplete,
+		Timezone:      req.Timezone,
+		CreatorUserID: userID,
+	}
+}
+
+// newRootTodo 根据创建请求构造重复待办的抽象待办
+func newRootTodo(userID uint, req *CreateTodoRequest, rule *models.RecurrenceRule, category *models.Category, exDates string) *models.Todo {
+	repeatEndDate := req.RepeatEndDate
+	if repeatEndDate.IsZero() {
+		repeatEndDate = rule.Until
+	}
+
+	return &models.Todo{
+		Title:          req.Title,
+		Description:    req.Description,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Urgency:        req.Urgency,
+		Category:       category.Name,
+		CategoryID:     category.ID,
+		Status:         "pending",
+		ParentID:       0,
+		HasChildren:    true,
+		RepeatType:     rule.RepeatType(),
+		RepeatInterval: rule.Interval,
+		RepeatEndDate:  repeatEndDate,
+		RRule:          rule.String(),
+		ExDates:        exDates,
+		AutoComplete:   req.AutoComplete,
+		Timezone:       req.Timezone,
+		CreatorUserID:  userID,
+	}
+}
+
+// newChildTodo 以抽象待办为模板创建一个实例
+func newChildTodo(parent *models.Todo, startTime, endTime time.Time) models.Todo {
+	return models.Todo{
+		Title:          parent.Title,
+		Description:    parent.Description,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		CreatedAt:      parent.CreatedAt,
+		Urgency:        parent.Urgency,
+		Category:       parent.Category,
+		CategoryID:     parent.CategoryID,
+		Status:         "pending",
+		ParentID:       parent.ID,
+		HasChildren:    false,
+		RepeatType:     parent.RepeatType,
+		RepeatInterval: parent.RepeatInterval,
+		RepeatEndDate:  parent.RepeatEndDate,
+		RRule:          parent.RRule,
+		ExDates:        parent.ExDates,
+		AutoComplete:   parent.AutoComplete,
+		Timezone:       parent.Timezone,
+		CreatorUserID:  parent.CreatorUserID,
+	}
+}
+
 // validateCreateRequest 验证创建请求
 func (s *TodoService) validateCreateRequest(req *CreateTodoRequest) error {
 	if req.Title == "" || req.Description == "" {
@@ -288,69 +555,162 @@ func (s *TodoService) validateCreateRequest(req *CreateTodoRequest) error {
 
 // RenewExpiredChildTodos 为有生育能力的过期待办创建下一代
 func (s *TodoService) RenewExpiredChildTodos(userID uint) (bool, string) {
-	// 查找有生育能力的过期子待办
-	expiredTodos, err := s.todoRepo.FindExpiredRenewableTodos(userID)
+	result, err := s.renewExpiredChildTodos(userID)
+	if err != nil {
+		return false, err.Error()
+	}
+	if result.Found == 0 {
+		return true, "未找到需要续期的过期子待办"
+	}
+
+	message := fmt.Sprintf("续期完成: 成功 %d, 跳过 %d, 失败 %d", result.Renewed, result.Skipped, result.Failed)
+	return true, message
+}
+
+// RenewResult 一轮续期的统计结果
+type RenewResult struct {
+	Found   int
+	Renewed int
+	Skipped int
+	Failed  int
+}
+
+// renewExpiredChildTodos 为用户执行一轮续期：每个有生育能力的子待办生成下一代实例
+// 整轮在一个事务中执行并锁定待续期的子待办，并发续期或中途失败都不会产生重复实例
+func (s *TodoService) renewExpiredChildTodos(userID uint) (*RenewResult, error) {
+	result := &RenewResult{}
+	err := s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		return s.renewExpiredChildTodosWithTx(tx, userID, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Renewed > 0 {
+		invalidateTodoStats(userID)
+	}
+	return result, nil
+}
+
+// renewExpiredChildTodosWithTx 在事务中执行一轮续期
+func (s *TodoService) renewExpiredChildTodosWithTx(tx *gorm.DB, userID uint, result *RenewResult) error {
+	// 查找并锁定有生育能力的过期子待办
+	expiredTodos, err := s.todoRepo.FindExpiredRenewableTodosForUpdateWithTx(tx, userID)
 	if err != nil {
-		return false, fmt.Sprintf("查询失败: %v", err)
+		return fmt.Errorf("查询失败: %v", err)
 	}
 
+	result.Found = len(expiredTodos)
 	if len(expiredTodos) == 0 {
-		return true, "未找到需要续期的过期子待办"
+		return nil
 	}
 
 	var newInstances []models.Todo
-	var renewedIDs []uint
-	renewedCount := 0
-	skippedCount := 0
-	failedCount := 0
+	var renewedIDs, endedIDs []uint
 
 	// 为每个待办创建下一代实例
 	for _, todo := range expiredTodos {
-		result, newTodo, err := s.createNextGeneration(&todo)
+		outcome, newTodo, err := s.createNextGeneration(&todo)
 		if err != nil {
-			failedCount++
+			result.Failed++
 			log.Printf("续期失败: %s (ID: %d) - %v", todo.Title, todo.ID, err)
 			continue
 		}
 
-		switch result {
+		switch outcome {
 		case "renewed":
-			renewedCount++
+			result.Renewed++
 			renewedIDs = append(renewedIDs, todo.ID)
 			newInstances = append(newInstances, *newTodo)
 		case "skipped":
-			skippedCount++
+			// 系列已结束（到达UNTIL或COUNT），最后一个实例不再参与续期
+			result.Skipped++
+			endedIDs = append(endedIDs, todo.ID)
 		}
 	}
 
-	// 批量创建新一代实例
+	// 批量创建新一代实例，并继承上一代的标签
 	if len(newInstances) > 0 {
-		if err := s.todoRepo.BatchCreate(newInstances); err != nil {
-			return false, fmt.Sprintf("创建新实例失败: %v", err)
+		if err := s.todoRepo.BatchCreateWithTx(tx, newInstances); err != nil {
+			return fmt.Errorf("创建新实例失败: %v", err)
+		}
+		targetToSource := make(map[uint]uint, len(newInstances))
+		for i := range newInstances {
+			targetToSource[newInstances[i].ID] = renewedIDs[i]
+		}
+		if err := s.tagRepo.CopyTodoTagsWithTx(tx, targetToSource); err != nil {
+			return fmt.Errorf("复制实例标签失败: %v", err)
+		}
+		if err := s.checklistRepo.CopyToTodosWithTx(tx, targetToSource); err != nil {
+			return fmt.Errorf("复制实例清单失败: %v", err)
 		}
 	}
 
-	// 将已续期的待办设为"无生育能力"
-	if len(renewedIDs) > 0 {
-		if err := s.todoRepo.BatchUpdateRepeatType(renewedIDs, "none"); err != nil {
-			return false, fmt.Sprintf("更新重复规则失败: %v", err)
+	// 将已续期和系列已结束的待办设为"无生育能力"
+	if settledIDs := append(renewedIDs, endedIDs...); len(settledIDs) > 0 {
+		if err := s.todoRepo.BatchUpdateRepeatTypeWithTx(tx, settledIDs, "none"); err != nil {
+			return fmt.Errorf("更新重复规则失败: %v", err)
 		}
 	}
 
-	message := fmt.Sprintf("续期完成: 成功 %d, 跳过 %d, 失败 %d", renewedCount, skippedCount, failedCount)
-	return true, message
+	return nil
 }
 
-// createNextGeneration 为待办创建下一代实例
+// maxRenewRounds 后台续期时单个用户最多连续执行的轮数，防止异常数据导致死循环
+const maxRenewRounds = 50
+
+// RenewAllSeries 为所有用户续期重复待办，直到实例覆盖续期窗口
+// 返回新生成的实例数量
+func (s *TodoService) RenewAllSeries() (int, error) {
+	userIDs, err := s.todoRepo.FindUserIDsWithRenewableTodos()
+	if err != nil {
+		return 0, fmt.Errorf("查询待续期用户失败: %v", err)
+	}
+
+	total := 0
+	for _, userID := range userIDs {
+		for round := 0; round < maxRenewRounds; round++ {
+			result, err := s.renewExpiredChildTodos(userID)
+			if err != nil {
+				log.Printf("用户 %d 续期失败: %v", userID, err)
+				break
+			}
+			total += result.Renewed
+			if result.Renewed == 0 {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// RefreshOverdueFlags 刷新待办的逾期标记，返回新标记和清除的数量
+func (s *TodoService) RefreshOverdueFlags() (int64, int64, error) {
+	now := time.Now()
+	marked, err := s.todoRepo.MarkOverdueTodos(now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("标记逾期待办失败: %v", err)
+	}
+	cleared, err := s.todoRepo.ClearOverdueTodos(now)
+	if err != nil {
+		return marked, 0, fmt.Errorf("清除逾期标记失败: %v", err)
+	}
+	return marked, cleared, nil
+}
+
+// createNextGeneration 为待办创建下一代实例，系列没有后续实例时返回"skipped"
 func (s *TodoService) createNextGeneration(parent *models.Todo) (string, *models.Todo, error) {
+	// 推迟过的实例按原定时间推算，推迟不影响后续实例
+	series := *parent
+	series.StartTime, series.EndTime = parent.ScheduledTimes()
+
 	// 使用CalculateNextInstance计算下一个实例时间
-	nextStart, exists := parent.CalculateNextInstance(time.Now())
+	nextStart, exists := series.CalculateNextInstance(time.Now())
 	if !exists {
-		return "skipped", nil, fmt.Errorf("无后续实例可用")
+		return "skipped", nil, nil
 	}
 
 	// 计算任务持续时间
-	taskDuration := parent.EndTime.Sub(parent.StartTime)
+	taskDuration := series.EndTime.Sub(series.StartTime)
 	nextEnd := nextStart.Add(taskDuration)
 
 	// 创建新一代实例
@@ -363,11 +723,16 @@ func (s *TodoService) createNextGeneration(parent *models.Todo) (string, *models
 		Status:         "pending",
 		Urgency:        parent.Urgency,
 		Category:       parent.Category,
+		CategoryID:     parent.CategoryID,
 		ParentID:       parent.ParentID,
 		HasChildren:    false,
 		RepeatType:     parent.RepeatType,
 		RepeatInterval: parent.RepeatInterval,
 		RepeatEndDate:  parent.RepeatEndDate,
+		RRule:          parent.RRule,
+		ExDates:        parent.ExDates,
+		AutoComplete:   parent.AutoComplete,
+		Timezone:       parent.Timezone,
 		CreatorUserID:  parent.CreatorUserID,
 		CreatorOrganID: parent.CreatorOrganID,
 	}
@@ -382,18 +747,13 @@ func (s *TodoService) CompleteTodoByDetails(userID uint, title, description stri
 		return false, err.Error()
 	}
 
-	completedAt := time.Now()
-
-	// 调用仓库层更新状态和完成时间
-	err := s.todoRepo.UpdateTodoStateAndCompletedTime(
-		userID, title, description, startTime, endTime, "completed", completedAt,
-	)
-
+	// 按状态机更新状态和完成时间
+	todo, err := s.transitionTodoByDetails(userID, title, description, startTime, endTime, TodoStatusCompleted)
 	if err != nil {
 		return false, fmt.Sprintf("完成待办失败: %v", err)
 	}
 
-	return true, fmt.Sprintf("待办已完成于 %s", completedAt.Format("2006-01-02 15:04"))
+	return true, fmt.Sprintf("待办已完成于 %s", todo.CompletedAt.Format("2006-01-02 15:04"))
 }
 
 // CancelCompletedTodo 将已完成待办改成未完成状态
@@ -401,9 +761,16 @@ func (s *TodoService) CancelCompletedTodo(userID uint, title, description string
 	if err := s.validateCompleteRequest(title, description, startTime, endTime); err != nil {
 		return fmt.Errorf("%s", err.Error())
 	}
-	if err := s.todoRepo.UpdateTodoStateAndCompletedTime(userID, title, description, startTime, endTime, "pending", time.Now()); err != nil {
+	todo, err := s.todoRepo.FindTodoByDetails(userID, startTime, endTime, title, description)
+	if err != nil {
 		return fmt.Errorf("无法将完成待办改成未完成，原因：%v", err)
 	}
+	if todo.Status != TodoStatusCompleted {
+		return fmt.Errorf("%w: 待办当前状态为 %s，不是已完成", ErrIllegalTransition, todo.Status)
+	}
+	if _, err := s.TransitionTodo(userID, todo.ID, TodoStatusPending); err != nil {
+		return fmt.Errorf("无法将完成待办改成未完成，原因：%w", err)
+	}
 	return nil
 }
 
@@ -425,43 +792,712 @@ func (s *TodoService) validateCompleteRequest(title, description string, startTi
 	return nil
 }
 
+// GetTodoByID 根据ID获取待办，并校验是否属于当前用户
+func (s *TodoService) GetTodoByID(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.FindByID(todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+
+	if todo.CreatorUserID != userID {
+		return nil, ErrTodoForbidden
+	}
+
+	return todo, nil
+}
+
+// UpdateTodoRequest 全量更新待办请求（PUT）
+type UpdateTodoRequest struct {
+	Title          string    `json:"title" binding:"required" example:"团队会议"`
+	Description    string    `json:"description" example:"项目进度讨论"`
+	Status         string    `json:"status" binding:"required,oneof=pending in_progress completed cancelled" example:"pending"`
+	Urgency        string    `json:"urgency" binding:"required,oneof=low medium high" example:"medium"`
+	Category       string    `json:"category" binding:"required,max=50" example:"work"`
+	StartTime      time.Time `json:"start_time" binding:"required" example:"2024-01-15T14:00:00Z"`
+	EndTime        time.Time `json:"end_time" binding:"required" example:"2024-01-15T15:00:00Z"`
+	RepeatType     string    `json:"repeat_type" binding:"omitempty,oneof=none daily weekly monthly yearly" example:"none"`
+	RepeatInterval int       `json:"repeat_interval" binding:"omitempty,min=1" example:"1"`
+	RepeatEndDate  time.Time `json:"repeat_end_date"`
+	RRule          string    `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	ExDates        []string  `json:"exdates" example:"2024-01-17"`
+	Tags           []string  `json:"tags" example:"会议"`
+}
+
+// PatchTodoRequest 部分更新待办请求（PATCH），只更新非空字段
+type PatchTodoRequest struct {
+	Title          *string    `json:"title,omitempty" example:"团队会议"`
+	Description    *string    `json:"description,omitempty" example:"项目进度讨论"`
+	Status         *string    `json:"status,omitempty" binding:"omitempty,oneof=pending in_progress completed cancelled" example:"completed"`
+	Urgency        *string    `json:"urgency,omitempty" binding:"omitempty,oneof=low medium high" example:"high"`
+	Category       *string    `json:"category,omitempty" binding:"omitempty,max=50" example:"study"`
+	StartTime      *time.Time `json:"start_time,omitempty" example:"2024-01-15T14:00:00Z"`
+	EndTime        *time.Time `json:"end_time,omitempty" example:"2024-01-15T15:00:00Z"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	RepeatType     *string    `json:"repeat_type,omitempty" binding:"omitempty,oneof=none daily weekly monthly yearly" example:"weekly"`
+	RepeatInterval *int       `json:"repeat_interval,omitempty" binding:"omitempty,min=1" example:"1"`
+	RepeatEndDate  *time.Time `json:"repeat_end_date,omitempty"`
+	RRule          *string    `json:"rrule,omitempty" example:"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"`
+	ExDates        *[]string  `json:"exdates,omitempty" example:"2024-01-31"`
+	Tags           *[]string  `json:"tags,omitempty" example:"会议"` // 提供时整体替换待办的标签
+	AutoComplete   *bool      `json:"auto_complete,omitempty" example:"true"`
+}
+
+// ReplaceTodo 全量更新待办（PUT语义）
+func (s *TodoService) ReplaceTodo(userID, todoID uint, req *UpdateTodoRequest) (*models.Todo, error) {
+	patch := &PatchTodoRequest{
+		Title:       &req.Title,
+		Description: &req.Description,
+		Status:      &req.Status,
+		Urgency:     &req.Urgency,
+		Category:    &req.Category,
+		StartTime:   &req.StartTime,
+		EndTime:     &req.EndTime,
+		RRule:       &req.RRule,
+		ExDates:     &req.ExDates,
+		Tags:        &req.Tags,
+	}
+	if req.RepeatType != "" {
+		patch.RepeatType = &req.RepeatType
+	}
+	if req.RepeatInterval > 0 {
+		patch.RepeatInterval = &req.RepeatInterval
+	}
+	if !req.RepeatEndDate.IsZero() {
+		patch.RepeatEndDate = &req.RepeatEndDate
+	}
+
+	return s.PatchTodo(userID, todoID, patch)
+}
+
+// PatchTodo 部分更新待办（PATCH语义），抽象待办被取消时会一并取消未完成的实例
+func (s *TodoService) PatchTodo(userID, todoID uint, req *PatchTodoRequest) (*models.Todo, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return s.patchTodo(userID, todo, req, nil)
+}
+
+// patchTodo 在一个事务中应用部分更新，extra非nil时在同一事务中执行附加的写入（如看板排序键）
+func (s *TodoService) patchTodo(userID uint, todo *models.Todo, req *PatchTodoRequest, extra func(tx *gorm.DB) error) (*models.Todo, error) {
+	updates, err := s.buildTodoUpdates(todo, req)
+	if err != nil {
+		return nil, err
+	}
+	var tagIDs []uint
+	if req.Tags != nil {
+		if tagIDs, err = s.resolveTagIDs(userID, *req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	// 没有需要更新的字段，直接返回原待办
+	if len(updates) == 0 && req.Tags == nil && extra == nil {
+		return s.todoWithDetails(todo)
+	}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if extra != nil {
+			if err := extra(tx); err != nil {
+				return err
+			}
+		}
+		if len(updates) == 0 && req.Tags == nil {
+			return nil
+		}
+		recorder := &todoChangeRecorder{}
+		if len(updates) > 0 {
+			if err := s.updateTodoWithTx(tx, todo.ID, updates); err != nil {
+				return fmt.Errorf("更新待办失败: %v", err)
+			}
+			recorder.record(todo, updates)
+		}
+		if req.Tags != nil {
+			if err := s.tagRepo.ReplaceTodoTagsWithTx(tx, []uint{todo.ID}, tagIDs); err != nil {
+				return fmt.Errorf("更新待办标签失败: %v", err)
+			}
+		}
+
+		// 抽象待办被取消时，同步取消其未完成的实例
+		if todo.HasChildren && updates["status"] == TodoStatusCancelled {
+			if _, err := s.cancelChildrenWithTx(tx, todo.ID, recorder); err != nil {
+				return err
+			}
+		}
+
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: todo.CreatorUserID,
+			ActorID: userID,
+			TodoID:  todo.ID,
+			Action:  patchAction(updates),
+		}, recorder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 前置待办结束后通知解除阻塞的后续待办
+	if status, ok := updates["status"].(string); ok && isResolvedStatus(status) {
+		s.notifyUnblocked(todo)
+	}
+	// 时间或状态变化会改变空闲时段，重新安排弹性待办
+	if affectsSchedule(updates) {
+		s.replanAfterChange(todo.CreatorUserID)
+	}
+
+	updated, err := s.todoRepo.FindByID(todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.todoWithDetails(updated)
+}
+
+// updateTodoWithTx 在事务中更新待办字段，开始/结束时间变化时同步其相对提醒的触发时间
+func (s *TodoService) updateTodoWithTx(tx *gorm.DB, todoID uint, updates map[string]any) error {
+	if err := s.todoRepo.UpdateWithTx(tx, todoID, updates); err != nil {
+		return err
+	}
+	return s.syncReminderTimesWithTx(tx, todoID, updates)
+}
+
+// syncReminderTimesWithTx 更新字段包含开始或结束时间时，重新计算待办的相对提醒触发时间
+func (s *TodoService) syncReminderTimesWithTx(tx *gorm.DB, todoID uint, updates map[string]any) error {
+	_, startChanged := updates["start_time"]
+	_, endChanged := updates["end_time"]
+	if !startChanged && !endChanged {
+		return nil
+	}
+	return s.reminderRepo.SyncRelativeRemindAtWithTx(tx, models.ReminderTargetTodo, todoID)
+}
+
+// todoWithDetails 为单个待办填充标签和清单进度
+func (s *TodoService) todoWithDetails(todo *models.Todo) (*models.Todo, error) {
+	todos := []models.Todo{*todo}
+	if err := s.attachTodoDetails(todos); err != nil {
+		return nil, err
+	}
+	return &todos[0], nil
+}
+
+// GetTodoDetail 获取待办详情（含标签和清单进度）
+func (s *TodoService) GetTodoDetail(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return s.todoWithDetails(todo)
+}
+
+// buildTodoUpdates 根据部分更新请求构建更新字段，并校验更新后的待办是否合法
+func (s *TodoService) buildTodoUpdates(todo *models.Todo, req *PatchTodoRequest) (map[string]any, error) {
+	updates := make(map[string]any)
+
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return nil, fmt.Errorf("%w: 标题不能为空", ErrInvalidTodoParams)
+		}
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.AutoComplete != nil {
+		updates["auto_complete"] = *req.AutoComplete
+	}
+	if req.Urgency != nil {
+		updates["urgency"] = *req.Urgency
+	}
+	if req.Category != nil {
+		category, err := s.resolveCategory(todo.CreatorUserID, *req.Category)
+		if err != nil {
+			return nil, err
+		}
+		updates["category"] = category.Name
+		updates["category_id"] = category.ID
+	}
+
+	// 校验更新后的时间范围
+	startTime, endTime := todo.StartTime, todo.EndTime
+	if req.StartTime != nil {
+		startTime = *req.StartTime
+		updates["start_time"] = startTime
+	}
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+		updates["end_time"] = endTime
+	}
+	if startTime.After(endTime) {
+		return nil, fmt.Errorf("%w: 开始时间不能晚于结束时间", ErrInvalidTodoParams)
+	}
+	retimed := !startTime.Equal(todo.StartTime) || !endTime.Equal(todo.EndTime)
+	// 单独修改重复实例的时间时记录原定时间，续期仍按原定时间推算，不影响系列中的其他实例
+	if retimed && todo.ParentID != 0 && !todo.Rescheduled() {
+		updates["original_start_time"] = todo.StartTime
+		updates["original_end_time"] = todo.EndTime
+	}
+	// 手动修改弹性待办的时间后转为固定待办，自动排程不再移动它
+	if todo.Flexible && retimed {
+		updates["flexible"] = false
+		updates["unplaced"] = false
+	}
+
+	// 状态变更必须符合状态机，开始/完成时间随之自动维护
+	if req.Status != nil && *req.Status != todo.Status {
+		if err := mergeTransitionUpdates(updates, todo, *req.Status); err != nil {
+			return nil, err
+		}
+		if err := s.ensureNotBlocked(todo, *req.Status); err != nil {
+			return nil, err
+		}
+	}
+	// 只能为已完成（或本次变为已完成）的待办指定完成时间，用于补记实际完成时间
+	if req.CompletedAt != nil {
+		status := todo.Status
+		if req.Status != nil {
+			status = *req.Status
+		}
+		if status != TodoStatusCompleted {
+			return nil, fmt.Errorf("%w: 只有已完成的待办可以设置完成时间", ErrInvalidTodoParams)
+		}
+		if req.CompletedAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: 完成时间不能晚于当前时间", ErrInvalidTodoParams)
+		}
+		updates["completed_at"] = *req.CompletedAt
+	}
+
+	// 重复规则只能在抽象待办上修改：普通待办或实例带上重复规则后会被续期逻辑当作系列，生成重复或无归属的实例
+	if !todo.HasChildren {
+		changed, err := recurrenceChanged(todo, req)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			return nil, fmt.Errorf("%w: 只有重复待办的抽象待办可以修改重复规则，编辑系列中的实例请使用按范围编辑接口", ErrInvalidTodoParams)
+		}
+		return updates, nil
+	}
+
+	if req.RepeatType != nil {
+		updates["repeat_type"] = *req.RepeatType
+	}
+	if req.RepeatInterval != nil {
+		updates["repeat_interval"] = *req.RepeatInterval
+	}
+	if req.RepeatEndDate != nil {
+		updates["repeat_end_date"] = *req.RepeatEndDate
+	}
+
+	// RRULE非空时以规则中的频率和间隔为准，空字符串表示清除规则
+	if req.RRule != nil {
+		if strings.TrimSpace(*req.RRule) == "" {
+			updates["rrule"] = ""
+		} else {
+			rule, err := models.ParseRRule(*req.RRule, todo.Location())
+			if err != nil {
+				return nil, fmt.Errorf("%w: 重复规则无效: %v", ErrInvalidTodoParams, err)
+			}
+			rule.ResolveCount(startTime.In(todo.Location()))
+			updates["rrule"] = rule.String()
+			updates["repeat_type"] = rule.RepeatType()
+			updates["repeat_interval"] = rule.Interval
+		}
+	}
+	if req.ExDates != nil {
+		exDates, err := normalizeExDates(*req.ExDates)
+		if err != nil {
+			return nil, err
+		}
+		updates["exdates"] = exDates
+	}
+
+	return updates, nil
+}
+
+// recurrenceChanged 判断部分更新请求是否修改了待办的重复规则相关字段，与当前值相同（如PUT原样提交）不算修改
+func recurrenceChanged(todo *models.Todo, req *PatchTodoRequest) (bool, error) {
+	if req.RepeatType != nil && *req.RepeatType != todo.RepeatType {
+		return true, nil
+	}
+	if req.RepeatInterval != nil && *req.RepeatInterval != todo.RepeatInterval {
+		return true, nil
+	}
+	if req.RepeatEndDate != nil && !req.RepeatEndDate.Equal(todo.RepeatEndDate) {
+		return true, nil
+	}
+	if req.RRule != nil && strings.TrimSpace(*req.RRule) != todo.RRule {
+		return true, nil
+	}
+	if req.ExDates != nil {
+		exDates, err := normalizeExDates(*req.ExDates)
+		if err != nil {
+			return false, err
+		}
+		if exDates != todo.ExDates {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// 编辑重复待办时的作用范围
+const (
+	EditScopeThis      = "this"      // 仅当前实例
+	EditScopeFollowing = "following" // 当前及之后的实例
+	EditScopeAll       = "all"       // 整个系列
+)
+
+// EditTodoRequest 编辑待办请求，支持对重复待办按范围编辑
+type EditTodoRequest struct {
+	Scope       string     `json:"scope" binding:"omitempty,oneof=this following all" example:"following"`
+	Title       *string    `json:"title,omitempty" example:"每周例会"`
+	Description *string    `json:"description,omitempty" example:"同步本周进度"`
+	Urgency     *string    `json:"urgency,omitempty" binding:"omitempty,oneof=low medium high" example:"high"`
+	Category    *string    `json:"category,omitempty" binding:"omitempty,max=50" example:"work"`
+	StartTime   *time.Time `json:"start_time,omitempty" example:"2024-01-15T15:00:00Z"`
+	EndTime     *time.Time `json:"end_time,omitempty" example:"2024-01-15T16:00:00Z"`
+	Tags        *[]string  `json:"tags,omitempty" example:"会议"`
+}
+
+// EditTodo 编辑待办，返回被修改的待办数量
+// 对重复待办：this 只改当前实例，following 改当前及之后的实例，all 改整个系列（含抽象待办）。
+// following 会拆分系列：原系列的规则截止到当前实例之前，当前及之后的实例归入新的抽象待办，订阅的日历随之更新。
+// this 修改时间时记录实例的原定时间，续期仍按原定时间推算，不会带动之后的实例。
+// 时间的修改以当前实例为基准计算偏移量和新时长，再应用到范围内的每个实例上，重复规则随之平移；已完成和已取消的实例不受影响。
+func (s *TodoService) EditTodo(userID, todoID uint, req *EditTodoRequest) (int, error) {
+	target, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return 0, err
+	}
+
+	// 普通待办，或者只修改当前实例
+	scope := req.Scope
+	if scope == "" {
+		scope = EditScopeThis
+	}
+	isSingle := target.ParentID == 0 && !target.HasChildren
+	if isSingle || (target.ParentID != 0 && scope == EditScopeThis) {
+		patch := &PatchTodoRequest{
+			Title:       req.Title,
+			Description: req.Description,
+			Urgency:     req.Urgency,
+			Category:    req.Category,
+			StartTime:   req.StartTime,
+			EndTime:     req.EndTime,
+			Tags:        req.Tags,
+		}
+		if _, err := s.PatchTodo(userID, todoID, patch); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	// 编辑抽象待办等同于编辑整个系列
+	parentID := target.ParentID
+	if target.HasChildren {
+		parentID = target.ID
+		scope = EditScopeAll
+	}
+	parent, err := s.todoRepo.FindByID(parentID)
+	if err != nil {
+		return 0, fmt.Errorf("查询抽象待办失败: %v", err)
+	}
+	// 从系列的第一个实例开始编辑之后的实例，等同于编辑整个系列
+	splitAt, _ := target.ScheduledTimes()
+	if scope == EditScopeFollowing && !splitAt.After(parent.StartTime) {
+		scope = EditScopeAll
+	}
+
+	// 以当前实例为基准计算新的时间，并校验其合法性
+	textUpdates, err := s.buildTodoUpdates(target, &PatchTodoRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Urgency:     req.Urgency,
+		Category:    req.Category,
+	})
+	if err != nil {
+		return 0, err
+	}
+	newStart, newEnd := target.StartTime, target.EndTime
+	if req.StartTime != nil {
+		newStart = *req.StartTime
+	}
+	if req.EndTime != nil {
+		newEnd = *req.EndTime
+	}
+	if newStart.After(newEnd) {
+		return 0, fmt.Errorf("%w: 开始时间不能晚于结束时间", ErrInvalidTodoParams)
+	}
+	retime := req.StartTime != nil || req.EndTime != nil
+	shift := newStart.Sub(target.StartTime)
+	duration := newEnd.Sub(newStart)
+	shiftDays := calendarDaysBetween(target.StartTime, newStart, target.LocalStartTime().Location())
+	if retime {
+		// 提前校验规则能否随时间平移，避免事务中途失败
+		if _, err := shiftSeriesRule(target, shift, shiftDays); err != nil {
+			return 0, err
+		}
+	}
+
+	var tagIDs []uint
+	if req.Tags != nil {
+		if tagIDs, err = s.resolveTagIDs(userID, *req.Tags); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(textUpdates) == 0 && !retime && req.Tags == nil {
+		return 0, nil
+	}
+
+	// retimedUpdates 为单个待办生成包含平移后时间和重复规则的更新字段
+	retimedUpdates := func(todo *models.Todo) (map[string]any, error) {
+		updates := make(map[string]any, len(textUpdates)+2)
+		for k, v := range textUpdates {
+			updates[k] = v
+		}
+		if retime {
+			ruleUpdates, err := shiftSeriesRule(todo, shift, shiftDays)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range ruleUpdates {
+				updates[k] = v
+			}
+
+			start := todo.StartTime.Add(shift)
+			updates["start_time"] = start
+			updates["end_time"] = start.Add(duration)
+			// 偏离过原定时间的实例同步平移原定时间，续期仍与系列保持一致
+			if todo.Rescheduled() {
+				originalStart := todo.OriginalStartTime.Add(shift)
+				updates["original_start_time"] = originalStart
+				updates["original_end_time"] = originalStart.Add(duration)
+			}
+		}
+		return updates, nil
+	}
+
+	var editedIDs []uint
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		recorder := &todoChangeRecorder{}
+		seriesID := parentID
+		var children []models.Todo
+		if scope == EditScopeAll {
+			updates, err := retimedUpdates(parent)
+			if err != nil {
+				return err
+			}
+			if len(updates) > 0 {
+				if err := s.updateTodoWithTx(tx, parent.ID, updates); err != nil {
+					return fmt.Errorf("更新抽象待办失败: %v", err)
+				}
+				recorder.record(parent, updates)
+			}
+			editedIDs = append(editedIDs, parent.ID)
+
+			if children, err = s.todoRepo.FindEditableChildrenWithTx(tx, parentID, time.Time{}); err != nil {
+				return fmt.Errorf("查询子待办失败: %v", err)
+			}
+		} else {
+			newParent, err := s.splitSeriesWithTx(tx, parent, splitAt, recorder)
+			if err != nil {
+				return err
+			}
+			updates, err := retimedUpdates(newParent)
+			if err != nil {
+				return err
+			}
+			if len(updates) > 0 {
+				if err := s.updateTodoWithTx(tx, newParent.ID, updates); err != nil {
+					return fmt.Errorf("更新抽象待办失败: %v", err)
+				}
+			}
+			editedIDs = append(editedIDs, newParent.ID)
+			seriesID = newParent.ID
+
+			from := target.StartTime
+			if splitAt.Before(from) {
+				from = splitAt
+			}
+			if children, err = s.todoRepo.FindChildrenFromWithTx(tx, parentID, from); err != nil {
+				return fmt.Errorf("查询子待办失败: %v", err)
+			}
+		}
+
+		for i := range children {
+			// 已完成和已取消的实例只随系列拆分归入新的抽象待办，内容和时间保持不变
+			editable := children[i].Status == TodoStatusPending || children[i].Status == TodoStatusInProgress
+			updates := make(map[string]any)
+			if editable {
+				if updates, err = retimedUpdates(&children[i]); err != nil {
+					return err
+				}
+			}
+			if seriesID != parentID {
+				updates["parent_id"] = seriesID
+			}
+			if len(updates) > 0 {
+				if err := s.updateTodoWithTx(tx, children[i].ID, updates); err != nil {
+					return fmt.Errorf("更新子待办失败: %v", err)
+				}
+				recorder.record(&children[i], updates)
+			}
+			if editable {
+				editedIDs = append(editedIDs, children[i].ID)
+			}
+		}
+
+		if req.Tags != nil {
+			if err := s.tagRepo.ReplaceTodoTagsWithTx(tx, editedIDs, tagIDs); err != nil {
+				return fmt.Errorf("更新待办标签失败: %v", err)
+			}
+		}
+
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: target.CreatorUserID,
+			ActorID: userID,
+			TodoID:  parentID,
+			Action:  models.TodoActionEditSeries,
+		}, recorder)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(editedIDs), nil
+}
+
+// splitSeriesWithTx 在splitAt处拆分系列：原抽象待办的规则截止到splitAt之前，
+// 并以splitAt为首次出现创建新的抽象待办（继承标签和清单），返回新的抽象待办
+// 撤销时新的抽象待办移入回收站，实例恢复归属原系列
+func (s *TodoService) splitSeriesWithTx(tx *gorm.DB, parent *models.Todo, splitAt time.Time, recorder *todoChangeRecorder) (*models.Todo, error) {
+	end := splitAt.Add(-time.Second)
+	capUpdates := make(map[string]any)
+	if parent.RRule != "" {
+		rule, err := models.ParseRRule(parent.RRule, parent.Location())
+		if err != nil {
+			return nil, fmt.Errorf("%w: 重复规则无效: %v", ErrInvalidTodoParams, err)
+		}
+		rule.Count = 0
+		if rule.Until.IsZero() || rule.Until.After(end) {
+			rule.Until = end
+		}
+		capUpdates["rrule"] = rule.String()
+	}
+	if parent.RepeatEndDate.After(end) {
+		capUpdates["repeat_end_date"] = end
+	}
+	if len(capUpdates) > 0 {
+		if err := s.updateTodoWithTx(tx, parent.ID, capUpdates); err != nil {
+			return nil, fmt.Errorf("更新抽象待办失败: %v", err)
+		}
+		recorder.record(parent, capUpdates)
+	}
+
+	newParent := *parent
+	newParent.ID = 0
+	newParent.CreatedAt = time.Time{}
+	newParent.StartTime = splitAt
+	newParent.EndTime = splitAt.Add(parent.EndTime.Sub(parent.StartTime))
+	if err := s.todoRepo.CreateWithTx(tx, &newParent); err != nil {
+		return nil, fmt.Errorf("拆分系列失败: %v", err)
+	}
+	targetToSource := map[uint]uint{newParent.ID: parent.ID}
+	if err := s.tagRepo.CopyTodoTagsWithTx(tx, targetToSource); err != nil {
+		return nil, fmt.Errorf("复制系列标签失败: %v", err)
+	}
+	if err := s.checklistRepo.CopyToTodosWithTx(tx, targetToSource); err != nil {
+		return nil, fmt.Errorf("复制系列清单失败: %v", err)
+	}
+	// 新系列在拆分前不存在，记为从回收站恢复，撤销时移入回收站
+	recorder.add(newParent.ID, "deleted_at",
+		formatTodoFieldValue(gorm.DeletedAt{Time: time.Now(), Valid: true}), "")
+	return &newParent, nil
+}
+
+// shiftSeriesRule 整体平移系列时间时同步调整重复规则：BYDAY随跨天的天数平移，UNTIL随时间平移，排除日期随天数平移
+// 无法等价平移的规则返回参数错误
+func shiftSeriesRule(todo *models.Todo, shift time.Duration, days int) (map[string]any, error) {
+	updates := make(map[string]any)
+	if todo.RRule == "" || shift == 0 {
+		return updates, nil
+	}
+	rule, err := models.ParseRRule(todo.RRule, todo.Location())
+	if err != nil {
+		return nil, fmt.Errorf("%w: 重复规则无效: %v", ErrInvalidTodoParams, err)
+	}
+	if err := rule.ShiftWeekdays(days); err != nil {
+		return nil, fmt.Errorf("%w: %v，请只调整当天的钟点或单独修改实例", ErrInvalidTodoParams, err)
+	}
+	// UNTIL由COUNT换算而来时等于最后一次出现的时间，不随之平移会丢掉最后一次
+	if !rule.Until.IsZero() {
+		if todo.RepeatEndDate.Equal(rule.Until) {
+			updates["repeat_end_date"] = rule.Until.Add(shift)
+		}
+		rule.Until = rule.Until.Add(shift)
+	}
+	updates["rrule"] = rule.String()
+
+	if days != 0 && todo.ExDates != "" {
+		dates := strings.Split(todo.ExDates, ",")
+		for i, date := range dates {
+			if day, err := time.Parse("2006-01-02", strings.TrimSpace(date)); err == nil {
+				dates[i] = day.AddDate(0, 0, days).Format("2006-01-02")
+			}
+		}
+		updates["exdates"] = strings.Join(dates, ",")
+	}
+	return updates, nil
+}
+
+// calendarDaysBetween 返回两个时间在loc时区下相差的日历天数
+func calendarDaysBetween(from, to time.Time, loc *time.Location) int {
+	fy, fm, fd := from.In(loc).Date()
+	ty, tm, td := to.In(loc).Date()
+	diff := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Sub(time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC))
+	return int(diff.Hours() / 24)
+}
+
 // GetTodayTodos 查找今日待办
-func (s *TodoService) GetTodayTodos(userID uint) ([]models.Todo, error) {
+func (s *TodoService) GetTodayTodos(userID uint, filter TodoListFilter) ([]models.Todo, error) {
 
-	startOfToday, endOfToday := TodayRange()
+	startOfToday, endOfToday := TodayRange(s.userLocation(userID))
 
 	todos, err := s.todoRepo.FindTodosInTimeRange(userID, startOfToday, endOfToday)
 	if err != nil {
 		return nil, fmt.Errorf("查询今日待办失败: %v", err)
 	}
 
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetOneDayTodos 得到某一天开始的未完成的待办
-func (s *TodoService) GetOneDayTodos(userID uint, dateStr string) ([]models.Todo, error) {
+func (s *TodoService) GetOneDayTodos(userID uint, dateStr string, filter TodoListFilter) ([]models.Todo, error) {
 	date, err := ParseDateString(dateStr)
 	if err != nil {
 		return nil, err
 	}
-	startOfDay, endOfDay := DayRange(date)
+	startOfDay, endOfDay := DayRange(date, s.userLocation(userID))
 	todos, err := s.todoRepo.FindTodosStartingInRange(userID, startOfDay, endOfDay)
 	if err != nil {
 		return nil, err
 	}
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetCompletedTodosByDate 获取指定日期完成的所有待办
-func (s *TodoService) GetCompletedTodosByDate(userID uint, dateStr string) ([]models.Todo, error) {
+func (s *TodoService) GetCompletedTodosByDate(userID uint, dateStr string, filter TodoListFilter) ([]models.Todo, error) {
 	// 解析日期字符串
 	date, err := ParseDateString(dateStr)
 	if err != nil {
 		return nil, err
 	}
 
-	//获取指定日期的开始和结束
-	startOfDay, endOfDay := DayRange(date)
+	//获取指定日期在用户时区的开始和结束
+	startOfDay, endOfDay := DayRange(date, s.userLocation(userID))
 
 	// 调用仓库层函数查询在指定日期范围内完成的任务
 	todos, err := s.todoRepo.FindCompletedInRange(userID, startOfDay, endOfDay)
@@ -469,15 +1505,14 @@ func (s *TodoService) GetCompletedTodosByDate(userID uint, dateStr string) ([]mo
 		return nil, fmt.Errorf("查询已完成待办失败: %v", err)
 	}
 
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetTodosStartingInNext7Days 获取未来七天内会开始的待办
-func (s *TodoService) GetTodosStartingInNext7Days(userID uint) ([]models.Todo, error) {
-	// 获取当前时间和未来七天的时间范围
-	now := time.Now()
-	startTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	startTime = startTime.AddDate(0, 0, 1)
+func (s *TodoService) GetTodosStartingInNext7Days(userID uint, filter TodoListFilter) ([]models.Todo, error) {
+	// 获取用户时区下明天开始的七天
+	startOfToday, _ := TodayRange(s.userLocation(userID))
+	startTime := startOfToday.AddDate(0, 0, 1)
 	endTime := startTime.AddDate(0, 0, 7)
 
 	todos, err := s.todoRepo.FindTodosStartingInRange(userID, startTime, endTime)
@@ -485,42 +1520,41 @@ func (s *TodoService) GetTodosStartingInNext7Days(userID uint) ([]models.Todo, e
 		return nil, fmt.Errorf("查询未来七天开始的待办失败: %v", err)
 	}
 
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetTodosEndingInNext7Days 获取未来七天内会结束的待办
-func (s *TodoService) GetTodosEndingInNext7Days(userID uint) ([]models.Todo, error) {
-	// 获取当前时间和未来七天的时间范围
-	now := time.Now()
-	startTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endTime := now.AddDate(0, 0, 7)
+func (s *TodoService) GetTodosEndingInNext7Days(userID uint, filter TodoListFilter) ([]models.Todo, error) {
+	// 获取用户时区下今天开始到七天后的时间范围
+	startTime, _ := TodayRange(s.userLocation(userID))
+	endTime := time.Now().AddDate(0, 0, 7)
 
 	todos, err := s.todoRepo.FindTodosEndingInRange(userID, startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("查询未来七天结束的待办失败: %v", err)
 	}
 
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetOneDayExpiredTodos 获取某一天过期的待办
-func (s *TodoService) GetOneDayExpiredTodos(userID uint, dateStr string) ([]models.Todo, error) {
+func (s *TodoService) GetOneDayExpiredTodos(userID uint, dateStr string, filter TodoListFilter) ([]models.Todo, error) {
 	date, err := ParseDateString(dateStr)
 	if err != nil {
 		return nil, fmt.Errorf("解析日期出错，%v", err)
 	}
-	startTime, endTime := DayRange(date)
+	startTime, endTime := DayRange(date, s.userLocation(userID))
 	todos, err := s.todoRepo.FindOneDayExpiredTodos(userID, startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("获取过期待办出错，%v", err)
 	}
-	return todos, nil
+	return s.applyListFilter(todos, filter)
 }
 
 // GetTodayOrganizationTodos 获取用户今日有待办活动的组织列表
 // 查找条件：活动的开始时间或结束时间包含今天，或者活动时间段与今天有交集
 func (s *TodoService) GetTodayOrganizationTodos(userID uint) ([]models.Organization, error) {
-	startOfToday, endOfToday := TodayRange()
+	startOfToday, endOfToday := TodayRange(s.userLocation(userID))
 
 	organizations, err := s.activityRepo.FindOrganizationsWithActivitiesInTimeRange(userID, startOfToday, endOfToday)
 	if err != nil {
@@ -531,7 +1565,7 @@ func (s *TodoService) GetTodayOrganizationTodos(userID uint) ([]models.Organizat
 
 // GetTodayTodos 获取今日组织待办
 func (s *TodoService) GetTodayOrgTodos(userID uint) ([]models.Activity, error) {
-	startOfToday, endOfToday := TodayRange()
+	startOfToday, endOfToday := TodayRange(s.userLocation(userID))
 
 	activities, err := s.activityRepo.FindUserActivitiesOverlappingRange(userID, startOfToday, endOfToday)
 	if err != nil {
@@ -542,8 +1576,8 @@ func (s *TodoService) GetTodayOrgTodos(userID uint) ([]models.Activity, error) {
 
 // GetUpcomingStartingTodos 获取即将开始的组织待办（未来7天）
 func (s *TodoService) GetOrgUpcomingTodos(userID uint) ([]models.Activity, error) {
-	now := time.Now()
-	startOfTomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	startOfToday, _ := TodayRange(s.userLocation(userID))
+	startOfTomorrow := startOfToday.AddDate(0, 0, 1)
 	endOfNext7Days := startOfTomorrow.AddDate(0, 0, 7)
 
 	activities, err := s.activityRepo.FindUserActivitiesStartingInRange(userID, startOfTomorrow, endOfNext7Days)
@@ -555,7 +1589,7 @@ func (s *TodoService) GetOrgUpcomingTodos(userID uint) ([]models.Activity, error
 
 // GetUpcomingEndingTodos 获取即将结束的组织待办（未来7天）
 func (s *TodoService) GetOrgUpcomingEndingTodos(userID uint) ([]models.Activity, error) {
-	startOfToday, _ := TodayRange()
+	startOfToday, _ := TodayRange(s.userLocation(userID))
 	endOfNext7Days := startOfToday.AddDate(0, 0, 8).Add(-time.Nanosecond)
 
 	activities, err := s.activityRepo.FindUserActivitiesEndingInRange(userID, startOfToday, endOfNext7Days)
@@ -572,7 +1606,7 @@ func (s *TodoService) GetOrgTodosStartingOnDate(userID uint, dateStr string) ([]
 		return nil, fmt.Errorf("日期格式错误: %v", err)
 	}
 
-	startOfDay, endOfDay := DayRange(date)
+	startOfDay, endOfDay := DayRange(date, s.userLocation(userID))
 	activities, err := s.activityRepo.FindUserActivitiesStartingInRange(userID, startOfDay, endOfDay)
 	if err != nil {
 		return nil, fmt.Errorf("查询指定日期开始待办失败: %v", err)
@@ -587,7 +1621,7 @@ func (s *TodoService) GetTodosCompletedOnDate(userID uint, dateStr string) ([]mo
 		return nil, fmt.Errorf("日期格式错误: %v", err)
 	}
 
-	startOfDay, endOfDay := DayRange(date)
+	startOfDay, endOfDay := DayRange(date, s.userLocation(userID))
 	activities, err := s.activityRepo.FindUserActivitiesCompletedInRange(userID, startOfDay, endOfDay)
 	if err != nil {
 		return nil, fmt.Errorf("查询指定日期完成待办失败: %v", err)
@@ -603,8 +1637,8 @@ func (s *TodoService) GetOrgTodosExpiringOnDate(userID uint, dateStr string) ([]
 		return nil, fmt.Errorf("日期格式错误: %v", err)
 	}
 
-	// 获取指定日期的开始和结束时间
-	startOfDay, endOfDay := DayRange(date)
+	// 获取指定日期在用户时区的开始和结束时间
+	startOfDay, endOfDay := DayRange(date, s.userLocation(userID))
 
 	// 调用数据访问层查询过期待办
 	activities, err := s.activityRepo.FindUserPendingActivitiesEndingOnDate(userID, startOfDay, endOfDay)
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxSnoozeDays 单次推迟的最长天数
+const maxSnoozeDays = 365
+
+// SnoozeTodoRequest 推迟待办请求，duration和date二选一
+type SnoozeTodoRequest struct {
+	Duration string `json:"duration,omitempty" example:"1d"`     // 推迟时长，如 30m、2h、1d，按天推迟时保持本地钟点不变
+	Date     string `json:"date,omitempty" example:"2024-01-20"` // 推迟到指定日期（YYYY-MM-DD），保持原来的本地钟点
+}
+
+// SnoozeTodo 推迟待开始或进行中的待办，开始和结束时间整体后移，持续时长不变
+// 首次推迟时记录原定时间；重复实例按原定时间续期，推迟不影响系列中的其他实例
+func (s *TodoService) SnoozeTodo(userID, todoID uint, req *SnoozeTodoRequest) (*models.Todo, error) {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.HasChildren {
+		return nil, fmt.Errorf("%w: 重复待办请推迟具体的实例", ErrInvalidTodoParams)
+	}
+	if todo.Status != TodoStatusPending && todo.Status != TodoStatusInProgress {
+		return nil, fmt.Errorf("%w: 只能推迟待开始或进行中的待办，当前状态为 %s", ErrInvalidTodoParams, todo.Status)
+	}
+
+	newStart, err := snoozedStartTime(todo, req)
+	if err != nil {
+		return nil, err
+	}
+	if !newStart.After(todo.StartTime) {
+		return nil, fmt.Errorf("%w: 推迟后的开始时间必须晚于当前开始时间", ErrInvalidTodoParams)
+	}
+	if newStart.Sub(todo.StartTime) > maxSnoozeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: 单次最多推迟%d天", ErrInvalidTodoParams, maxSnoozeDays)
+	}
+	newEnd := newStart.Add(todo.EndTime.Sub(todo.StartTime))
+
+	updates := map[string]any{
+		"start_time":   newStart,
+		"end_time":     newEnd,
+		"snooze_count": todo.SnoozeCount + 1,
+	}
+	if !todo.Rescheduled() {
+		updates["original_start_time"] = todo.StartTime
+		updates["original_end_time"] = todo.EndTime
+	}
+	if newEnd.After(time.Now()) {
+		updates["is_overdue"] = false
+	}
+	// 推迟后的弹性待办转为固定待办，自动排程不再移动它
+	if todo.Flexible {
+		updates["flexible"] = false
+		updates["unplaced"] = false
+	}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.updateTodoWithTx(tx, todo.ID, updates); err != nil {
+			return fmt.Errorf("推迟待办失败: %v", err)
+		}
+		recorder := &todoChangeRecorder{}
+		recorder.record(todo, updates)
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: todo.CreatorUserID,
+			ActorID: userID,
+			TodoID:  todo.ID,
+			Action:  models.TodoActionSnooze,
+		}, recorder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.replanAfterChange(todo.CreatorUserID)
+
+	updated, err := s.todoRepo.FindByID(todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.todoWithDetails(updated)
+}
+
+// snoozedStartTime 根据请求计算推迟后的开始时间，日期和天数按待办所属时区的本地时间计算
+func snoozedStartTime(todo *models.Todo, req *SnoozeTodoRequest) (time.Time, error) {
+	duration := strings.TrimSpace(req.Duration)
+	date := strings.TrimSpace(req.Date)
+	if (duration == "") == (date == "") {
+		return time.Time{}, fmt.Errorf("%w: duration和date必须且只能提供一个", ErrInvalidTodoParams)
+	}
+
+	start := todo.LocalStartTime()
+	if date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, start.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: 日期格式应为YYYY-MM-DD", ErrInvalidTodoParams)
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location()), nil
+	}
+
+	if days, ok := strings.CutSuffix(duration, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 || n > maxSnoozeDays {
+			return time.Time{}, fmt.Errorf("%w: 无效的推迟天数 '%s'", ErrInvalidTodoParams, duration)
+		}
+		return start.AddDate(0, 0, n), nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("%w: 无效的推迟时长 '%s'", ErrInvalidTodoParams, duration)
+	}
+	return start.Add(d), nil
+}
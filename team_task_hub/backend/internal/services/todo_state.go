@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrIllegalTransition 待办状态转换不合法
+var ErrIllegalTransition = errors.New("非法的状态转换")
+
+// 待办状态
+const (
+	TodoStatusPending    = "pending"
+	TodoStatusInProgress = "in_progress"
+	TodoStatusCompleted  = "completed"
+	TodoStatusCancelled  = "cancelled"
+)
+
+// todoTransitions 待办状态机：key为当前状态，value为允许转换到的状态
+//
+//	pending     → in_progress / completed / cancelled
+//	in_progress → pending（暂停） / completed / cancelled
+//	completed   → pending（重新打开）
+//	cancelled   → pending（重新打开）
+var todoTransitions = map[string][]string{
+	TodoStatusPending:    {TodoStatusInProgress, TodoStatusCompleted, TodoStatusCancelled},
+	TodoStatusInProgress: {TodoStatusPending, TodoStatusCompleted, TodoStatusCancelled},
+	TodoStatusCompleted:  {TodoStatusPending},
+	TodoStatusCancelled:  {TodoStatusPending},
+}
+
+// unsetTime 时间字段的“未设置”值，与数据库默认值 1900-01-01 保持一致
+var unsetTime = time.Date(1900, 1, 1, 0, 0, 0, 0, time.Local)
+
+// CanTransition 判断待办能否从from状态转换到to状态
+func CanTransition(from, to string) bool {
+	for _, allowed := range todoTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// todoTransitionUpdates 校验状态转换并返回需要一并更新的字段
+func todoTransitionUpdates(todo *models.Todo, to string, now time.Time) (map[string]any, error) {
+	if !CanTransition(todo.Status, to) {
+		return nil, fmt.Errorf("%w: 不能从 %s 变为 %s", ErrIllegalTransition, todo.Status, to)
+	}
+	// 抽象待办本身不执行，只能取消或恢复
+	if todo.HasChildren && (to == TodoStatusInProgress || to == TodoStatusCompleted) {
+		return nil, fmt.Errorf("%w: 重复待办的抽象待办不能开始或完成，请操作具体实例", ErrIllegalTransition)
+	}
+
+	updates := map[string]any{"status": to}
+	switch to {
+	case TodoStatusInProgress:
+		if todo.StartedAt.IsZero() || !todo.StartedAt.After(unsetTime) {
+			updates["started_at"] = now
+		}
+	case TodoStatusCompleted:
+		updates["completed_at"] = now
+	case TodoStatusPending:
+		// 暂停保留首次开始时间，重新打开已完成或已取消的待办时才清空
+		if todo.Status == TodoStatusCompleted || todo.Status == TodoStatusCancelled {
+			updates["started_at"] = unsetTime
+			updates["completed_at"] = unsetTime
+		}
+	}
+	return updates, nil
+}
+
+// TransitionTodo 按状态机转换待办状态
+func (s *TodoService) TransitionTodo(userID, todoID uint, to string) (*models.Todo, error) {
+	return s.PatchTodo(userID, todoID, &PatchTodoRequest{Status: &to})
+}
+
+// StartTodo 开始待办，记录开始执行的时间
+func (s *TodoService) StartTodo(userID, todoID uint) (*models.Todo, error) {
+	return s.TransitionTodo(userID, todoID, TodoStatusInProgress)
+}
+
+// transitionTodoByDetails 根据待办详情定位待办后按状态机转换状态
+func (s *TodoService) transitionTodoByDetails(userID uint, title, description string, startTime, endTime time.Time, to string) (*models.Todo, error) {
+	todo, err := s.todoRepo.FindTodoByDetails(userID, startTime, endTime, title, description)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+	return s.TransitionTodo(userID, todo.ID, to)
+}
+
+// patchAction 根据更新的字段判断变更集的操作类型，只涉及状态及其时间戳时视为状态转换
+func patchAction(updates map[string]any) string {
+	for field := range updates {
+		if field != "status" && field != "started_at" && field != "completed_at" {
+			return models.TodoActionUpdate
+		}
+	}
+	if _, ok := updates["status"]; ok {
+		return models.TodoActionStatus
+	}
+	return models.TodoActionUpdate
+}
+
+// mergeTransitionUpdates 将状态转换产生的字段合并到更新字段中
+func mergeTransitionUpdates(updates map[string]any, todo *models.Todo, to string) error {
+	transition, err := todoTransitionUpdates(todo, to, time.Now())
+	if err != nil {
+		return err
+	}
+	maps.Copy(updates, transition)
+	return nil
+}
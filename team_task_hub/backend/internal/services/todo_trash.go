@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"team_task_hub/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrTodoParentTrashed 实例所属的重复待办在回收站中
+var ErrTodoParentTrashed = errors.New("所属重复待办在回收站中，请先恢复重复待办")
+
+// purgeBatchSize 每批清理的回收站条目数
+const purgeBatchSize = 100
+
+// DeleteTodo 将待办移入回收站，抽象待办会连同其所有实例一起移入
+// 清单、标签和依赖关系保留到彻底删除时再清理，以便恢复
+func (s *TodoService) DeleteTodo(userID, todoID uint) error {
+	todo, err := s.GetTodoByID(userID, todoID)
+	if err != nil {
+		return err
+	}
+
+	return s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		group, err := s.todoRepo.FindTrashGroupWithTx(tx, todo.ID)
+		if err != nil {
+			return fmt.Errorf("查询待办失败: %v", err)
+		}
+		now := time.Now()
+		recorder := &todoChangeRecorder{}
+		ids := make([]uint, len(group))
+		for i := range group {
+			ids[i] = group[i].ID
+			recorder.record(&group[i], map[string]any{"deleted_at": now})
+		}
+		if _, err := s.todoRepo.MoveToTrashWithTx(tx, ids, now); err != nil {
+			return fmt.Errorf("删除待办失败: %v", err)
+		}
+
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: todo.CreatorUserID,
+			ActorID: userID,
+			TodoID:  todo.ID,
+			Action:  models.TodoActionDelete,
+		}, recorder)
+	})
+}
+
+// ListTrash 获取用户回收站中的待办，抽象待办的实例不单独列出
+func (s *TodoService) ListTrash(userID uint) ([]models.Todo, error) {
+	todos, err := s.todoRepo.FindTrash(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询回收站失败: %v", err)
+	}
+	if err := s.attachTodoDetails(todos); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// RestoreTodo 从回收站恢复待办，抽象待办会连同与它一起删除的实例一起恢复
+func (s *TodoService) RestoreTodo(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.getTrashedTodo(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.ParentID != 0 {
+		trashed, err := s.todoRepo.IsTrashed(todo.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("查询重复待办失败: %v", err)
+		}
+		if trashed {
+			return nil, ErrTodoParentTrashed
+		}
+	}
+
+	err = s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		group, err := s.todoRepo.FindRestoreGroupWithTx(tx, todo)
+		if err != nil {
+			return fmt.Errorf("查询待办失败: %v", err)
+		}
+		recorder := &todoChangeRecorder{}
+		ids := make([]uint, len(group))
+		for i := range group {
+			ids[i] = group[i].ID
+			recorder.record(&group[i], map[string]any{"deleted_at": nil})
+		}
+		if _, err := s.todoRepo.RestoreWithTx(tx, ids); err != nil {
+			return fmt.Errorf("恢复待办失败: %v", err)
+		}
+
+		return s.saveChangeSetWithTx(tx, &models.TodoChangeSet{
+			OwnerID: todo.CreatorUserID,
+			ActorID: userID,
+			TodoID:  todo.ID,
+			Action:  models.TodoActionRestore,
+		}, recorder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTodoDetail(userID, todoID)
+}
+
+// PurgeTodo 彻底删除回收站中的待办
+func (s *TodoService) PurgeTodo(userID, todoID uint) error {
+	todo, err := s.getTrashedTodo(userID, todoID)
+	if err != nil {
+		return err
+	}
+	return s.purgeTodo(todo)
+}
+
+// PurgeExpiredTrash 彻底删除在回收站中超过保留期的待办，供后台任务调用
+func (s *TodoService) PurgeExpiredTrash(retention time.Duration) (int, error) {
+	before := time.Now().Add(-retention)
+	purged := 0
+
+	for {
+		todos, err := s.todoRepo.FindExpiredTrash(before, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("查询过期回收站条目失败: %v", err)
+		}
+
+		failed := 0
+		for i := range todos {
+			if err := s.purgeTodo(&todos[i]); err != nil {
+				log.Printf("清理回收站待办 %d 失败: %v", todos[i].ID, err)
+				failed++
+				continue
+			}
+			purged++
+		}
+		// 有失败的条目时不再继续，避免反复查询到同一批数据
+		if len(todos) < purgeBatchSize || failed > 0 {
+			return purged, nil
+		}
+	}
+}
+
+// getTrashedTodo 获取回收站中属于用户的待办
+func (s *TodoService) getTrashedTodo(userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.FindTrashedByID(todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("查询待办失败: %v", err)
+	}
+	if todo.CreatorUserID != userID {
+		return nil, ErrTodoForbidden
+	}
+	return todo, nil
+}
+
+// purgeTodo 清理清单项、标签、依赖关系、看板排序、用时记录、提醒和变更历史后彻底删除待办及其实例
+func (s *TodoService) purgeTodo(todo *models.Todo) error {
+	return s.todoRepo.Transaction(func(tx *gorm.DB) error {
+		if err := s.checklistRepo.DeleteByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办清单失败: %v", err)
+		}
+		if err := s.tagRepo.DeleteTodoLinksWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办标签失败: %v", err)
+		}
+		if err := s.dependencyRepo.DeleteByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办依赖失败: %v", err)
+		}
+		if err := s.boardRepo.DeleteCardsByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办看板排序失败: %v", err)
+		}
+		if err := s.timeEntryRepo.DeleteByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办用时记录失败: %v", err)
+		}
+		if err := s.reminderRepo.DeleteByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办提醒失败: %v", err)
+		}
+		if err := s.historyRepo.DeleteByTodoWithTx(tx, todo.ID, todo.HasChildren); err != nil {
+			return fmt.Errorf("删除待办变更历史失败: %v", err)
+		}
+		if _, err := s.todoRepo.PurgeWithTx(tx, todo.ID); err != nil {
+			return fmt.Errorf("删除待办失败: %v", err)
+		}
+		return nil
+	})
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,33 +26,27 @@ func checkPassword(password, hashedPassword string) bool {
 	return err == nil
 }
 
-// 创建今天的时间范围
-func TodayRange() (start, end time.Time) {
-	now := time.Now()
-	start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	end = start.Add(24 * time.Hour)
-	return start, end
+// 创建今天在指定时区的时间范围
+func TodayRange(loc *time.Location) (start, end time.Time) {
+	return DayRange(time.Now().In(loc), loc)
 }
 
-// 创建某一天的时间范围
-func DayRange(data time.Time) (start, end time.Time) {
-	now := time.Now()
-	var dayStart time.Time
-	var dayEnd time.Time
-	dayStart = time.Date(data.Year(), data.Month(), data.Day(), 0, 0, 0, 0, now.Location())
-	dayEnd = dayStart.Add(24 * time.Hour)
-	return dayStart, dayEnd
+// 创建某一天在指定时区的时间范围，只取data的年月日；夏令时切换当天的长度不是24小时
+func DayRange(data time.Time, loc *time.Location) (start, end time.Time) {
+	start = time.Date(data.Year(), data.Month(), data.Day(), 0, 0, 0, 0, loc)
+	end = start.AddDate(0, 0, 1)
+	return start, end
 }
 
-// 创建本周的时间范围（周一到周日）
-func ThisWeekRange() (start, end time.Time) {
-	now := time.Now()
+// 创建本周在指定时区的时间范围（周一到周日）
+func ThisWeekRange(loc *time.Location) (start, end time.Time) {
+	now := time.Now().In(loc)
 	weekday := int(now.Weekday())
 	if weekday == 0 {
 		weekday = 7
 	}
 
-	start = time.Date(now.Year(), now.Month(), now.Day()-weekday+1, 0, 0, 0, 0, now.Location())
+	start = time.Date(now.Year(), now.Month(), now.Day()-weekday+1, 0, 0, 0, 0, loc)
 	end = start.AddDate(0, 0, 7)
 	return start, end
 }
@@ -62,13 +59,56 @@ func NextDaysRange(days int) (start, end time.Time) {
 	return start, end
 }
 
-// 创建特定月份的时间范围
-func MonthRange(year int, month time.Month) (start, end time.Time) {
-	start = time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-	end = time.Date(year, month+1, 1, 0, 0, 0, 0, time.Local)
+// 创建特定月份在指定时区的时间范围
+func MonthRange(year int, month time.Month, loc *time.Location) (start, end time.Time) {
+	start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	end = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
 	return start, end
 }
 
+// maxDateRangeDays 统计类查询最多覆盖的天数
+const maxDateRangeDays = 366
+
+// parseDateRange 解析统计区间，from和to为用户时区下的日期（YYYY-MM-DD），
+// to所在的当天也包含在内；均为空时默认本周
+func parseDateRange(fromStr, toStr string, loc *time.Location) (time.Time, time.Time, error) {
+	if fromStr == "" && toStr == "" {
+		from, to := ThisWeekRange(loc)
+		return from, to, nil
+	}
+
+	var from, to time.Time
+	if fromStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", fromStr, loc)
+		if err != nil {
+			return from, to, errors.New("无效的from日期")
+		}
+		from, _ = DayRange(date, loc)
+	}
+	if toStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", toStr, loc)
+		if err != nil {
+			return from, to, errors.New("无效的to日期")
+		}
+		_, to = DayRange(date, loc)
+	}
+	// 只给出一端时统计该端起止的一周
+	if fromStr == "" {
+		from = to.AddDate(0, 0, -7)
+	}
+	if toStr == "" {
+		to = from.AddDate(0, 0, 7)
+	}
+
+	if !to.After(from) {
+		return from, to, errors.New("to不能早于from")
+	}
+	if to.After(from.AddDate(0, 0, maxDateRangeDays)) {
+		return from, to, fmt.Errorf("统计区间不能超过%d天", maxDateRangeDays)
+	}
+	return from, to, nil
+}
+
 // parseDateString 解析日期字符串（优化版：使用单一标准格式）
 func ParseDateString(dateStr string) (time.Time, error) {
 	// 定义系统规定的标准日期格式
@@ -85,3 +125,12 @@ func ParseDateString(dateStr string) (time.Time, error) {
 
 	return parsedTime, nil
 }
+
+// randomHexToken 生成指定字节数的随机十六进制字符串
+func randomHexToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}